@@ -0,0 +1,94 @@
+// Command devkeys generates the ES256 key pairs used to sign tokens in a local
+// environment. Each pair is written as a PEM private key, to sign test tokens
+// with, and a JWKS with its public key, which the service is configured to read.
+// Existing pairs are kept, so tokens signed earlier stay valid.
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const _p256CoordinateSize = 32
+
+// _keys are the key IDs by file name prefix, the user and the operator ones.
+var _keys = map[string]string{
+	"dev-token":          "dev",
+	"dev-operator-token": "dev-operator",
+}
+
+func main() {
+	dir := flag.String("dir", "configuration/keys", "directory the keys are written to")
+	flag.Parse()
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for name, kid := range _keys {
+		if err := generate(*dir, name, kid); err != nil {
+			fmt.Fprintf(os.Stderr, "generating %s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func generate(dir, name, kid string) error {
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	jwksFile := filepath.Join(dir, name+"-jwks.json")
+
+	if _, err := os.Stat(keyFile); err == nil {
+		return nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	point, err := key.PublicKey.Bytes()
+	if err != nil {
+		return err
+	}
+
+	// uncompressed point: 0x04 followed by both coordinates
+	jwks, err := json.MarshalIndent(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "EC",
+			"crv": "P-256",
+			"kid": kid,
+			"use": "sig",
+			"alg": "ES256",
+			"x":   base64.RawURLEncoding.EncodeToString(point[1 : 1+_p256CoordinateSize]),
+			"y":   base64.RawURLEncoding.EncodeToString(point[1+_p256CoordinateSize:]),
+		}},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(jwksFile, append(jwks, '\n'), 0o644); err != nil {
+		return err
+	}
+
+	return os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600)
+}
@@ -4,24 +4,44 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"log/slog"
+	"net/netip"
 	"time"
 
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/fxrates"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/http"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/jwtauth"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/metrics"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/pubsub/kafka"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/pubsub/rabbit"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/storage/postgresql"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/tracing"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/audit"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/balance"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/billers"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/expiry"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/fx"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/limits"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/outbox"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/payments"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/refunds"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/risk"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/transfers"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/wallets"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/config"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/logger"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/signals"
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/gorilla/mux"
 )
 
 const (
 	_configPath = "./configuration"
+	_localEnv   = "local"
 )
 
 func main() {
@@ -46,19 +66,50 @@ func run(logger *slog.Logger, cfg *config.Config) {
 		panic(err)
 	}
 
-	srvCfg, err := wire(ctx, logger, cfg)
+	tracerProvider, err := newTracerProvider(ctx, cfg)
+	if err != nil {
+		logger.Error("failed to start tracing", "error", err)
+		panic(err)
+	}
+
+	srvCfg, relay, sweeper, m, pub, err := wire(ctx, logger, cfg)
 	if err != nil {
 		logger.Error("failed to wire services", "error", err)
 		panic(err)
 	}
 
+	go relay.Start(ctx)
+	go sweeper.Start(ctx)
+	m.ListenAndServe()
+
 	srv := http.NewServer(srvCfg, logger)
 	httpSrv, healthy := srv.ListenAndServe(ctx)
 
 	// graceful shutdown
 	stopCh := signals.SetupSignalHandler()
 	sd, _ := signals.NewShutdown(3*time.Second, logger)
-	sd.Graceful(stopCh, httpSrv, healthy)
+	sd.WithTracerProvider(tracerProvider)
+	// the publisher is closed after the relay, which publishes through it and
+	// waits for its batch in flight on close
+	sd.Graceful(stopCh, httpSrv, healthy, srvCfg.Subscriber, relay, sweeper, m, pub)
+}
+
+// newTracerProvider starts exporting traces when tracing is enabled. When it
+// is not, spans are not recorded and no provider is returned.
+func newTracerProvider(ctx context.Context, cfg *config.Config) (signals.TracerProvider, error) {
+	if cfg.TracingConfig == nil || !cfg.TracingConfig.Enabled {
+		return nil, nil
+	}
+
+	return tracing.NewTracerProvider(ctx, tracing.Config{
+		ServiceName:    cfg.AppID,
+		ServiceVersion: cfg.Version,
+		Environment:    cfg.Env,
+		Exporter:       cfg.TracingConfig.Exporter,
+		OTLPEndpoint:   cfg.TracingConfig.OTLPEndpoint,
+		Insecure:       cfg.TracingConfig.Insecure,
+		SampleRatio:    cfg.TracingConfig.SampleRatio,
+	})
 }
 
 func migration(ctx context.Context, logger *slog.Logger, cfg *config.Config) error {
@@ -81,45 +132,339 @@ func migration(ctx context.Context, logger *slog.Logger, cfg *config.Config) err
 	return nil
 }
 
-func wire(ctx context.Context, logger *slog.Logger, cfg *config.Config) (*http.ServerConfig, error) {
+func wire(ctx context.Context, logger *slog.Logger, cfg *config.Config) (*http.ServerConfig, *outbox.Relay, *expiry.Sweeper, *metrics.Metrics, *rabbit.Pub, error) {
 	var (
-		balanceServiceConfig  balance.ServiceConfig
-		paymentsServiceConfig payments.ServiceConfig
-		pubConfig             rabbit.Config
-		srvCfg                http.ServerConfig
+		balanceServiceConfig   balance.ServiceConfig
+		paymentsServiceConfig  payments.ServiceConfig
+		refundsServiceConfig   refunds.ServiceConfig
+		walletsServiceConfig   wallets.ServiceConfig
+		transfersServiceConfig transfers.ServiceConfig
+		fxServiceConfig        fx.ServiceConfig
+		ratesConfig            fxrates.Config
+		limitsServiceConfig    limits.ServiceConfig
+		rulesConfig            risk.RulesConfig
+		billersServiceConfig   billers.ServiceConfig
+		auditServiceConfig     audit.ServiceConfig
+		metricsConfig          metrics.Config
+		pubConfig              rabbit.Config
+		subConfig              kafka.Config
+		relayConfig            outbox.RelayConfig
+		sweeperConfig          expiry.SweeperConfig
+		srvCfg                 http.ServerConfig
 	)
 	db, err := postgresql.NewDatabase(ctx, cfg.StorageConfig.Dsn)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, nil, nil, err
 	}
 
 	balanceRepo := postgresql.NewPgBalanceRepository(db.DB)
 	paymentRepo := postgresql.NewPgPaymentsRepository(db.DB)
+	outboxRepo := postgresql.NewPgOutboxRepository(db.DB)
+	ledgerRepo := postgresql.NewPgLedgerRepository(db.DB)
+	refundRepo := postgresql.NewPgRefundRepository(db.DB)
+	topUpRepo := postgresql.NewPgTopUpRepository(db.DB)
+	transferRepo := postgresql.NewPgTransferRepository(db.DB)
+	limitRepo := postgresql.NewPgLimitRepository(db.DB)
+	billerRepo := postgresql.NewPgBillerRepository(db.DB)
+	auditRepo := postgresql.NewPgAuditRepository(db.DB)
 
-	pubConfig.RoutingKey = cfg.PubConfig.RoutingKey
+	// metrics are always recorded, they are only served when enabled
+	metricsConfig.Logger = logger
+	metricsConfig.Pool = db.DB
+	if cfg.MetricsConfig != nil && cfg.MetricsConfig.Prometheus != nil && cfg.MetricsConfig.Prometheus.Enabled {
+		metricsConfig.Port = cfg.MetricsConfig.Prometheus.Port
+		metricsConfig.Path = cfg.MetricsConfig.Prometheus.Path
+	}
+	m := metrics.NewMetrics(metricsConfig)
+
+	pubConfig.RoutingKeys = cfg.PubConfig.RoutingKeys
 	pubConfig.Exchange = cfg.PubConfig.Exchange
 	pubConfig.RabbitURL = cfg.PubConfig.RabbitURL
 	pubConfig.Logger = logger
 
 	pub, errRabbitPub := rabbit.NewRabbitPub(pubConfig)
 	if errRabbitPub != nil {
-		return nil, errRabbitPub
+		return nil, nil, nil, nil, nil, errRabbitPub
+	}
+
+	relayConfig.Logger = logger
+	relayConfig.DB = db
+	relayConfig.OutboxRepository = outboxRepo
+	relayConfig.Publisher = m.Publisher(pub)
+	if cfg.OutboxConfig != nil {
+		relayConfig.PollInterval = cfg.OutboxConfig.PollInterval
+		relayConfig.BatchSize = cfg.OutboxConfig.BatchSize
+		relayConfig.BaseBackoff = cfg.OutboxConfig.BaseBackoff
+		relayConfig.MaxBackoff = cfg.OutboxConfig.MaxBackoff
+		relayConfig.ClaimLease = cfg.OutboxConfig.ClaimLease
 	}
+	relay := outbox.NewRelay(relayConfig)
 
+	balanceServiceConfig.DB = db
 	balanceServiceConfig.BalanceRepository = balanceRepo
+	balanceServiceConfig.LedgerRepository = ledgerRepo
 	balanceServiceConfig.Logger = logger
 	balanceSvc := balance.NewBalanceService(&balanceServiceConfig)
 
+	if cfg.FXConfig != nil {
+		ratesConfig.Rates = cfg.FXConfig.Rates
+		ratesConfig.File = cfg.FXConfig.RatesFile
+		fxServiceConfig.SpreadBps = cfg.FXConfig.SpreadBps
+		fxServiceConfig.Rounding = domain.RoundingMode(cfg.FXConfig.Rounding)
+	}
+	if !fxServiceConfig.Rounding.OrDefault().Valid() {
+		return nil, nil, nil, nil, nil, fmt.Errorf("invalid fx rounding mode %q", fxServiceConfig.Rounding)
+	}
+
+	rateProvider, errRates := fxrates.NewStaticRateProvider(ratesConfig)
+	if errRates != nil {
+		return nil, nil, nil, nil, nil, errRates
+	}
+
+	fxServiceConfig.Logger = logger
+	fxServiceConfig.RateProvider = rateProvider
+	fxSvc := fx.NewFXService(fxServiceConfig)
+
+	if cfg.LimitsConfig != nil {
+		tiers, errTiers := limitTiers(cfg.LimitsConfig)
+		if errTiers != nil {
+			return nil, nil, nil, nil, nil, errTiers
+		}
+		limitsServiceConfig.Tiers = tiers
+		limitsServiceConfig.DefaultTier = cfg.LimitsConfig.DefaultTier
+	}
+	limitsServiceConfig.Logger = logger
+	limitsServiceConfig.LimitRepository = limitRepo
+	limitsSvc := limits.NewLimitService(limitsServiceConfig)
+
+	if cfg.RiskConfig != nil {
+		largeAmounts, errAmounts := riskLargeAmounts(cfg.RiskConfig)
+		if errAmounts != nil {
+			return nil, nil, nil, nil, nil, errAmounts
+		}
+		rulesConfig.LargeAmounts = largeAmounts
+		rulesConfig.BurstWindow = cfg.RiskConfig.BurstWindow
+		rulesConfig.ReviewBurst = cfg.RiskConfig.ReviewBurst
+		rulesConfig.DenyBurst = cfg.RiskConfig.DenyBurst
+	}
+	rulesConfig.Logger = logger
+	riskEvaluator := risk.NewRulesEvaluator(rulesConfig)
+
+	billersServiceConfig.Logger = logger
+	billersServiceConfig.BillerRepository = billerRepo
+	billersSvc := billers.NewBillerService(billersServiceConfig)
+
+	auditServiceConfig.Logger = logger
+	auditServiceConfig.DB = db
+	auditServiceConfig.AuditRepository = auditRepo
+	auditSvc := audit.NewAuditService(auditServiceConfig)
+
 	paymentsServiceConfig.PaymentRepository = paymentRepo
 	paymentsServiceConfig.Logger = logger
 	paymentsServiceConfig.BalanceService = balanceSvc
 	paymentsServiceConfig.DB = db
-	paymentsServiceConfig.PublisherService = pub
+	paymentsServiceConfig.OutboxRepository = outboxRepo
+	paymentsServiceConfig.FXService = fxSvc
+	paymentsServiceConfig.LimitService = limitsSvc
+	paymentsServiceConfig.RiskEvaluator = riskEvaluator
+	paymentsServiceConfig.BillerService = billersSvc
+	paymentsServiceConfig.AuditService = auditSvc
+	paymentsServiceConfig.Metrics = m
 	paymentsSvc := payments.NewPaymentService(paymentsServiceConfig)
 
+	refundsServiceConfig.Logger = logger
+	refundsServiceConfig.DB = db
+	refundsServiceConfig.RefundRepository = refundRepo
+	refundsServiceConfig.PaymentRepository = paymentRepo
+	refundsServiceConfig.BalanceService = balanceSvc
+	refundsServiceConfig.OutboxRepository = outboxRepo
+	refundsServiceConfig.Metrics = m
+	refundsSvc := refunds.NewRefundService(refundsServiceConfig)
+
+	if cfg.TopUpConfig != nil {
+		maxTopUps, errMaxTopUps := topUpMaxAmounts(cfg.TopUpConfig)
+		if errMaxTopUps != nil {
+			return nil, nil, nil, nil, nil, errMaxTopUps
+		}
+		walletsServiceConfig.MaxTopUpAmounts = maxTopUps
+	}
+	walletsServiceConfig.Logger = logger
+	walletsServiceConfig.DB = db
+	walletsServiceConfig.TopUpRepository = topUpRepo
+	walletsServiceConfig.BalanceRepository = balanceRepo
+	walletsServiceConfig.BalanceService = balanceSvc
+	walletsServiceConfig.OutboxRepository = outboxRepo
+	walletsServiceConfig.AuditService = auditSvc
+	walletsSvc := wallets.NewWalletService(walletsServiceConfig)
+
+	transfersServiceConfig.Logger = logger
+	transfersServiceConfig.DB = db
+	transfersServiceConfig.TransferRepository = transferRepo
+	transfersServiceConfig.BalanceService = balanceSvc
+	transfersServiceConfig.OutboxRepository = outboxRepo
+	transfersSvc := transfers.NewTransferService(transfersServiceConfig)
+
+	sweeperConfig.Logger = logger
+	sweeperConfig.DB = db
+	sweeperConfig.PaymentRepository = paymentRepo
+	sweeperConfig.BalanceService = balanceSvc
+	sweeperConfig.OutboxRepository = outboxRepo
+	sweeperConfig.Metrics = m
+	if cfg.ExpiryConfig != nil {
+		sweeperConfig.TTL = cfg.ExpiryConfig.TTL
+		sweeperConfig.PollInterval = cfg.ExpiryConfig.PollInterval
+		sweeperConfig.BatchSize = cfg.ExpiryConfig.BatchSize
+	}
+	sweeper := expiry.NewSweeper(sweeperConfig)
+
+	subConfig.Brokers = cfg.SubConfig.Brokers
+	subConfig.Topic = cfg.SubConfig.Topic
+	subConfig.GroupID = cfg.SubConfig.GroupID
+	subConfig.Logger = logger
+	subConfig.PaymentService = paymentsSvc
+	sub := kafka.NewKafkaSub(subConfig)
+
+	if err := checkStaticKeys(cfg); err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
+
+	verifier, err := jwtauth.NewVerifier(ctx, verifierConfig(logger, cfg.AuthConfig))
+	if err != nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("loading token verification keys: %w", err)
+	}
+
+	operatorVerifier, err := jwtauth.NewVerifier(ctx, verifierConfig(logger, cfg.AdminAuthConfig))
+	if err != nil {
+		return nil, nil, nil, nil, nil, fmt.Errorf("loading operator token verification keys: %w", err)
+	}
+
 	srvCfg.Port = cfg.Port
+	srvCfg.TrustedProxies, err = trustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, nil, nil, nil, nil, err
+	}
 	srvCfg.PaymentService = paymentsSvc
 	srvCfg.BalanceService = balanceSvc
+	srvCfg.RefundService = refundsSvc
+	srvCfg.WalletService = walletsSvc
+	srvCfg.TransferService = transfersSvc
+	srvCfg.BillerService = billersSvc
+	srvCfg.TokenVerifier = verifier
+	srvCfg.OperatorVerifier = operatorVerifier
+	srvCfg.AuditService = auditSvc
+	srvCfg.Subscriber = sub
+	// tracing goes first so the span covers the time measured by the metrics
+	srvCfg.Instrumentation = []mux.MiddlewareFunc{tracing.Middleware, m.Middleware}
+
+	return &srvCfg, relay, sweeper, m, pub, nil
+}
+
+// checkStaticKeys refuses static verification keys outside a local
+// environment, since whoever holds their private keys can sign any token.
+func checkStaticKeys(cfg *config.Config) error {
+	if cfg.Env == _localEnv {
+		return nil
+	}
+
+	if cfg.AuthConfig != nil && len(cfg.AuthConfig.StaticKeys) > 0 {
+		return fmt.Errorf("auth static keys are only allowed with env %s", _localEnv)
+	}
+	if cfg.AdminAuthConfig != nil && len(cfg.AdminAuthConfig.StaticKeys) > 0 {
+		return fmt.Errorf("admin auth static keys are only allowed with env %s", _localEnv)
+	}
+
+	return nil
+}
+
+// verifierConfig builds the token verification settings of a credential,
+// either the user or the operator one.
+func verifierConfig(logger *slog.Logger, cfg *config.AuthConfig) jwtauth.Config {
+	verifierConfig := jwtauth.Config{Logger: logger}
+	if cfg != nil {
+		verifierConfig.Issuer = cfg.Issuer
+		verifierConfig.Audience = cfg.Audience
+		verifierConfig.JWKSFile = cfg.JWKSFile
+		verifierConfig.JWKSURL = cfg.JWKSURL
+		verifierConfig.StaticKeys = cfg.StaticKeys
+		verifierConfig.RefreshInterval = cfg.RefreshInterval
+		verifierConfig.Leeway = cfg.Leeway
+	}
+
+	return verifierConfig
+}
+
+// trustedProxies parses the configured proxies, either addresses or CIDR
+// prefixes.
+func trustedProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// limitTiers converts the configured tiers, keyed by currency code, to the
+// limits the service checks.
+func limitTiers(cfg *config.LimitsConfig) (map[string]map[domain.Currency]domain.Limits, error) {
+	tiers := make(map[string]map[domain.Currency]domain.Limits, len(cfg.Tiers))
+	for tier, currencies := range cfg.Tiers {
+		tiers[tier] = make(map[domain.Currency]domain.Limits, len(currencies))
+		for code, limit := range currencies {
+			currency, err := domain.ParseCurrency(code)
+			if err != nil {
+				return nil, fmt.Errorf("invalid currency %q in limit tier %s: %w", code, tier, err)
+			}
+
+			tiers[tier][currency] = domain.Limits{
+				PerTransaction: limit.PerTransaction,
+				DailyAmount:    limit.DailyAmount,
+				MonthlyAmount:  limit.MonthlyAmount,
+				HourlyCount:    limit.HourlyCount,
+			}
+		}
+	}
+
+	return tiers, nil
+}
+
+// riskLargeAmounts converts the configured large amounts, keyed by currency
+// code, to the thresholds the risk rules check.
+func riskLargeAmounts(cfg *config.RiskConfig) (map[domain.Currency]int64, error) {
+	amounts := make(map[domain.Currency]int64, len(cfg.LargeAmounts))
+	for code, amount := range cfg.LargeAmounts {
+		currency, err := domain.ParseCurrency(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency %q in risk large amounts: %w", code, err)
+		}
+
+		amounts[currency] = amount
+	}
+
+	return amounts, nil
+}
+
+// topUpMaxAmounts converts the configured top-up caps, keyed by currency code,
+// to the caps the wallet service checks.
+func topUpMaxAmounts(cfg *config.TopUpConfig) (map[domain.Currency]int64, error) {
+	amounts := make(map[domain.Currency]int64, len(cfg.MaxAmounts))
+	for code, amount := range cfg.MaxAmounts {
+		currency, err := domain.ParseCurrency(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency %q in top-up max amounts: %w", code, err)
+		}
+
+		amounts[currency] = amount
+	}
 
-	return &srvCfg, nil
+	return amounts, nil
 }
@@ -0,0 +1,88 @@
+package fxrates
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the rates keyed by currency pair, like "USD/ARS": "1250.50",
+// meaning 1250.50 ARS per USD. When File is set the rates are read from that
+// YAML file instead, using the same shape.
+type Config struct {
+	Rates map[string]string
+	File  string
+}
+
+type pair struct {
+	from domain.Currency
+	to   domain.Currency
+}
+
+// StaticRateProvider serves rates loaded once at startup. A missing pair is
+// answered with the inverse of the opposite one when it is known.
+type StaticRateProvider struct {
+	rates map[pair]*big.Rat
+}
+
+func NewStaticRateProvider(config Config) (*StaticRateProvider, error) {
+	raw := config.Rates
+	if config.File != "" {
+		file, err := os.ReadFile(config.File)
+		if err != nil {
+			return nil, err
+		}
+
+		raw = map[string]string{}
+		if err = yaml.Unmarshal(file, &raw); err != nil {
+			return nil, err
+		}
+	}
+
+	rates := make(map[pair]*big.Rat, len(raw))
+	for key, value := range raw {
+		from, to, ok := strings.Cut(key, "/")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid currency pair %q", key)
+		}
+
+		p := pair{}
+		var err error
+		if p.from, err = domain.ParseCurrency(from); err != nil {
+			return nil, fmt.Errorf("invalid currency pair %q: %w", key, err)
+		}
+		if p.to, err = domain.ParseCurrency(to); err != nil {
+			return nil, fmt.Errorf("invalid currency pair %q: %w", key, err)
+		}
+
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid rate %q for %s", value, key)
+		}
+
+		rates[p] = rate
+	}
+
+	return &StaticRateProvider{rates: rates}, nil
+}
+
+func (p *StaticRateProvider) Rate(_ context.Context, from, to domain.Currency) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	if rate, ok := p.rates[pair{from: from, to: to}]; ok {
+		return new(big.Rat).Set(rate), nil
+	}
+
+	if rate, ok := p.rates[pair{from: to, to: from}]; ok {
+		return new(big.Rat).Inv(rate), nil
+	}
+
+	return nil, domain.ErrFXRateUnavailable
+}
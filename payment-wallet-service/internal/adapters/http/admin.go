@@ -0,0 +1,172 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+// adminListPaymentsHandler lists the payments of any user with the filters of
+// the user listing. The lookup is audited before anything is returned.
+func (s *Server) adminListPaymentsHandler(w http.ResponseWriter, r *http.Request) {
+	operator, ok := operatorFromContext(r.Context())
+	if !ok {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := parsePaymentFilter(r.URL.Query())
+	if err != nil {
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.UserID = mux.Vars(r)["user_id"]
+
+	if err = s.auditService.Record(r.Context(), operator, domain.AuditLookupPayments, filter.UserID, r.URL.Query()); err != nil {
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	page, err := s.paymentService.List(r.Context(), filter)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidPaymentFilter) && !errors.Is(err, domain.ErrInvalidCursor) {
+			s.logger.ErrorContext(r.Context(), "cannot list payments", slog.Any("error", err))
+		}
+
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponse(w, r, page)
+}
+
+// adminGetBalanceHandler returns the balance of any user in a currency with
+// its latest ledger entries, flagging whether the balance matches the ledger.
+// The lookup is audited before anything is returned.
+func (s *Server) adminGetBalanceHandler(w http.ResponseWriter, r *http.Request) {
+	operator, ok := operatorFromContext(r.Context())
+	if !ok {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	currency, err := domain.ParseCurrency(r.URL.Query().Get("currency"))
+	if err != nil {
+		s.ErrorResponse(w, r, CodeInvalidRequest, "currency: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := parseEntries(r)
+	if err != nil {
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := mux.Vars(r)["user_id"]
+	err = s.auditService.Record(r.Context(), operator, domain.AuditLookupBalance, userID,
+		map[string]domain.Currency{"currency": currency})
+	if err != nil {
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	statement, err := s.balanceService.Statement(r.Context(), userID, currency, entries)
+	if err != nil {
+		if !errors.Is(err, domain.ErrWalletNotFound) && !errors.Is(err, domain.ErrCurrencyNotHeld) {
+			s.logger.ErrorContext(r.Context(), "cannot get statement", slog.Any("error", err))
+		}
+
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponse(w, r, statement)
+}
+
+// parseEntries reads how many ledger entries to return, from 1 to the maximum
+// page size.
+func parseEntries(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("entries")
+	if value == "" {
+		return domain.DefaultPageSize, nil
+	}
+
+	entries, err := strconv.Atoi(value)
+	if err != nil || entries < 1 || entries > domain.MaxPageSize {
+		return 0, errors.New("entries must be an integer from 1 to " + strconv.Itoa(domain.MaxPageSize))
+	}
+
+	return entries, nil
+}
+
+func (s *Server) adjustBalanceHandler(w http.ResponseWriter, r *http.Request) {
+	operator, ok := operatorFromContext(r.Context())
+	if !ok {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.BalanceAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot unmarshal request", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.UserID = mux.Vars(r)["user_id"]
+	req.Currency = req.Currency.OrDefault()
+
+	if err := req.Validate(); err != nil {
+		s.logger.ErrorContext(r.Context(), "validation error", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	adjustment, err := s.walletService.Adjust(r.Context(), operator, req)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot adjust balance",
+			slog.Any("error", err),
+			slog.String("user_id", req.UserID))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponseCode(w, r, adjustment, http.StatusCreated)
+}
+
+func (s *Server) forcePaymentStatusHandler(w http.ResponseWriter, r *http.Request) {
+	operator, ok := operatorFromContext(r.Context())
+	if !ok {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.ForcePaymentStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot unmarshal request", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.PaymentID = mux.Vars(r)["id"]
+
+	if err := req.Validate(); err != nil {
+		s.logger.ErrorContext(r.Context(), "validation error", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payment, err := s.paymentService.ForceStatus(r.Context(), operator, req)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot force payment status",
+			slog.Any("error", err),
+			slog.String("transaction_id", req.PaymentID))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponse(w, r, payment)
+}
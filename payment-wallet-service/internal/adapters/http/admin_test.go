@@ -0,0 +1,465 @@
+package http
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestServer_adminListPaymentsHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		operatorID           string
+		query                string
+		page                 *domain.PaymentPage
+		auditError           error
+		paymentServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		auditTimes           int
+		paymentServiceTimes  int
+	}{
+		{
+			name:       "Success - Payments of the user",
+			operatorID: "operator-1",
+			query:      "?status=APPROVED",
+			page: &domain.PaymentPage{Payments: []domain.Payment{
+				{ID: "tx-1", UserID: "user123", Status: domain.StatusApproved},
+			}},
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: "tx-1",
+			auditTimes:           1,
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Missing operator",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+		},
+		{
+			name:                 "Error - Invalid filter",
+			operatorID:           "operator-1",
+			query:                "?min_amount=abc",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+		},
+		{
+			name:                 "Error - Audit entry not recorded",
+			operatorID:           "operator-1",
+			auditError:           domain.ErrRecordAudit,
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedErrorMessage: "AUDIT_FAILED",
+			auditTimes:           1,
+		},
+		{
+			name:                 "Error - Payments unavailable",
+			operatorID:           "operator-1",
+			paymentServiceError:  domain.ErrListPayments,
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedErrorMessage: "PAYMENTS_UNAVAILABLE",
+			auditTimes:           1,
+			paymentServiceTimes:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockPaymentSvc := mocks.NewMockPaymentService(ctrl)
+			mockAuditSvc := mocks.NewMockAuditService(ctrl)
+
+			operator := domain.Operator{ID: tt.operatorID, Roles: []domain.Role{domain.RoleSupportReadOnly}}
+
+			mockAuditSvc.EXPECT().Record(gomock.Any(), operator, domain.AuditLookupPayments, "user123", gomock.Any()).
+				Return(tt.auditError).Times(tt.auditTimes)
+			mockPaymentSvc.EXPECT().List(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ any, filter domain.PaymentFilter) (*domain.PaymentPage, error) {
+					if filter.UserID != "user123" {
+						t.Errorf("Expected payments of user123, got %s", filter.UserID)
+					}
+					return tt.page, tt.paymentServiceError
+				}).Times(tt.paymentServiceTimes)
+
+			server := &Server{
+				logger:         slog.Default(),
+				paymentService: mockPaymentSvc,
+				auditService:   mockAuditSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/v1/users/user123/payments"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"user_id": "user123"})
+			if tt.operatorID != "" {
+				req = req.WithContext(withOperator(req.Context(), operator))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.adminListPaymentsHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.expectedErrorMessage) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+			}
+		})
+	}
+}
+
+func TestServer_adminGetBalanceHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		operatorID           string
+		query                string
+		entries              int
+		statement            *domain.Statement
+		auditError           error
+		balanceServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		auditTimes           int
+		balanceServiceTimes  int
+	}{
+		{
+			name:       "Success - Default entries",
+			operatorID: "operator-1",
+			query:      "?currency=ARS",
+			entries:    domain.DefaultPageSize,
+			statement: &domain.Statement{
+				UserID:     "user123",
+				Available:  1000,
+				Currency:   domain.CurrencyARS,
+				Reconciled: true,
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: `"reconciled": true`,
+			auditTimes:           1,
+			balanceServiceTimes:  1,
+		},
+		{
+			name:                 "Success - Requested entries",
+			operatorID:           "operator-1",
+			query:                "?currency=USD&entries=5",
+			entries:              5,
+			statement:            &domain.Statement{UserID: "user123", Currency: domain.CurrencyUSD},
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: "USD",
+			auditTimes:           1,
+			balanceServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Missing operator",
+			query:                "?currency=ARS",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+		},
+		{
+			name:                 "Error - Unsupported currency",
+			operatorID:           "operator-1",
+			query:                "?currency=XYZ",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "currency",
+		},
+		{
+			name:                 "Error - Invalid entries",
+			operatorID:           "operator-1",
+			query:                "?currency=ARS&entries=0",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "entries",
+		},
+		{
+			name:                 "Error - Audit entry not recorded",
+			operatorID:           "operator-1",
+			query:                "?currency=ARS",
+			auditError:           domain.ErrRecordAudit,
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedErrorMessage: "AUDIT_FAILED",
+			auditTimes:           1,
+		},
+		{
+			name:                 "Error - Wallet not found",
+			operatorID:           "operator-1",
+			query:                "?currency=ARS",
+			entries:              domain.DefaultPageSize,
+			balanceServiceError:  domain.ErrWalletNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: "WALLET_NOT_FOUND",
+			auditTimes:           1,
+			balanceServiceTimes:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockBalanceSvc := mocks.NewMockBalanceService(ctrl)
+			mockAuditSvc := mocks.NewMockAuditService(ctrl)
+
+			operator := domain.Operator{ID: tt.operatorID, Roles: []domain.Role{domain.RoleSupportReadOnly}}
+
+			mockAuditSvc.EXPECT().Record(gomock.Any(), operator, domain.AuditLookupBalance, "user123", gomock.Any()).
+				Return(tt.auditError).Times(tt.auditTimes)
+			mockBalanceSvc.EXPECT().Statement(gomock.Any(), "user123", gomock.Any(), tt.entries).
+				Return(tt.statement, tt.balanceServiceError).Times(tt.balanceServiceTimes)
+
+			server := &Server{
+				logger:         slog.Default(),
+				balanceService: mockBalanceSvc,
+				auditService:   mockAuditSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/v1/users/user123/balance"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"user_id": "user123"})
+			if tt.operatorID != "" {
+				req = req.WithContext(withOperator(req.Context(), operator))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.adminGetBalanceHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.expectedErrorMessage) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+			}
+		})
+	}
+}
+
+func TestServer_adjustBalanceHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		operatorID           string
+		body                 string
+		adjustment           *domain.BalanceAdjustment
+		walletServiceError   error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		walletServiceTimes   int
+	}{
+		{
+			name:       "Success - Balance credited",
+			operatorID: "operator-1",
+			body:       `{"amount": 2500, "reason": "chargeback won"}`,
+			adjustment: &domain.BalanceAdjustment{
+				ID:       "adjustment-1",
+				UserID:   "user123",
+				Amount:   2500,
+				Currency: domain.CurrencyARS,
+				Reason:   "chargeback won",
+			},
+			expectedStatusCode:   http.StatusCreated,
+			expectedErrorMessage: "adjustment-1",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Error - Missing operator",
+			body:                 `{"amount": 2500, "reason": "chargeback won"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+		},
+		{
+			name:                 "Error - Invalid JSON",
+			operatorID:           "operator-1",
+			body:                 `{"amount": `,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+		},
+		{
+			name:                 "Error - Missing reason",
+			operatorID:           "operator-1",
+			body:                 `{"amount": 2500}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "reason",
+		},
+		{
+			name:                 "Error - Zero amount",
+			operatorID:           "operator-1",
+			body:                 `{"amount": 0, "reason": "chargeback won"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "amount",
+		},
+		{
+			name:                 "Error - Insufficient funds",
+			operatorID:           "operator-1",
+			body:                 `{"amount": -2500, "reason": "duplicated top-up"}`,
+			walletServiceError:   domain.ErrInsufficientFunds,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "INSUFFICIENT_FUNDS",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Error - Audit entry not recorded",
+			operatorID:           "operator-1",
+			body:                 `{"amount": 2500, "reason": "chargeback won"}`,
+			walletServiceError:   domain.ErrRecordAudit,
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedErrorMessage: "AUDIT_FAILED",
+			walletServiceTimes:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWalletSvc := mocks.NewMockWalletService(ctrl)
+
+			operator := domain.Operator{ID: tt.operatorID, Roles: []domain.Role{domain.RoleFinance}}
+
+			mockWalletSvc.EXPECT().Adjust(gomock.Any(), operator, gomock.Any()).
+				DoAndReturn(func(_ any, _ domain.Operator, request domain.BalanceAdjustmentRequest) (*domain.BalanceAdjustment, error) {
+					if request.UserID != "user123" || request.Currency != domain.CurrencyARS {
+						t.Errorf("Expected an ARS adjustment of user123, got %+v", request)
+					}
+					return tt.adjustment, tt.walletServiceError
+				}).Times(tt.walletServiceTimes)
+
+			server := &Server{
+				logger:        slog.Default(),
+				walletService: mockWalletSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/v1/wallets/user123/adjustments", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"user_id": "user123"})
+			if tt.operatorID != "" {
+				req = req.WithContext(withOperator(req.Context(), operator))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.adjustBalanceHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.expectedErrorMessage) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+			}
+		})
+	}
+}
+
+func TestServer_forcePaymentStatusHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		operatorID           string
+		body                 string
+		payment              *domain.Payment
+		paymentServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		paymentServiceTimes  int
+	}{
+		{
+			name:                 "Success - Payment forced to rejected",
+			operatorID:           "operator-1",
+			body:                 `{"status": "REJECTED", "reason": "processor lost the payment"}`,
+			payment:              &domain.Payment{ID: "tx-1", Status: domain.StatusRejected},
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: `"status": "REJECTED"`,
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Missing operator",
+			body:                 `{"status": "REJECTED", "reason": "processor lost the payment"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+		},
+		{
+			name:                 "Error - Invalid JSON",
+			operatorID:           "operator-1",
+			body:                 `{"status": `,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+		},
+		{
+			name:                 "Error - Unknown status",
+			operatorID:           "operator-1",
+			body:                 `{"status": "LOST", "reason": "processor lost the payment"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "status",
+		},
+		{
+			name:                 "Error - Missing reason",
+			operatorID:           "operator-1",
+			body:                 `{"status": "APPROVED"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "reason",
+		},
+		{
+			name:                 "Error - Payment already settled",
+			operatorID:           "operator-1",
+			body:                 `{"status": "REJECTED", "reason": "processor lost the payment"}`,
+			paymentServiceError:  domain.ErrPaymentNotForceable,
+			expectedStatusCode:   http.StatusConflict,
+			expectedErrorMessage: "PAYMENT_NOT_FORCEABLE",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Payment not found",
+			operatorID:           "operator-1",
+			body:                 `{"status": "APPROVED", "reason": "processor confirmed by phone"}`,
+			paymentServiceError:  domain.ErrPaymentNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: "PAYMENT_NOT_FOUND",
+			paymentServiceTimes:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockPaymentSvc := mocks.NewMockPaymentService(ctrl)
+
+			operator := domain.Operator{ID: tt.operatorID, Roles: []domain.Role{domain.RoleOps}}
+
+			mockPaymentSvc.EXPECT().ForceStatus(gomock.Any(), operator, gomock.Any()).
+				DoAndReturn(func(_ any, _ domain.Operator, request domain.ForcePaymentStatusRequest) (*domain.Payment, error) {
+					if request.PaymentID != "tx-1" {
+						t.Errorf("Expected payment tx-1, got %s", request.PaymentID)
+					}
+					return tt.payment, tt.paymentServiceError
+				}).Times(tt.paymentServiceTimes)
+
+			server := &Server{
+				logger:         slog.Default(),
+				paymentService: mockPaymentSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/v1/payments/tx-1/status", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "tx-1"})
+			if tt.operatorID != "" {
+				req = req.WithContext(withOperator(req.Context(), operator))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.forcePaymentStatusHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.expectedErrorMessage) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+)
+
+type contextKey int
+
+const (
+	_userIDKey contextKey = iota
+	_operatorKey
+)
+
+const (
+	_authorizationHeader   = "Authorization"
+	_wwwAuthenticateHeader = "WWW-Authenticate"
+	_bearerPrefix          = "Bearer "
+)
+
+// authenticate rejects requests without a valid bearer token and puts the
+// subject of the token in the context as the authenticated user.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set(_wwwAuthenticateHeader, "Bearer")
+			s.ErrorResponse(w, r, CodeUnauthorized, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		userID, err := s.tokenVerifier.Verify(r.Context(), token)
+		if err != nil {
+			s.logger.WarnContext(r.Context(), "rejected bearer token", slog.Any("error", err))
+			w.Header().Set(_wwwAuthenticateHeader, `Bearer error="invalid_token"`)
+			s.ErrorResponse(w, r, CodeUnauthorized, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withUserID(r.Context(), userID)))
+	})
+}
+
+// authenticateOperator rejects admin requests without a valid operator token
+// and puts the operator, with its roles, in the context. Operator tokens are a
+// credential of their own, so user tokens are not accepted here.
+func (s *Server) authenticateOperator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set(_wwwAuthenticateHeader, "Bearer")
+			s.ErrorResponse(w, r, CodeUnauthorized, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		operator, err := s.operatorVerifier.VerifyOperator(r.Context(), token)
+		if err != nil {
+			s.logger.WarnContext(r.Context(), "rejected operator token", slog.Any("error", err))
+			w.Header().Set(_wwwAuthenticateHeader, `Bearer error="invalid_token"`)
+			s.ErrorResponse(w, r, CodeUnauthorized, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withOperator(r.Context(), *operator)))
+	})
+}
+
+// requireRole serves the handler only to operators holding one of the roles.
+func (s *Server) requireRole(handler http.HandlerFunc, roles ...domain.Role) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operator, ok := operatorFromContext(r.Context())
+		if !ok {
+			s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if !operator.HasAnyRole(roles...) {
+			s.logger.WarnContext(r.Context(), "operator lacks the role for the admin action",
+				slog.String("operator_id", operator.ID),
+				slog.String("path", r.URL.Path))
+			s.ErrorResponse(w, r, CodeForbidden, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		handler(w, r)
+	})
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get(_authorizationHeader)
+	if len(header) < len(_bearerPrefix) || !strings.EqualFold(header[:len(_bearerPrefix)], _bearerPrefix) {
+		return "", false
+	}
+
+	token := strings.TrimSpace(header[len(_bearerPrefix):])
+	return token, token != ""
+}
+
+// withUserID sets the authenticated user, also tagging it for the logs.
+func withUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(correlation.WithUserID(ctx, userID), _userIDKey, userID)
+}
+
+// userIDFromContext returns the authenticated user, or an empty string when
+// the request did not go through authenticate.
+func userIDFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(_userIDKey).(string)
+	return userID
+}
+
+func withOperator(ctx context.Context, operator domain.Operator) context.Context {
+	return context.WithValue(ctx, _operatorKey, operator)
+}
+
+// operatorFromContext returns the authenticated operator of an admin request.
+func operatorFromContext(ctx context.Context) (domain.Operator, bool) {
+	operator, ok := ctx.Value(_operatorKey).(domain.Operator)
+	return operator, ok
+}
@@ -0,0 +1,370 @@
+package http
+
+import (
+	"errors"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer_authenticate(t *testing.T) {
+	tests := []struct {
+		name                 string
+		authorization        string
+		verifiedUserID       string
+		verifierError        error
+		verifierTimes        int
+		expectedStatusCode   int
+		expectedErrorMessage string
+		expectedUserID       string
+	}{
+		{
+			name:               "Success - Valid token",
+			authorization:      "Bearer valid-token",
+			verifiedUserID:     "user123",
+			verifierTimes:      1,
+			expectedStatusCode: http.StatusOK,
+			expectedUserID:     "user123",
+		},
+		{
+			name:               "Success - Case insensitive scheme",
+			authorization:      "bearer valid-token",
+			verifiedUserID:     "user123",
+			verifierTimes:      1,
+			expectedStatusCode: http.StatusOK,
+			expectedUserID:     "user123",
+		},
+		{
+			name:                 "Error - Missing token",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "missing bearer token",
+		},
+		{
+			name:                 "Error - Other scheme",
+			authorization:        "Basic dXNlcjpwYXNz",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "missing bearer token",
+		},
+		{
+			name:                 "Error - Empty token",
+			authorization:        "Bearer  ",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "missing bearer token",
+		},
+		{
+			name:                 "Error - Invalid token",
+			authorization:        "Bearer expired-token",
+			verifierError:        errors.New("token is expired"),
+			verifierTimes:        1,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "invalid bearer token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVerifier := mocks.NewMockTokenVerifier(ctrl)
+			mockVerifier.EXPECT().Verify(gomock.Any(), strings.TrimPrefix(strings.TrimPrefix(tt.authorization, "Bearer "), "bearer ")).
+				Return(tt.verifiedUserID, tt.verifierError).Times(tt.verifierTimes)
+
+			server := &Server{
+				logger:        slog.Default(),
+				tokenVerifier: mockVerifier,
+			}
+
+			var userID string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID = userIDFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/balance", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+
+			server.authenticate(next).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if userID != tt.expectedUserID {
+				t.Errorf("Expected user ID '%s', got '%s'", tt.expectedUserID, userID)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+				if !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), "Bearer") {
+					t.Errorf("Expected a Bearer challenge, got '%s'", w.Header().Get("WWW-Authenticate"))
+				}
+			}
+		})
+	}
+}
+
+func TestServer_authenticateOperator(t *testing.T) {
+	tests := []struct {
+		name                 string
+		authorization        string
+		operator             *domain.Operator
+		verifierError        error
+		verifierTimes        int
+		expectedStatusCode   int
+		expectedErrorMessage string
+	}{
+		{
+			name:               "Success - Valid operator token",
+			authorization:      "Bearer operator-token",
+			operator:           &domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleOps}},
+			verifierTimes:      1,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:                 "Error - Missing token",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "missing bearer token",
+		},
+		{
+			name:                 "Error - User token",
+			authorization:        "Bearer user-token",
+			verifierError:        errors.New("token has invalid audience"),
+			verifierTimes:        1,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "invalid bearer token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockVerifier := mocks.NewMockOperatorVerifier(ctrl)
+			mockVerifier.EXPECT().VerifyOperator(gomock.Any(), strings.TrimPrefix(tt.authorization, "Bearer ")).
+				Return(tt.operator, tt.verifierError).Times(tt.verifierTimes)
+
+			server := &Server{
+				logger:           slog.Default(),
+				operatorVerifier: mockVerifier,
+			}
+
+			var (
+				operator domain.Operator
+				found    bool
+			)
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				operator, found = operatorFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/v1/users/user123/balance", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			w := httptest.NewRecorder()
+
+			server.authenticateOperator(next).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.operator != nil && (!found || operator.ID != tt.operator.ID) {
+				t.Errorf("Expected operator %+v in the context, got %+v", tt.operator, operator)
+			}
+
+			if tt.expectedErrorMessage != "" && !strings.Contains(w.Body.String(), tt.expectedErrorMessage) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_requireRole(t *testing.T) {
+	tests := []struct {
+		name               string
+		operator           *domain.Operator
+		roles              []domain.Role
+		expectedStatusCode int
+	}{
+		{
+			name:               "Success - Operator holds the role",
+			operator:           &domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleFinance}},
+			roles:              []domain.Role{domain.RoleFinance},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Success - Operator holds one of the roles",
+			operator:           &domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleSupportReadOnly}},
+			roles:              []domain.Role{domain.RoleSupportReadOnly, domain.RoleOps, domain.RoleFinance},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Error - Operator lacks the role",
+			operator:           &domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleSupportReadOnly}},
+			roles:              []domain.Role{domain.RoleFinance},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Error - Operator without roles",
+			operator:           &domain.Operator{ID: "operator-1"},
+			roles:              []domain.Role{domain.RoleOps},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "Error - Missing operator",
+			roles:              []domain.Role{domain.RoleOps},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{logger: slog.Default()}
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/v1/wallets/user123/adjustments", nil)
+			if tt.operator != nil {
+				req = req.WithContext(withOperator(req.Context(), *tt.operator))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.requireRole(handler, tt.roles...).ServeHTTP(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+		})
+	}
+}
+
+func TestServer_registerHandlers_authentication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVerifier := mocks.NewMockTokenVerifier(ctrl)
+	mockOperatorVerifier := mocks.NewMockOperatorVerifier(ctrl)
+
+	server := NewServer(&ServerConfig{TokenVerifier: mockVerifier, OperatorVerifier: mockOperatorVerifier}, slog.Default())
+	server.registerHandlers()
+
+	t.Run("health does not require a token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+
+		if w.Code == http.StatusUnauthorized {
+			t.Errorf("Expected health to be public, got status code %d", w.Code)
+		}
+	})
+
+	t.Run("API routes require a token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/balance", nil))
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+
+	t.Run("admin routes require an operator token", func(t *testing.T) {
+		mockVerifier.EXPECT().Verify(gomock.Any(), gomock.Any()).Times(0)
+		mockOperatorVerifier.EXPECT().VerifyOperator(gomock.Any(), "user-token").
+			Return(nil, errors.New("token has invalid audience")).Times(1)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/v1/wallets/user123/freeze", nil)
+		req.Header.Set("Authorization", "Bearer user-token")
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+
+	t.Run("admin routes check the role of the operator", func(t *testing.T) {
+		mockOperatorVerifier.EXPECT().VerifyOperator(gomock.Any(), "operator-token").
+			Return(&domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleSupportReadOnly}}, nil).Times(1)
+
+		req := httptest.NewRequest(http.MethodPost, "/admin/v1/wallets/user123/freeze", nil)
+		req.Header.Set("Authorization", "Bearer operator-token")
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, got %d", http.StatusForbidden, w.Code)
+		}
+	})
+
+	t.Run("top-ups are refused to operators without the ops or finance role", func(t *testing.T) {
+		mockOperatorVerifier.EXPECT().VerifyOperator(gomock.Any(), "operator-token").
+			Return(&domain.Operator{ID: "operator-1"}, nil).Times(1)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/wallets/user123/topups",
+			strings.NewReader(`{"amount": 5000, "idempotency_key": "topup-key"}`))
+		req.Header.Set("Authorization", "Bearer operator-token")
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected status code %d, got %d", http.StatusForbidden, w.Code)
+		}
+	})
+
+	t.Run("top-ups are refused to user tokens", func(t *testing.T) {
+		mockVerifier.EXPECT().Verify(gomock.Any(), gomock.Any()).Times(0)
+		mockOperatorVerifier.EXPECT().VerifyOperator(gomock.Any(), "user-token").
+			Return(nil, errors.New("token has invalid audience")).Times(1)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/wallets/user123/topups",
+			strings.NewReader(`{"amount": 5000, "idempotency_key": "topup-key"}`))
+		req.Header.Set("Authorization", "Bearer user-token")
+
+		w := httptest.NewRecorder()
+		server.router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, w.Code)
+		}
+	})
+}
+
+func TestServer_registerHandlers_instrumentation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockVerifier := mocks.NewMockTokenVerifier(ctrl)
+
+	var routes []string
+	instrumentation := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			template, _ := mux.CurrentRoute(r).GetPathTemplate()
+			routes = append(routes, template)
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	server := NewServer(&ServerConfig{TokenVerifier: mockVerifier, Instrumentation: []mux.MiddlewareFunc{instrumentation}}, slog.Default())
+	server.registerHandlers()
+
+	server.router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	server.router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/payments/payment123", nil))
+
+	if strings.Join(routes, ",") != "/v1/health,/v1/payments/{id}" {
+		t.Errorf("Expected every route to be instrumented by its template, got %v", routes)
+	}
+}
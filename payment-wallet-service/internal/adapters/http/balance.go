@@ -0,0 +1,35 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+func (s *Server) getBalanceHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	currency, err := domain.ParseCurrency(r.URL.Query().Get("currency"))
+	if err != nil {
+		s.ErrorResponse(w, r, CodeInvalidRequest, "currency: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	balance, err := s.balanceService.Get(r.Context(), userID, currency)
+	if err != nil {
+		if !errors.Is(err, domain.ErrWalletNotFound) && !errors.Is(err, domain.ErrCurrencyNotHeld) {
+			s.logger.ErrorContext(r.Context(), "cannot get balance", slog.Any("error", err))
+		}
+
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponse(w, r, balance)
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer_getBalanceHandler(t *testing.T) {
+	updatedAt := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		userID               string
+		query                string
+		currency             domain.Currency
+		balance              *domain.Balance
+		balanceServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		balanceServiceTimes  int
+	}{
+		{
+			name:   "Success - Wallet exists",
+			userID: "user123",
+			balance: &domain.Balance{
+				UserID:    "user123",
+				Available: 9000,
+				Reserved:  1000,
+				UpdatedAt: updatedAt,
+			},
+			expectedStatusCode:  http.StatusOK,
+			balanceServiceTimes: 1,
+		},
+		{
+			name:     "Success - Wallet in another currency",
+			userID:   "user123",
+			query:    "?currency=usd",
+			currency: domain.CurrencyUSD,
+			balance: &domain.Balance{
+				UserID:    "user123",
+				Currency:  domain.CurrencyUSD,
+				Available: 500,
+				UpdatedAt: updatedAt,
+			},
+			expectedStatusCode:  http.StatusOK,
+			balanceServiceTimes: 1,
+		},
+		{
+			name:                 "Error - Unsupported currency",
+			userID:               "user123",
+			query:                "?currency=XYZ",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+			balanceServiceTimes:  0,
+		},
+		{
+			name:                 "Error - Currency not held",
+			userID:               "user123",
+			query:                "?currency=BRL",
+			currency:             domain.CurrencyBRL,
+			balanceServiceError:  domain.ErrCurrencyNotHeld,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "CURRENCY_NOT_HELD",
+			balanceServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			userID:               "",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "unauthorized",
+			balanceServiceTimes:  0,
+		},
+		{
+			name:                 "Error - Wallet not found",
+			userID:               "user123",
+			balanceServiceError:  domain.ErrWalletNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: domain.ErrWalletNotFound.Error(),
+			balanceServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Balance unavailable",
+			userID:               "user123",
+			balanceServiceError:  domain.ErrGetBalance,
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedErrorMessage: "BALANCE_UNAVAILABLE",
+			balanceServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Balance service fails",
+			userID:               "user123",
+			balanceServiceError:  errors.New("service error"),
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedErrorMessage: "INTERNAL_ERROR",
+			balanceServiceTimes:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockBalanceSvc := mocks.NewMockBalanceService(ctrl)
+			mockBalanceSvc.EXPECT().Get(gomock.Any(), tt.userID, tt.currency.OrDefault()).
+				Return(tt.balance, tt.balanceServiceError).Times(tt.balanceServiceTimes)
+
+			server := &Server{
+				logger:         slog.Default(),
+				balanceService: mockBalanceSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/balance"+tt.query, nil)
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.getBalanceHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+
+			if tt.balance != nil {
+				var got domain.Balance
+				if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+					t.Fatalf("cannot unmarshal response: %v", err)
+				}
+				if got != *tt.balance {
+					t.Errorf("Expected balance %+v, got %+v", *tt.balance, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+func (s *Server) listBillersHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	billers, err := s.billerService.List(r.Context())
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot list billers", slog.Any("error", err))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponse(w, r, billers)
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"encoding/json"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServer_listBillersHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userID               string
+		billers              []domain.Biller
+		billerServiceError   error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		billerServiceTimes   int
+	}{
+		{
+			name:   "Success - Enabled billers",
+			userID: "user123",
+			billers: []domain.Biller{
+				{
+					ID:                  "biller-1",
+					Name:                "Aguas del Sur",
+					Currency:            domain.CurrencyARS,
+					MinAmount:           100,
+					MaxAmount:           20000000,
+					Enabled:             true,
+					ClientNumberPattern: "[0-9]{6,10}",
+				},
+			},
+			expectedStatusCode: http.StatusOK,
+			billerServiceTimes: 1,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "unauthorized",
+			billerServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Billers unavailable",
+			userID:               "user123",
+			billerServiceError:   domain.ErrListBillers,
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedErrorMessage: "BILLERS_UNAVAILABLE",
+			billerServiceTimes:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockBillerSvc := mocks.NewMockBillerService(ctrl)
+			mockBillerSvc.EXPECT().List(gomock.Any()).
+				Return(tt.billers, tt.billerServiceError).Times(tt.billerServiceTimes)
+
+			server := &Server{
+				logger:        slog.Default(),
+				billerService: mockBillerSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/billers", nil)
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.listBillersHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+
+			if tt.billers != nil {
+				var got []domain.Biller
+				if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+					t.Fatalf("cannot unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(got, tt.billers) {
+					t.Errorf("Expected billers %+v, got %+v", tt.billers, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+const (
+	CodeUnauthorized   = "UNAUTHORIZED"
+	CodeForbidden      = "FORBIDDEN"
+	CodeInvalidRequest = "INVALID_REQUEST"
+	CodeInternalError  = "INTERNAL_ERROR"
+)
+
+const (
+	_internalErrorMessage = "internal server error"
+)
+
+// apiError is the HTTP representation of a domain error.
+type apiError struct {
+	err    error
+	status int
+	code   string
+}
+
+// _apiErrors maps every domain sentinel error to the status and the stable
+// code returned to clients. Failures reading state are reported as 503 since
+// retrying is safe; failures writing it are reported as 500.
+var _apiErrors = []apiError{
+	{domain.ErrInsufficientFunds, http.StatusUnprocessableEntity, "INSUFFICIENT_FUNDS"},
+	{domain.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED"},
+	{domain.ErrRefundExceedsPayment, http.StatusUnprocessableEntity, "REFUND_EXCEEDS_PAYMENT"},
+	{domain.ErrWalletFrozen, http.StatusUnprocessableEntity, "WALLET_FROZEN"},
+	{domain.ErrWalletClosed, http.StatusUnprocessableEntity, "WALLET_CLOSED"},
+	{domain.ErrCurrencyNotHeld, http.StatusUnprocessableEntity, "CURRENCY_NOT_HELD"},
+	{domain.ErrFXRateUnavailable, http.StatusUnprocessableEntity, "FX_RATE_UNAVAILABLE"},
+	{domain.ErrLimitExceeded, http.StatusUnprocessableEntity, "LIMIT_EXCEEDED"},
+	{domain.ErrTopUpLimitExceeded, http.StatusUnprocessableEntity, "TOP_UP_LIMIT_EXCEEDED"},
+	{domain.ErrPaymentDenied, http.StatusUnprocessableEntity, "PAYMENT_DENIED"},
+	{domain.ErrBillerNotFound, http.StatusUnprocessableEntity, "UNKNOWN_BILLER"},
+	{domain.ErrBillerDisabled, http.StatusUnprocessableEntity, "BILLER_DISABLED"},
+	{domain.ErrBillerCurrencyMismatch, http.StatusUnprocessableEntity, "BILLER_CURRENCY_MISMATCH"},
+	{domain.ErrAmountOutOfRange, http.StatusUnprocessableEntity, "AMOUNT_OUT_OF_RANGE"},
+	{domain.ErrInvalidClientNumber, http.StatusUnprocessableEntity, "INVALID_CLIENT_NUMBER"},
+	{domain.ErrPaymentNotRefundable, http.StatusConflict, "PAYMENT_NOT_REFUNDABLE"},
+	{domain.ErrWalletAlreadyExists, http.StatusConflict, "WALLET_ALREADY_EXISTS"},
+	{domain.ErrWalletHasReservedFunds, http.StatusConflict, "WALLET_HAS_RESERVED_FUNDS"},
+	{domain.ErrInvalidWalletTransition, http.StatusConflict, "INVALID_WALLET_TRANSITION"},
+	{domain.ErrIdempotencyKeyConflict, http.StatusConflict, "IDEMPOTENCY_KEY_CONFLICT"},
+	{domain.ErrReservationNotFound, http.StatusConflict, "RESERVATION_NOT_FOUND"},
+	{domain.ErrWalletNotFound, http.StatusNotFound, "WALLET_NOT_FOUND"},
+	{domain.ErrPaymentNotFound, http.StatusNotFound, "PAYMENT_NOT_FOUND"},
+	{domain.ErrPaymentNotCancellable, http.StatusConflict, "PAYMENT_NOT_CANCELLABLE"},
+	{domain.ErrPaymentConflict, http.StatusConflict, "PAYMENT_CONFLICT"},
+	{domain.ErrPaymentNotUnderReview, http.StatusConflict, "PAYMENT_NOT_UNDER_REVIEW"},
+	{domain.ErrPaymentNotForceable, http.StatusConflict, "PAYMENT_NOT_FORCEABLE"},
+	{domain.ErrInvalidStatusTransition, http.StatusConflict, "INVALID_STATUS_TRANSITION"},
+	{domain.ErrInvalidPaymentFilter, http.StatusBadRequest, "INVALID_PAYMENT_FILTER"},
+	{domain.ErrInvalidCursor, http.StatusBadRequest, "INVALID_CURSOR"},
+	{domain.ErrInvalidPaymentResult, http.StatusBadRequest, "INVALID_PAYMENT_RESULT"},
+	{domain.ErrGetBalance, http.StatusServiceUnavailable, "BALANCE_UNAVAILABLE"},
+	{domain.ErrGetStatement, http.StatusServiceUnavailable, "STATEMENT_UNAVAILABLE"},
+	{domain.ErrCheckIdempotency, http.StatusServiceUnavailable, "IDEMPOTENCY_CHECK_FAILED"},
+	{domain.ErrGetPayment, http.StatusServiceUnavailable, "PAYMENT_UNAVAILABLE"},
+	{domain.ErrListPayments, http.StatusServiceUnavailable, "PAYMENTS_UNAVAILABLE"},
+	{domain.ErrGetPaymentHistory, http.StatusServiceUnavailable, "PAYMENT_HISTORY_UNAVAILABLE"},
+	{domain.ErrGetFXRate, http.StatusServiceUnavailable, "FX_PROVIDER_UNAVAILABLE"},
+	{domain.ErrGetLimits, http.StatusServiceUnavailable, "LIMITS_UNAVAILABLE"},
+	{domain.ErrEvaluateRisk, http.StatusServiceUnavailable, "RISK_UNAVAILABLE"},
+	{domain.ErrGetBiller, http.StatusServiceUnavailable, "BILLERS_UNAVAILABLE"},
+	{domain.ErrListBillers, http.StatusServiceUnavailable, "BILLERS_UNAVAILABLE"},
+	{domain.ErrReserveFunds, http.StatusInternalServerError, "RESERVE_FUNDS_FAILED"},
+	{domain.ErrUpdateBalance, http.StatusInternalServerError, "UPDATE_BALANCE_FAILED"},
+	{domain.ErrPostLedger, http.StatusInternalServerError, "LEDGER_POST_FAILED"},
+	{domain.ErrUnbalancedLedgerTransaction, http.StatusInternalServerError, "LEDGER_POST_FAILED"},
+	{domain.ErrCreatePayment, http.StatusInternalServerError, "CREATE_PAYMENT_FAILED"},
+	{domain.ErrCreateOutboxMessage, http.StatusInternalServerError, "CREATE_PAYMENT_FAILED"},
+	{domain.ErrUpdatePayment, http.StatusInternalServerError, "UPDATE_PAYMENT_FAILED"},
+	{domain.ErrCreateRefund, http.StatusInternalServerError, "CREATE_REFUND_FAILED"},
+	{domain.ErrCreditBalance, http.StatusInternalServerError, "CREDIT_BALANCE_FAILED"},
+	{domain.ErrRecordAudit, http.StatusInternalServerError, "AUDIT_FAILED"},
+	{domain.ErrCreateTopUp, http.StatusInternalServerError, "CREATE_TOPUP_FAILED"},
+	{domain.ErrCreateWallet, http.StatusInternalServerError, "CREATE_WALLET_FAILED"},
+	{domain.ErrCreateTransfer, http.StatusInternalServerError, "CREATE_TRANSFER_FAILED"},
+	{domain.ErrUpdateWallet, http.StatusInternalServerError, "UPDATE_WALLET_FAILED"},
+}
+
+// mapError resolves the status, code and client facing message of err.
+// Client errors keep the full message, which may carry validation details;
+// server errors only expose the sentinel message and unknown errors a
+// generic one, so internal details never leak.
+func mapError(err error) (int, string, string) {
+	for _, apiErr := range _apiErrors {
+		if !errors.Is(err, apiErr.err) {
+			continue
+		}
+
+		if apiErr.status >= http.StatusInternalServerError {
+			return apiErr.status, apiErr.code, apiErr.err.Error()
+		}
+
+		return apiErr.status, apiErr.code, err.Error()
+	}
+
+	return http.StatusInternalServerError, CodeInternalError, _internalErrorMessage
+}
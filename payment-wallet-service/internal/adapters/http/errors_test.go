@@ -0,0 +1,201 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+)
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedCode    string
+		expectedMessage string
+	}{
+		{
+			name:            "Client error keeps the message",
+			err:             domain.ErrInsufficientFunds,
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedCode:    "INSUFFICIENT_FUNDS",
+			expectedMessage: domain.ErrInsufficientFunds.Error(),
+		},
+		{
+			name:            "Wrapped client error keeps the details",
+			err:             fmt.Errorf("%w: limit: must be no greater than 100", domain.ErrInvalidPaymentFilter),
+			expectedStatus:  http.StatusBadRequest,
+			expectedCode:    "INVALID_PAYMENT_FILTER",
+			expectedMessage: "invalid payment filter: limit: must be no greater than 100",
+		},
+		{
+			name: "Limit exceeded tells which limit was hit",
+			err: &domain.LimitExceededError{
+				Limit: domain.LimitHourlyCount,
+				Max:   10,
+				Used:  10,
+			},
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedCode:    "LIMIT_EXCEEDED",
+			expectedMessage: "spending limit exceeded: HOURLY_COUNT of 10 payments, 10 already made",
+		},
+		{
+			name:            "Read failure is unavailable",
+			err:             domain.ErrGetBalance,
+			expectedStatus:  http.StatusServiceUnavailable,
+			expectedCode:    "BALANCE_UNAVAILABLE",
+			expectedMessage: domain.ErrGetBalance.Error(),
+		},
+		{
+			name:            "Wrapped server error hides the details",
+			err:             fmt.Errorf("%w: connection refused", domain.ErrCreatePayment),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedCode:    "CREATE_PAYMENT_FAILED",
+			expectedMessage: domain.ErrCreatePayment.Error(),
+		},
+		{
+			name:            "Unknown error is generic",
+			err:             errors.New("pq: relation payments does not exist"),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedCode:    CodeInternalError,
+			expectedMessage: _internalErrorMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code, message := mapError(tt.err)
+
+			if status != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, status)
+			}
+			if code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, code)
+			}
+			if message != tt.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tt.expectedMessage, message)
+			}
+		})
+	}
+}
+
+func TestServer_ErrorResponse(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestID         string
+		expectGeneratedID bool
+	}{
+		{
+			name:      "Request ID is echoed",
+			requestID: "req-123",
+		},
+		{
+			name:              "Request ID is generated",
+			expectGeneratedID: true,
+		},
+		{
+			name:              "Request ID with unsafe characters is replaced",
+			requestID:         "req-123\nlevel=ERROR",
+			expectGeneratedID: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{logger: slog.Default()}
+
+			req := httptest.NewRequest(http.MethodGet, "/payments", nil)
+			if tt.requestID != "" {
+				req.Header.Set(_requestIDHeader, tt.requestID)
+			}
+
+			w := httptest.NewRecorder()
+
+			server.DomainErrorResponse(w, req, domain.ErrPaymentNotFound)
+
+			if w.Code != http.StatusNotFound {
+				t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+			}
+
+			var body struct {
+				Code      string `json:"code"`
+				Message   string `json:"message"`
+				RequestID string `json:"request_id"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("cannot unmarshal response: %v", err)
+			}
+
+			if body.Code != "PAYMENT_NOT_FOUND" {
+				t.Errorf("Expected code PAYMENT_NOT_FOUND, got %s", body.Code)
+			}
+			if tt.expectGeneratedID && (body.RequestID == "" || body.RequestID == tt.requestID) {
+				t.Errorf("Expected a generated request ID, got %q", body.RequestID)
+			}
+			if !tt.expectGeneratedID && body.RequestID != tt.requestID {
+				t.Errorf("Expected request ID %s, got %s", tt.requestID, body.RequestID)
+			}
+			if got := w.Header().Get(_requestIDHeader); got != body.RequestID {
+				t.Errorf("Expected header request ID %s, got %s", body.RequestID, got)
+			}
+		})
+	}
+}
+
+func TestServer_tagRequestID(t *testing.T) {
+	tests := []struct {
+		name              string
+		requestID         string
+		expectGeneratedID bool
+	}{
+		{
+			name:      "Request ID of the caller is tagged",
+			requestID: "req-123",
+		},
+		{
+			name:              "Generated request ID is tagged",
+			expectGeneratedID: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{logger: slog.Default()}
+
+			var tagged string
+			handler := server.tagRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tagged = correlation.RequestID(r.Context())
+				server.DomainErrorResponse(w, r, domain.ErrPaymentNotFound)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/payments", nil)
+			if tt.requestID != "" {
+				req.Header.Set(_requestIDHeader, tt.requestID)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if tt.expectGeneratedID && tagged == "" {
+				t.Errorf("Expected a generated request ID in the context")
+			}
+			if !tt.expectGeneratedID && tagged != tt.requestID {
+				t.Errorf("Expected request ID %s in the context, got %s", tt.requestID, tagged)
+			}
+			if got := w.Header().Get(_requestIDHeader); got != tagged {
+				t.Errorf("Expected header request ID %s, got %s", tagged, got)
+			}
+			if !strings.Contains(w.Body.String(), tagged) {
+				t.Errorf("Expected the error response to quote request ID %s", tagged)
+			}
+		})
+	}
+}
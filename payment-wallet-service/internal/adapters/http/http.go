@@ -5,13 +5,21 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
+)
+
+const (
+	_requestIDHeader    = "X-Request-ID"
+	_maxRequestIDLength = 128
 )
 
-func (s *Server) JSONResponse(w http.ResponseWriter, _ *http.Request, result any) {
+func (s *Server) JSONResponse(w http.ResponseWriter, r *http.Request, result any) {
 	body, err := json.Marshal(result)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		s.logger.Error("cannot marshal response", slog.Any("error", err))
+		s.logger.ErrorContext(r.Context(), "cannot marshal response", slog.Any("error", err))
 		return
 	}
 
@@ -20,11 +28,11 @@ func (s *Server) JSONResponse(w http.ResponseWriter, _ *http.Request, result any
 	_, _ = w.Write(prettyJSON(body))
 }
 
-func (s *Server) JSONResponseCode(w http.ResponseWriter, _ *http.Request, result interface{}, responseCode int) {
+func (s *Server) JSONResponseCode(w http.ResponseWriter, r *http.Request, result interface{}, responseCode int) {
 	body, err := json.Marshal(result)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		s.logger.Error("JSON marshal failed", slog.Any("error", err))
+		s.logger.ErrorContext(r.Context(), "JSON marshal failed", slog.Any("error", err))
 		return
 	}
 
@@ -39,24 +47,79 @@ func prettyJSON(b []byte) []byte {
 	return out.Bytes()
 }
 
-func (s *Server) ErrorResponse(w http.ResponseWriter, _ *http.Request, error string, code int) {
+// ErrorResponse writes the error schema shared by every endpoint. The code is
+// stable and meant for clients to branch on, the message is for humans.
+func (s *Server) ErrorResponse(w http.ResponseWriter, r *http.Request, code, message string, status int) {
 	data := struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
 	}{
-		Code:    code,
-		Message: error,
+		Code:      code,
+		Message:   message,
+		RequestID: requestID(w, r),
 	}
 
 	body, err := json.Marshal(data)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
-		s.logger.Error("JSON marshal failed", slog.Any("error", err))
+		s.logger.ErrorContext(r.Context(), "JSON marshal failed", slog.Any("error", err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.Header().Set("X-Content-Type-Options", "nosniff")
-	w.WriteHeader(code)
+	w.WriteHeader(status)
 	_, _ = w.Write(prettyJSON(body))
 }
+
+// DomainErrorResponse writes err using the status and code it maps to.
+func (s *Server) DomainErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	status, code, message := mapError(err)
+	s.ErrorResponse(w, r, code, message, status)
+}
+
+// tagRequestID puts the request ID in the context, where the logs and the
+// events published while serving the request take it from.
+func (s *Server) tagRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestID(w, r)
+		next.ServeHTTP(w, r.WithContext(correlation.WithRequestID(r.Context(), id)))
+	})
+}
+
+// requestID returns the ID the caller sent in X-Request-ID, or generates one,
+// and echoes it back so clients can quote it when reporting an error. A
+// request already tagged keeps its ID.
+func requestID(w http.ResponseWriter, r *http.Request) string {
+	if id := correlation.RequestID(r.Context()); id != "" {
+		return id
+	}
+
+	id := r.Header.Get(_requestIDHeader)
+	if !validRequestID(id) {
+		id = uidgen.NewUUID()
+	}
+
+	w.Header().Set(_requestIDHeader, id)
+	return id
+}
+
+// validRequestID accepts IDs of up to 128 letters, digits and the separators
+// commonly found in them, so a caller cannot forge log lines or headers.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > _maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
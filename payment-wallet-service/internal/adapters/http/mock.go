@@ -7,16 +7,22 @@ import (
 )
 
 type deps struct {
-	balanceSvc *mocks.MockBalanceService
-	paymentSvc *mocks.MockPaymentService
+	balanceSvc  *mocks.MockBalanceService
+	paymentSvc  *mocks.MockPaymentService
+	refundSvc   *mocks.MockRefundService
+	walletSvc   *mocks.MockWalletService
+	transferSvc *mocks.MockTransferService
 }
 
 func NewMockServer(deps *deps) *Server {
 	return &Server{
-		port:           5555,
-		logger:         slog.Default(),
-		router:         mux.NewRouter(),
-		paymentService: deps.paymentSvc,
-		balanceService: deps.balanceSvc,
+		port:            5555,
+		logger:          slog.Default(),
+		router:          mux.NewRouter(),
+		paymentService:  deps.paymentSvc,
+		balanceService:  deps.balanceSvc,
+		refundService:   deps.refundSvc,
+		walletService:   deps.walletSvc,
+		transferService: deps.transferSvc,
 	}
 }
@@ -3,40 +3,296 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+const (
+	_idempotentReplayedHeader = "Idempotent-Replayed"
+	_forwardedForHeader       = "X-Forwarded-For"
+	_deviceIDHeader           = "X-Device-ID"
 )
 
 func (s *Server) createPaymentHandler(w http.ResponseWriter, r *http.Request) {
-	userID := r.Header.Get("X-User-ID")
+	userID := userIDFromContext(r.Context())
 	if userID == "" {
-		s.ErrorResponse(w, r, "unauthorized", http.StatusUnauthorized)
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	var req domain.CreatePaymentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.logger.Error("cannot unmarshal request", slog.Any("error", err))
-		s.ErrorResponse(w, r, err.Error(), http.StatusBadRequest)
+		s.logger.ErrorContext(r.Context(), "cannot unmarshal request", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
 		return
 	}
 	req.UserID = userID
+	req.Currency = req.Currency.OrDefault()
+	if req.FundingCurrency == "" {
+		req.FundingCurrency = req.Currency
+	}
+	req.Metadata = domain.RequestMetadata{
+		IP:       s.clientIP(r),
+		DeviceID: r.Header.Get(_deviceIDHeader),
+	}
 
 	if err := req.Validate(); err != nil {
-		s.logger.Error("validation error", slog.Any("error", err))
-		s.ErrorResponse(w, r, err.Error(), http.StatusBadRequest)
+		s.logger.ErrorContext(r.Context(), "validation error", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payment, replayed, err := s.paymentService.Create(r.Context(), req)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot create payment", slog.Any("error", err))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	if replayed {
+		w.Header().Set(_idempotentReplayedHeader, "true")
+	}
+
+	s.JSONResponseCode(w, r, payment, http.StatusCreated)
+}
+
+func (s *Server) approvePaymentHandler(w http.ResponseWriter, r *http.Request) {
+	s.reviewPayment(w, r, "approve", s.paymentService.Approve)
+}
+
+func (s *Server) rejectPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	s.reviewPayment(w, r, "reject", s.paymentService.Reject)
+}
+
+// reviewPayment settles the review of a payment held by the risk evaluation on
+// behalf of the authenticated operator.
+func (s *Server) reviewPayment(w http.ResponseWriter, r *http.Request, action string,
+	review func(ctx context.Context, operator domain.Operator, paymentID string) (*domain.Payment, error)) {
+	operator, ok := operatorFromContext(r.Context())
+	if !ok {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	paymentID := mux.Vars(r)["id"]
+	payment, err := review(r.Context(), operator, paymentID)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot review payment",
+			slog.Any("error", err),
+			slog.String("action", action),
+			slog.String("transaction_id", paymentID))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.logger.InfoContext(r.Context(), "payment reviewed by admin",
+		slog.String("action", action),
+		slog.String("operator_id", operator.ID),
+		slog.String("transaction_id", paymentID),
+		slog.String("status", string(payment.Status)))
+
+	s.JSONResponse(w, r, payment)
+}
+
+func (s *Server) getPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	payment, err := s.paymentService.Get(r.Context(), userID, mux.Vars(r)["id"])
+	if err != nil {
+		if !errors.Is(err, domain.ErrPaymentNotFound) {
+			s.logger.ErrorContext(r.Context(), "cannot get payment", slog.Any("error", err))
+		}
+
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponse(w, r, payment)
+}
+
+func (s *Server) cancelPaymentHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	payment, err := s.paymentService.Cancel(r.Context(), userID, mux.Vars(r)["id"])
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot cancel payment", slog.Any("error", err))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponse(w, r, payment)
+}
+
+func (s *Server) getPaymentHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	history, err := s.paymentService.History(r.Context(), userID, mux.Vars(r)["id"])
+	if err != nil {
+		if !errors.Is(err, domain.ErrPaymentNotFound) {
+			s.logger.ErrorContext(r.Context(), "cannot get payment history", slog.Any("error", err))
+		}
+
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponse(w, r, history)
+}
+
+func (s *Server) listPaymentsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	err := s.paymentService.Create(context.TODO(), req)
+	filter, err := parsePaymentFilter(r.URL.Query())
 	if err != nil {
-		s.logger.Error("cannot create payment", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.UserID = userID
+
+	page, err := s.paymentService.List(r.Context(), filter)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidPaymentFilter) && !errors.Is(err, domain.ErrInvalidCursor) {
+			s.logger.ErrorContext(r.Context(), "cannot list payments", slog.Any("error", err))
+		}
 
-		s.ErrorResponse(w, r, err.Error(), http.StatusBadRequest)
+		s.DomainErrorResponse(w, r, err)
 		return
 	}
 
-	s.JSONResponseCode(w, r, "", http.StatusCreated)
+	s.JSONResponse(w, r, page)
+}
+
+func parsePaymentFilter(query url.Values) (domain.PaymentFilter, error) {
+	filter := domain.PaymentFilter{
+		Status:    domain.PaymentStatus(query.Get("status")),
+		ServiceID: query.Get("service_id"),
+		Cursor:    query.Get("cursor"),
+	}
+
+	var err error
+	if filter.MinAmount, err = parseInt(query, "min_amount"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = parseInt(query, "max_amount"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedFrom, err = parseTime(query, "created_from"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseTime(query, "created_to"); err != nil {
+		return filter, err
+	}
+
+	limit, err := parseInt(query, "limit")
+	if err != nil {
+		return filter, err
+	}
+	filter.Limit = int(limit)
+
+	return filter, nil
+}
+
+func parseInt(query url.Values, key string) (int64, error) {
+	value := query.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", key)
+	}
+
+	return n, nil
+}
+
+func parseTime(query url.Values, key string) (time.Time, error) {
+	value := query.Get(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC 3339 timestamp", key)
+	}
+
+	return t, nil
+}
+
+// clientIP returns the address the request originated from. X-Forwarded-For
+// is only read when the request came through a trusted proxy, and then from
+// the right, taking the first hop not added by a trusted proxy: the hops to its
+// left are set by the client and can be spoofed.
+func (s *Server) clientIP(r *http.Request) string {
+	ip := remoteIP(r)
+	if !s.trustedProxy(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values(_forwardedForHeader), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !s.trustedProxy(hop) {
+			return hop
+		}
+		ip = hop
+	}
+
+	return ip
+}
+
+func (s *Server) trustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+	for _, proxy := range s.trustedProxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
 }
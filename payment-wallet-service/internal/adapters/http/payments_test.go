@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/gorilla/mux"
 	"go.uber.org/mock/gomock"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestServer_createPaymentHandler(t *testing.T) {
@@ -19,10 +23,13 @@ func TestServer_createPaymentHandler(t *testing.T) {
 		name                 string
 		userID               string
 		requestBody          interface{}
+		payment              *domain.Payment
+		replayed             bool
 		paymentServiceError  error
 		validateError        error
 		expectedStatusCode   int
 		expectedErrorMessage string
+		expectedReplayed     bool
 		paymentServiceTimes  int
 	}{
 		{
@@ -35,7 +42,156 @@ func TestServer_createPaymentHandler(t *testing.T) {
 				Amount:         10,
 				IdempotencyKey: "test-idempotency-key",
 			},
-			expectedStatusCode:  http.StatusCreated,
+			payment:              &domain.Payment{ID: "payment-1", Status: "PENDING"},
+			expectedStatusCode:   http.StatusCreated,
+			expectedErrorMessage: "payment-1",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Success - Replayed request",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			payment:              &domain.Payment{ID: "payment-1", Status: "APPROVED"},
+			replayed:             true,
+			expectedStatusCode:   http.StatusCreated,
+			expectedErrorMessage: "APPROVED",
+			expectedReplayed:     true,
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Unsupported currency",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				Currency:       "XYZ",
+				IdempotencyKey: "test-idempotency-key",
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "currency",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:   "Error - Currency not held",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				Currency:       domain.CurrencyUSD,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError:  domain.ErrCurrencyNotHeld,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "CURRENCY_NOT_HELD",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Unsupported funding currency",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:          "user123",
+				ClientNumber:    "client-number",
+				ServiceID:       "service-id",
+				Amount:          10,
+				Currency:        domain.CurrencyUSD,
+				FundingCurrency: "XYZ",
+				IdempotencyKey:  "test-idempotency-key",
+			},
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "funding_currency",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:   "Error - No exchange rate for the currency pair",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:          "user123",
+				ClientNumber:    "client-number",
+				ServiceID:       "service-id",
+				Amount:          10,
+				Currency:        domain.CurrencyUSD,
+				FundingCurrency: domain.CurrencyUYU,
+				IdempotencyKey:  "test-idempotency-key",
+			},
+			paymentServiceError:  domain.ErrFXRateUnavailable,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "FX_RATE_UNAVAILABLE",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Exchange rate provider unavailable",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:          "user123",
+				ClientNumber:    "client-number",
+				ServiceID:       "service-id",
+				Amount:          10,
+				Currency:        domain.CurrencyUSD,
+				FundingCurrency: domain.CurrencyARS,
+				IdempotencyKey:  "test-idempotency-key",
+			},
+			paymentServiceError:  domain.ErrGetFXRate,
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedErrorMessage: "FX_PROVIDER_UNAVAILABLE",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Spending limit exceeded",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError: &domain.LimitExceededError{
+				Limit:     domain.LimitDailyAmount,
+				Max:       100000,
+				Used:      99995,
+				Requested: 10,
+				Currency:  domain.CurrencyARS,
+			},
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "DAILY_AMOUNT of 1000.00 ARS, 999.95 ARS already used",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Idempotency key reused",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError: domain.ErrIdempotencyKeyReused,
+			expectedStatusCode:  http.StatusUnprocessableEntity,
+			paymentServiceTimes: 1,
+		},
+		{
+			name:   "Error - Idempotency key conflict",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError: domain.ErrIdempotencyKeyConflict,
+			expectedStatusCode:  http.StatusConflict,
 			paymentServiceTimes: 1,
 		},
 		{
@@ -64,8 +220,83 @@ func TestServer_createPaymentHandler(t *testing.T) {
 				IdempotencyKey: "test-idempotency-key",
 			},
 			paymentServiceError:  errors.New("service error"),
-			expectedStatusCode:   http.StatusBadRequest,
-			expectedErrorMessage: "service error",
+			expectedStatusCode:   http.StatusInternalServerError,
+			expectedErrorMessage: "internal server error",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Insufficient funds",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError:  domain.ErrInsufficientFunds,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "INSUFFICIENT_FUNDS",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Idempotency check unavailable",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError:  domain.ErrCheckIdempotency,
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedErrorMessage: "IDEMPOTENCY_CHECK_FAILED",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Payment denied by risk evaluation",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError:  domain.ErrPaymentDenied,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "PAYMENT_DENIED",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Risk evaluation unavailable",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError:  domain.ErrEvaluateRisk,
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedErrorMessage: "RISK_UNAVAILABLE",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:   "Error - Client number rejected by the biller",
+			userID: "user123",
+			requestBody: domain.CreatePaymentRequest{
+				UserID:         "user123",
+				ClientNumber:   "client-number",
+				ServiceID:      "service-id",
+				Amount:         10,
+				IdempotencyKey: "test-idempotency-key",
+			},
+			paymentServiceError:  fmt.Errorf("%w: wrong check digit", domain.ErrInvalidClientNumber),
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "invalid client number: wrong check digit",
 			paymentServiceTimes:  1,
 		},
 	}
@@ -75,7 +306,7 @@ func TestServer_createPaymentHandler(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockPaymentSvc := mocks.NewMockPaymentService(ctrl)
 			mockPaymentSvc.EXPECT().Create(gomock.Any(), gomock.Any()).
-				Return(tt.paymentServiceError).Times(tt.paymentServiceTimes)
+				Return(tt.payment, tt.replayed, tt.paymentServiceError).Times(tt.paymentServiceTimes)
 
 			server := &Server{
 				logger:         slog.Default(),
@@ -96,7 +327,7 @@ func TestServer_createPaymentHandler(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPost, "/payments", bodyReader)
 			if tt.userID != "" {
-				req.Header.Set("X-User-ID", tt.userID)
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
 			}
 
 			w := httptest.NewRecorder()
@@ -113,6 +344,551 @@ func TestServer_createPaymentHandler(t *testing.T) {
 					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
 				}
 			}
+
+			if replayed := w.Header().Get(_idempotentReplayedHeader) == "true"; replayed != tt.expectedReplayed {
+				t.Errorf("Expected replayed header %v, got %v", tt.expectedReplayed, replayed)
+			}
+		})
+	}
+}
+
+func TestServer_getPaymentHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userID               string
+		payment              *domain.Payment
+		paymentServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		paymentServiceTimes  int
+	}{
+		{
+			name:                "Success - Payment found",
+			userID:              "user123",
+			payment:             &domain.Payment{ID: "payment-1", UserID: "user123", Status: "PENDING"},
+			expectedStatusCode:  http.StatusOK,
+			paymentServiceTimes: 1,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			userID:               "",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "unauthorized",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:                 "Error - Payment not found",
+			userID:               "user123",
+			paymentServiceError:  domain.ErrPaymentNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: domain.ErrPaymentNotFound.Error(),
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                "Error - Payment service fails",
+			userID:              "user123",
+			paymentServiceError: domain.ErrGetPayment,
+			expectedStatusCode:  http.StatusServiceUnavailable,
+			paymentServiceTimes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockPaymentSvc := mocks.NewMockPaymentService(ctrl)
+			mockPaymentSvc.EXPECT().Get(gomock.Any(), tt.userID, "payment-1").
+				Return(tt.payment, tt.paymentServiceError).Times(tt.paymentServiceTimes)
+
+			server := &Server{
+				logger:         slog.Default(),
+				paymentService: mockPaymentSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/payments/payment-1", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "payment-1"})
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.getPaymentHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_cancelPaymentHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userID               string
+		payment              *domain.Payment
+		paymentServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		paymentServiceTimes  int
+	}{
+		{
+			name:                 "Success - Payment cancelled",
+			userID:               "user123",
+			payment:              &domain.Payment{ID: "payment-1", UserID: "user123", Status: "CANCELLED"},
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: "CANCELLED",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			userID:               "",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "unauthorized",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:                 "Error - Payment not found",
+			userID:               "user123",
+			paymentServiceError:  domain.ErrPaymentNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: "PAYMENT_NOT_FOUND",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Payment not pending",
+			userID:               "user123",
+			paymentServiceError:  domain.ErrPaymentNotCancellable,
+			expectedStatusCode:   http.StatusConflict,
+			expectedErrorMessage: "PAYMENT_NOT_CANCELLABLE",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Payment modified concurrently",
+			userID:               "user123",
+			paymentServiceError:  domain.ErrPaymentConflict,
+			expectedStatusCode:   http.StatusConflict,
+			expectedErrorMessage: "PAYMENT_CONFLICT",
+			paymentServiceTimes:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockPaymentSvc := mocks.NewMockPaymentService(ctrl)
+			mockPaymentSvc.EXPECT().Cancel(gomock.Any(), tt.userID, "payment-1").
+				Return(tt.payment, tt.paymentServiceError).Times(tt.paymentServiceTimes)
+
+			server := &Server{
+				logger:         slog.Default(),
+				paymentService: mockPaymentSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/payments/payment-1/cancel", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "payment-1"})
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.cancelPaymentHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_getPaymentHistoryHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userID               string
+		history              []domain.PaymentStatusChange
+		paymentServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		paymentServiceTimes  int
+	}{
+		{
+			name:   "Success - History found",
+			userID: "user123",
+			history: []domain.PaymentStatusChange{
+				{PaymentID: "payment-1", To: domain.StatusPending},
+				{PaymentID: "payment-1", From: domain.StatusPending, To: domain.StatusApproved},
+			},
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: "APPROVED",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			userID:               "",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "unauthorized",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:                 "Error - Payment not found",
+			userID:               "user123",
+			paymentServiceError:  domain.ErrPaymentNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: "PAYMENT_NOT_FOUND",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - History unavailable",
+			userID:               "user123",
+			paymentServiceError:  domain.ErrGetPaymentHistory,
+			expectedStatusCode:   http.StatusServiceUnavailable,
+			expectedErrorMessage: "PAYMENT_HISTORY_UNAVAILABLE",
+			paymentServiceTimes:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockPaymentSvc := mocks.NewMockPaymentService(ctrl)
+			mockPaymentSvc.EXPECT().History(gomock.Any(), tt.userID, "payment-1").
+				Return(tt.history, tt.paymentServiceError).Times(tt.paymentServiceTimes)
+
+			server := &Server{
+				logger:         slog.Default(),
+				paymentService: mockPaymentSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/payments/payment-1/history", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "payment-1"})
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.getPaymentHistoryHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_listPaymentsHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userID               string
+		query                string
+		expectedFilter       domain.PaymentFilter
+		paymentServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		paymentServiceTimes  int
+	}{
+		{
+			name:   "Success - Filters are parsed",
+			userID: "user123",
+			query:  "status=APPROVED&service_id=svc-1&min_amount=10&max_amount=500&created_from=2025-09-01T00:00:00Z&created_to=2025-10-01T00:00:00Z&limit=5&cursor=abc",
+			expectedFilter: domain.PaymentFilter{
+				UserID:      "user123",
+				Status:      "APPROVED",
+				ServiceID:   "svc-1",
+				MinAmount:   10,
+				MaxAmount:   500,
+				CreatedFrom: time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+				CreatedTo:   time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+				Limit:       5,
+				Cursor:      "abc",
+			},
+			expectedStatusCode:  http.StatusOK,
+			paymentServiceTimes: 1,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			userID:               "",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "unauthorized",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:                 "Error - Invalid amount",
+			userID:               "user123",
+			query:                "min_amount=ten",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "min_amount must be an integer",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:                 "Error - Invalid date",
+			userID:               "user123",
+			query:                "created_from=yesterday",
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "created_from must be an RFC 3339 timestamp",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:                "Error - Invalid cursor",
+			userID:              "user123",
+			query:               "cursor=bogus",
+			expectedFilter:      domain.PaymentFilter{UserID: "user123", Cursor: "bogus"},
+			paymentServiceError: domain.ErrInvalidCursor,
+			expectedStatusCode:  http.StatusBadRequest,
+			paymentServiceTimes: 1,
+		},
+		{
+			name:                "Error - Payment service fails",
+			userID:              "user123",
+			expectedFilter:      domain.PaymentFilter{UserID: "user123"},
+			paymentServiceError: errors.New("service error"),
+			expectedStatusCode:  http.StatusInternalServerError,
+			paymentServiceTimes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockPaymentSvc := mocks.NewMockPaymentService(ctrl)
+			mockPaymentSvc.EXPECT().List(gomock.Any(), tt.expectedFilter).
+				Return(&domain.PaymentPage{Payments: []domain.Payment{}}, tt.paymentServiceError).
+				Times(tt.paymentServiceTimes)
+
+			server := &Server{
+				logger:         slog.Default(),
+				paymentService: mockPaymentSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/payments?"+tt.query, nil)
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.listPaymentsHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_reviewPaymentHandlers(t *testing.T) {
+	tests := []struct {
+		name                 string
+		operatorID           string
+		action               string
+		status               domain.PaymentStatus
+		paymentServiceError  error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		paymentServiceTimes  int
+	}{
+		{
+			name:                 "Success - Payment approved",
+			operatorID:           "operator-1",
+			action:               "approve",
+			status:               domain.StatusPending,
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: `"status": "PENDING"`,
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Success - Payment rejected",
+			operatorID:           "operator-1",
+			action:               "reject",
+			status:               domain.StatusRejected,
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: `"status": "REJECTED"`,
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Missing operator",
+			action:               "approve",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+			paymentServiceTimes:  0,
+		},
+		{
+			name:                 "Error - Payment not under review",
+			operatorID:           "operator-1",
+			action:               "reject",
+			paymentServiceError:  domain.ErrPaymentNotUnderReview,
+			expectedStatusCode:   http.StatusConflict,
+			expectedErrorMessage: "PAYMENT_NOT_UNDER_REVIEW",
+			paymentServiceTimes:  1,
+		},
+		{
+			name:                 "Error - Payment not found",
+			operatorID:           "operator-1",
+			action:               "approve",
+			paymentServiceError:  domain.ErrPaymentNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: "PAYMENT_NOT_FOUND",
+			paymentServiceTimes:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockPaymentSvc := mocks.NewMockPaymentService(ctrl)
+
+			var payment *domain.Payment
+			if tt.paymentServiceError == nil {
+				payment = &domain.Payment{ID: "payment-1", UserID: "user123", Status: tt.status}
+			}
+
+			server := &Server{
+				logger:         slog.Default(),
+				paymentService: mockPaymentSvc,
+			}
+
+			operator := domain.Operator{ID: tt.operatorID, Roles: []domain.Role{domain.RoleOps}}
+
+			var (
+				handler http.HandlerFunc
+				call    *gomock.Call
+			)
+			switch tt.action {
+			case "approve":
+				handler = server.approvePaymentHandler
+				call = mockPaymentSvc.EXPECT().Approve(gomock.Any(), operator, "payment-1")
+			case "reject":
+				handler = server.rejectPaymentHandler
+				call = mockPaymentSvc.EXPECT().Reject(gomock.Any(), operator, "payment-1")
+			}
+			call.Return(payment, tt.paymentServiceError).Times(tt.paymentServiceTimes)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/v1/payments/payment-1/"+tt.action, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "payment-1"})
+			if tt.operatorID != "" {
+				req = req.WithContext(withOperator(req.Context(), operator))
+			}
+
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.expectedErrorMessage) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name           string
+		trustedProxies []netip.Prefix
+		forwardedFor   []string
+		remoteAddr     string
+		expectedIP     string
+	}{
+		{
+			name:       "Remote address",
+			remoteAddr: "192.0.2.10:52100",
+			expectedIP: "192.0.2.10",
+		},
+		{
+			name:         "X-Forwarded-For ignored without trusted proxies",
+			forwardedFor: []string{"203.0.113.7"},
+			remoteAddr:   "192.0.2.10:52100",
+			expectedIP:   "192.0.2.10",
+		},
+		{
+			name:           "X-Forwarded-For ignored from an untrusted address",
+			trustedProxies: proxies,
+			forwardedFor:   []string{"203.0.113.7"},
+			remoteAddr:     "192.0.2.10:52100",
+			expectedIP:     "192.0.2.10",
+		},
+		{
+			name:           "Hop added by the trusted proxy",
+			trustedProxies: proxies,
+			forwardedFor:   []string{"203.0.113.7"},
+			remoteAddr:     "10.0.0.3:52100",
+			expectedIP:     "203.0.113.7",
+		},
+		{
+			name:           "Spoofed hops left of the client are skipped",
+			trustedProxies: proxies,
+			forwardedFor:   []string{"198.51.100.1, 203.0.113.7, 10.0.0.2"},
+			remoteAddr:     "10.0.0.3:52100",
+			expectedIP:     "203.0.113.7",
+		},
+		{
+			name:           "Hops across several headers",
+			trustedProxies: proxies,
+			forwardedFor:   []string{"198.51.100.1", "10.0.0.2"},
+			remoteAddr:     "10.0.0.3:52100",
+			expectedIP:     "198.51.100.1",
+		},
+		{
+			name:           "Only trusted hops",
+			trustedProxies: proxies,
+			forwardedFor:   []string{"10.0.0.1, 10.0.0.2"},
+			remoteAddr:     "10.0.0.3:52100",
+			expectedIP:     "10.0.0.1",
+		},
+		{
+			name:       "Remote address without port",
+			remoteAddr: "192.0.2.10",
+			expectedIP: "192.0.2.10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{trustedProxies: tt.trustedProxies}
+			req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, forwardedFor := range tt.forwardedFor {
+				req.Header.Add(_forwardedForHeader, forwardedFor)
+			}
+
+			if ip := server.clientIP(req); ip != tt.expectedIP {
+				t.Errorf("Expected IP %s, got %s", tt.expectedIP, ip)
+			}
 		})
 	}
 }
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) createRefundHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.CreateRefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot unmarshal request", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.UserID = userID
+	req.PaymentID = mux.Vars(r)["id"]
+
+	if err := req.Validate(); err != nil {
+		s.logger.ErrorContext(r.Context(), "validation error", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	refund, replayed, err := s.refundService.Create(r.Context(), req)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot create refund", slog.Any("error", err))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	if replayed {
+		w.Header().Set(_idempotentReplayedHeader, "true")
+	}
+
+	s.JSONResponseCode(w, r, refund, http.StatusCreated)
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestServer_createRefundHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userID               string
+		body                 string
+		refund               *domain.Refund
+		replayed             bool
+		refundServiceError   error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		refundServiceTimes   int
+	}{
+		{
+			name:                 "Success - Refund created",
+			userID:               "user123",
+			body:                 `{"amount": 500, "idempotency_key": "refund-key"}`,
+			refund:               &domain.Refund{ID: "refund-1", PaymentID: "payment-1", Amount: 500},
+			expectedStatusCode:   http.StatusCreated,
+			expectedErrorMessage: "refund-1",
+			refundServiceTimes:   1,
+		},
+		{
+			name:               "Success - Replayed request",
+			userID:             "user123",
+			body:               `{"amount": 500, "idempotency_key": "refund-key"}`,
+			refund:             &domain.Refund{ID: "refund-1", PaymentID: "payment-1", Amount: 500},
+			replayed:           true,
+			expectedStatusCode: http.StatusCreated,
+			refundServiceTimes: 1,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			userID:               "",
+			body:                 `{"amount": 500, "idempotency_key": "refund-key"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "unauthorized",
+			refundServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Missing idempotency key",
+			userID:               "user123",
+			body:                 `{"amount": 500}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+			refundServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Refund exceeds payment",
+			userID:               "user123",
+			body:                 `{"amount": 500, "idempotency_key": "refund-key"}`,
+			refundServiceError:   domain.ErrRefundExceedsPayment,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "REFUND_EXCEEDS_PAYMENT",
+			refundServiceTimes:   1,
+		},
+		{
+			name:                 "Error - Payment not refundable",
+			userID:               "user123",
+			body:                 `{"amount": 500, "idempotency_key": "refund-key"}`,
+			refundServiceError:   domain.ErrPaymentNotRefundable,
+			expectedStatusCode:   http.StatusConflict,
+			expectedErrorMessage: "PAYMENT_NOT_REFUNDABLE",
+			refundServiceTimes:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRefundSvc := mocks.NewMockRefundService(ctrl)
+			mockRefundSvc.EXPECT().Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ any, request domain.CreateRefundRequest) (*domain.Refund, bool, error) {
+					if request.UserID != tt.userID || request.PaymentID != "payment-1" {
+						t.Errorf("Unexpected request %+v", request)
+					}
+					return tt.refund, tt.replayed, tt.refundServiceError
+				}).Times(tt.refundServiceTimes)
+
+			server := &Server{
+				logger:        slog.Default(),
+				refundService: mockRefundSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/payments/payment-1/refunds", bytes.NewReader([]byte(tt.body)))
+			req = mux.SetURLVars(req, map[string]string{"id": "payment-1"})
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.createRefundHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+
+			if replayed := w.Header().Get(_idempotentReplayedHeader) == "true"; replayed != tt.replayed {
+				t.Errorf("Expected replayed header %v, got %v", tt.replayed, replayed)
+			}
+		})
+	}
+}
@@ -5,27 +5,53 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
 	"github.com/gorilla/mux"
 
 	"net/http"
+	"net/netip"
 	"sync/atomic"
 	"time"
 )
 
 type ServerConfig struct {
-	Port           int
-	PaymentService ports.PaymentService
-	BalanceService ports.BalanceService
+	Port             int
+	PaymentService   ports.PaymentService
+	BalanceService   ports.BalanceService
+	RefundService    ports.RefundService
+	WalletService    ports.WalletService
+	TransferService  ports.TransferService
+	BillerService    ports.BillerService
+	TokenVerifier    ports.TokenVerifier
+	OperatorVerifier ports.OperatorVerifier
+	AuditService     ports.AuditService
+	Subscriber       ports.Subscriber
+	// TrustedProxies are the proxies whose X-Forwarded-For hops are trusted
+	// when telling the address a request came from.
+	TrustedProxies []netip.Prefix
+	// Instrumentation wraps every route matched by the router, the first
+	// middleware being the outermost.
+	Instrumentation []mux.MiddlewareFunc
 }
 
 type Server struct {
-	port           int
-	logger         *slog.Logger
-	router         *mux.Router
-	handler        http.Handler
-	paymentService ports.PaymentService
-	balanceService ports.BalanceService
+	port             int
+	logger           *slog.Logger
+	router           *mux.Router
+	handler          http.Handler
+	paymentService   ports.PaymentService
+	balanceService   ports.BalanceService
+	refundService    ports.RefundService
+	walletService    ports.WalletService
+	transferService  ports.TransferService
+	billerService    ports.BillerService
+	tokenVerifier    ports.TokenVerifier
+	operatorVerifier ports.OperatorVerifier
+	auditService     ports.AuditService
+	ps               ports.Subscriber
+	trustedProxies   []netip.Prefix
+	instrumentation  []mux.MiddlewareFunc
 }
 
 var (
@@ -34,18 +60,64 @@ var (
 
 func NewServer(cfg *ServerConfig, logger *slog.Logger) *Server {
 	return &Server{
-		port:           cfg.Port,
-		logger:         logger,
-		router:         mux.NewRouter(),
-		paymentService: cfg.PaymentService,
-		balanceService: cfg.BalanceService,
+		port:             cfg.Port,
+		logger:           logger,
+		router:           mux.NewRouter(),
+		paymentService:   cfg.PaymentService,
+		balanceService:   cfg.BalanceService,
+		refundService:    cfg.RefundService,
+		walletService:    cfg.WalletService,
+		transferService:  cfg.TransferService,
+		billerService:    cfg.BillerService,
+		tokenVerifier:    cfg.TokenVerifier,
+		operatorVerifier: cfg.OperatorVerifier,
+		auditService:     cfg.AuditService,
+		ps:               cfg.Subscriber,
+		trustedProxies:   cfg.TrustedProxies,
+		instrumentation:  cfg.Instrumentation,
 	}
 }
 
 func (s *Server) registerHandlers() {
+	s.router.Use(s.tagRequestID)
+	s.router.Use(s.instrumentation...)
+
 	sub := s.router.PathPrefix("/v1").Subrouter()
 	sub.HandleFunc("/health", s.healthHandler).Methods(http.MethodGet)
-	sub.HandleFunc("/payments", s.createPaymentHandler).Methods(http.MethodPost)
+
+	// top-ups fund the wallet of any user, so they take an operator token even
+	// though they are served next to the user routes
+	sub.Handle("/wallets/{user_id}/topups",
+		s.authenticateOperator(s.requireRole(s.createTopUpHandler, domain.RoleOps, domain.RoleFinance))).Methods(http.MethodPost)
+
+	// every other route acts on behalf of the user of the bearer token
+	api := sub.NewRoute().Subrouter()
+	api.Use(s.authenticate)
+	api.HandleFunc("/payments", s.createPaymentHandler).Methods(http.MethodPost)
+	api.HandleFunc("/payments", s.listPaymentsHandler).Methods(http.MethodGet)
+	api.HandleFunc("/payments/{id}", s.getPaymentHandler).Methods(http.MethodGet)
+	api.HandleFunc("/payments/{id}/cancel", s.cancelPaymentHandler).Methods(http.MethodPost)
+	api.HandleFunc("/payments/{id}/history", s.getPaymentHistoryHandler).Methods(http.MethodGet)
+	api.HandleFunc("/payments/{id}/refunds", s.createRefundHandler).Methods(http.MethodPost)
+	api.HandleFunc("/transfers", s.createTransferHandler).Methods(http.MethodPost)
+	api.HandleFunc("/balance", s.getBalanceHandler).Methods(http.MethodGet)
+	api.HandleFunc("/billers", s.listBillersHandler).Methods(http.MethodGet)
+	api.HandleFunc("/wallets", s.createWalletHandler).Methods(http.MethodPost)
+
+	// admin routes act on behalf of the operator of the operator token, each
+	// open to the roles that may perform it
+	readers := []domain.Role{domain.RoleSupportReadOnly, domain.RoleOps, domain.RoleFinance}
+	admin := s.router.PathPrefix("/admin/v1").Subrouter()
+	admin.Use(s.authenticateOperator)
+	admin.Handle("/users/{user_id}/payments", s.requireRole(s.adminListPaymentsHandler, readers...)).Methods(http.MethodGet)
+	admin.Handle("/users/{user_id}/balance", s.requireRole(s.adminGetBalanceHandler, readers...)).Methods(http.MethodGet)
+	admin.Handle("/wallets/{user_id}/freeze", s.requireRole(s.freezeWalletHandler, domain.RoleOps)).Methods(http.MethodPost)
+	admin.Handle("/wallets/{user_id}/unfreeze", s.requireRole(s.unfreezeWalletHandler, domain.RoleOps)).Methods(http.MethodPost)
+	admin.Handle("/wallets/{user_id}/close", s.requireRole(s.closeWalletHandler, domain.RoleOps)).Methods(http.MethodPost)
+	admin.Handle("/wallets/{user_id}/adjustments", s.requireRole(s.adjustBalanceHandler, domain.RoleFinance)).Methods(http.MethodPost)
+	admin.Handle("/payments/{id}/approve", s.requireRole(s.approvePaymentHandler, domain.RoleOps)).Methods(http.MethodPost)
+	admin.Handle("/payments/{id}/reject", s.requireRole(s.rejectPaymentHandler, domain.RoleOps)).Methods(http.MethodPost)
+	admin.Handle("/payments/{id}/status", s.requireRole(s.forcePaymentStatusHandler, domain.RoleOps)).Methods(http.MethodPost)
 }
 
 func (s *Server) start() *http.Server {
@@ -77,7 +149,9 @@ func (s *Server) ListenAndServe(ctx context.Context) (*http.Server, *int32) {
 
 	atomic.StoreInt32(&_healthy, 1)
 
-	//go s.ps.Listen(ctx)
+	if s.ps != nil {
+		go s.ps.Listen(ctx)
+	}
 
 	return srv, &_healthy
 }
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+func (s *Server) createTransferHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot unmarshal request", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.FromUserID = userID
+	req.Currency = req.Currency.OrDefault()
+
+	if err := req.Validate(); err != nil {
+		s.logger.ErrorContext(r.Context(), "validation error", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transfer, replayed, err := s.transferService.Create(r.Context(), req)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot create transfer", slog.Any("error", err))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	if replayed {
+		w.Header().Set(_idempotentReplayedHeader, "true")
+	}
+
+	s.JSONResponseCode(w, r, transfer, http.StatusCreated)
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestServer_createTransferHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userID               string
+		body                 string
+		transfer             *domain.Transfer
+		replayed             bool
+		transferServiceError error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		transferServiceTimes int
+	}{
+		{
+			name:                 "Success - Transfer completed",
+			userID:               "user123",
+			body:                 `{"to_user_id": "user456", "amount": 500, "idempotency_key": "transfer-key"}`,
+			transfer:             &domain.Transfer{ID: "transfer-1", FromUserID: "user123", ToUserID: "user456", Amount: 500},
+			expectedStatusCode:   http.StatusCreated,
+			expectedErrorMessage: "transfer-1",
+			transferServiceTimes: 1,
+		},
+		{
+			name:                 "Success - Replayed request",
+			userID:               "user123",
+			body:                 `{"to_user_id": "user456", "amount": 500, "idempotency_key": "transfer-key"}`,
+			transfer:             &domain.Transfer{ID: "transfer-1", FromUserID: "user123", ToUserID: "user456", Amount: 500},
+			replayed:             true,
+			expectedStatusCode:   http.StatusCreated,
+			transferServiceTimes: 1,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			body:                 `{"to_user_id": "user456", "amount": 500, "idempotency_key": "transfer-key"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+			transferServiceTimes: 0,
+		},
+		{
+			name:                 "Error - Sender overridden by the body",
+			userID:               "user123",
+			body:                 `{"from_user_id": "user456", "to_user_id": "user123", "amount": 500, "idempotency_key": "transfer-key"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "same wallet",
+			transferServiceTimes: 0,
+		},
+		{
+			name:                 "Error - Transfer to self",
+			userID:               "user123",
+			body:                 `{"to_user_id": "user123", "amount": 500, "idempotency_key": "transfer-key"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+			transferServiceTimes: 0,
+		},
+		{
+			name:                 "Error - Insufficient funds",
+			userID:               "user123",
+			body:                 `{"to_user_id": "user456", "amount": 500, "idempotency_key": "transfer-key"}`,
+			transferServiceError: domain.ErrInsufficientFunds,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "INSUFFICIENT_FUNDS",
+			transferServiceTimes: 1,
+		},
+		{
+			name:                 "Error - Receiver wallet not found",
+			userID:               "user123",
+			body:                 `{"to_user_id": "user456", "amount": 500, "idempotency_key": "transfer-key"}`,
+			transferServiceError: domain.ErrWalletNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: "WALLET_NOT_FOUND",
+			transferServiceTimes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockTransferSvc := mocks.NewMockTransferService(ctrl)
+			mockTransferSvc.EXPECT().Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ any, request domain.CreateTransferRequest) (*domain.Transfer, bool, error) {
+					if request.FromUserID != tt.userID {
+						t.Errorf("Unexpected request %+v", request)
+					}
+					return tt.transfer, tt.replayed, tt.transferServiceError
+				}).Times(tt.transferServiceTimes)
+
+			server := &Server{
+				logger:          slog.Default(),
+				transferService: mockTransferSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/transfers", bytes.NewReader([]byte(tt.body)))
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.createTransferHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+
+			if replayed := w.Header().Get(_idempotentReplayedHeader) == "true"; replayed != tt.replayed {
+				t.Errorf("Expected replayed header %v, got %v", tt.replayed, replayed)
+			}
+		})
+	}
+}
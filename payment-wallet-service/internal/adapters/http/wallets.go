@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) createWalletHandler(w http.ResponseWriter, r *http.Request) {
+	userID := userIDFromContext(r.Context())
+	if userID == "" {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// the body is optional, wallets are opened in the default currency
+	var req struct {
+		Currency string `json:"currency"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.logger.ErrorContext(r.Context(), "cannot unmarshal request", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	currency, err := domain.ParseCurrency(req.Currency)
+	if err != nil {
+		s.ErrorResponse(w, r, CodeInvalidRequest, "currency: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	wallet, err := s.walletService.Create(r.Context(), userID, currency)
+	if err != nil {
+		if !errors.Is(err, domain.ErrWalletAlreadyExists) {
+			s.logger.ErrorContext(r.Context(), "cannot create wallet", slog.Any("error", err))
+		}
+
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.JSONResponseCode(w, r, wallet, http.StatusCreated)
+}
+
+func (s *Server) freezeWalletHandler(w http.ResponseWriter, r *http.Request) {
+	s.changeWalletStatus(w, r, "freeze", s.walletService.Freeze)
+}
+
+func (s *Server) unfreezeWalletHandler(w http.ResponseWriter, r *http.Request) {
+	s.changeWalletStatus(w, r, "unfreeze", s.walletService.Unfreeze)
+}
+
+func (s *Server) closeWalletHandler(w http.ResponseWriter, r *http.Request) {
+	s.changeWalletStatus(w, r, "close", s.walletService.Close)
+}
+
+// changeWalletStatus serves the admin wallet actions, which apply to every
+// currency wallet of the user, on behalf of the authenticated operator.
+func (s *Server) changeWalletStatus(w http.ResponseWriter, r *http.Request, action string,
+	change func(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error)) {
+	operator, ok := operatorFromContext(r.Context())
+	if !ok {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID := mux.Vars(r)["user_id"]
+	wallets, err := change(r.Context(), operator, userID)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot change wallet status",
+			slog.Any("error", err),
+			slog.String("action", action),
+			slog.String("user_id", userID))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	s.logger.InfoContext(r.Context(), "wallet status changed by admin",
+		slog.String("action", action),
+		slog.String("operator_id", operator.ID),
+		slog.String("user_id", userID),
+		slog.String("status", string(wallets[0].Status)))
+
+	s.JSONResponse(w, r, wallets)
+}
+
+// createTopUpHandler credits external funds to the wallet of any user on
+// behalf of the authenticated operator. Users cannot top up their own wallets,
+// since nothing backs the credited money but the operator.
+func (s *Server) createTopUpHandler(w http.ResponseWriter, r *http.Request) {
+	operator, ok := operatorFromContext(r.Context())
+	if !ok {
+		s.ErrorResponse(w, r, CodeUnauthorized, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req domain.CreateTopUpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot unmarshal request", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.UserID = mux.Vars(r)["user_id"]
+	req.Currency = req.Currency.OrDefault()
+
+	if err := req.Validate(); err != nil {
+		s.logger.ErrorContext(r.Context(), "validation error", slog.Any("error", err))
+		s.ErrorResponse(w, r, CodeInvalidRequest, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	topUp, replayed, err := s.walletService.TopUp(r.Context(), operator, req)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "cannot top up wallet",
+			slog.Any("error", err),
+			slog.String("user_id", req.UserID))
+		s.DomainErrorResponse(w, r, err)
+		return
+	}
+
+	if replayed {
+		w.Header().Set(_idempotentReplayedHeader, "true")
+	}
+
+	s.JSONResponseCode(w, r, topUp, http.StatusCreated)
+}
@@ -0,0 +1,364 @@
+package http
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/gorilla/mux"
+	"go.uber.org/mock/gomock"
+)
+
+func TestServer_createTopUpHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		operatorID           string
+		walletUserID         string
+		body                 string
+		topUp                *domain.TopUp
+		replayed             bool
+		walletServiceError   error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		walletServiceTimes   int
+	}{
+		{
+			name:                 "Success - Wallet credited",
+			operatorID:           "operator-1",
+			walletUserID:         "user123",
+			body:                 `{"amount": 5000, "idempotency_key": "topup-key"}`,
+			topUp:                &domain.TopUp{ID: "topup-1", UserID: "user123", Amount: 5000},
+			expectedStatusCode:   http.StatusCreated,
+			expectedErrorMessage: "topup-1",
+			walletServiceTimes:   1,
+		},
+		{
+			name:               "Success - Replayed request",
+			operatorID:         "operator-1",
+			walletUserID:       "user123",
+			body:               `{"amount": 5000, "idempotency_key": "topup-key"}`,
+			topUp:              &domain.TopUp{ID: "topup-1", UserID: "user123", Amount: 5000},
+			replayed:           true,
+			expectedStatusCode: http.StatusCreated,
+			walletServiceTimes: 1,
+		},
+		{
+			name:                 "Error - Missing operator",
+			walletUserID:         "user123",
+			body:                 `{"amount": 5000, "idempotency_key": "topup-key"}`,
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+			walletServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Invalid JSON",
+			operatorID:           "operator-1",
+			walletUserID:         "user123",
+			body:                 `{"amount": `,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+			walletServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Negative amount",
+			operatorID:           "operator-1",
+			walletUserID:         "user123",
+			body:                 `{"amount": -5, "idempotency_key": "topup-key"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+			walletServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Wallet not found",
+			operatorID:           "operator-1",
+			walletUserID:         "user123",
+			body:                 `{"amount": 5000, "idempotency_key": "topup-key"}`,
+			walletServiceError:   domain.ErrWalletNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: "WALLET_NOT_FOUND",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Error - Amount above the cap",
+			operatorID:           "operator-1",
+			walletUserID:         "user123",
+			body:                 `{"amount": 500000000, "idempotency_key": "topup-key"}`,
+			walletServiceError:   domain.ErrTopUpLimitExceeded,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "TOP_UP_LIMIT_EXCEEDED",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Error - Idempotency key reused",
+			operatorID:           "operator-1",
+			walletUserID:         "user123",
+			body:                 `{"amount": 5000, "idempotency_key": "topup-key"}`,
+			walletServiceError:   domain.ErrIdempotencyKeyReused,
+			expectedStatusCode:   http.StatusUnprocessableEntity,
+			expectedErrorMessage: "IDEMPOTENCY_KEY_REUSED",
+			walletServiceTimes:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWalletSvc := mocks.NewMockWalletService(ctrl)
+			mockWalletSvc.EXPECT().TopUp(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ any, operator domain.Operator, request domain.CreateTopUpRequest) (*domain.TopUp, bool, error) {
+					if operator.ID != tt.operatorID || request.UserID != tt.walletUserID {
+						t.Errorf("Unexpected request %+v by %+v", request, operator)
+					}
+					return tt.topUp, tt.replayed, tt.walletServiceError
+				}).Times(tt.walletServiceTimes)
+
+			server := &Server{
+				logger:        slog.Default(),
+				walletService: mockWalletSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/wallets/"+tt.walletUserID+"/topups", bytes.NewReader([]byte(tt.body)))
+			req = mux.SetURLVars(req, map[string]string{"user_id": tt.walletUserID})
+			if tt.operatorID != "" {
+				req = req.WithContext(withOperator(req.Context(), domain.Operator{ID: tt.operatorID, Roles: []domain.Role{domain.RoleOps}}))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.createTopUpHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			if tt.expectedErrorMessage != "" {
+				body := w.Body.String()
+				if !strings.Contains(body, tt.expectedErrorMessage) {
+					t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+				}
+			}
+
+			if replayed := w.Header().Get(_idempotentReplayedHeader) == "true"; replayed != tt.replayed {
+				t.Errorf("Expected replayed header %v, got %v", tt.replayed, replayed)
+			}
+		})
+	}
+}
+
+func TestServer_createWalletHandler(t *testing.T) {
+	tests := []struct {
+		name                 string
+		userID               string
+		body                 string
+		currency             domain.Currency
+		walletServiceError   error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		walletServiceTimes   int
+	}{
+		{
+			name:                 "Success - Wallet created",
+			userID:               "user123",
+			expectedStatusCode:   http.StatusCreated,
+			expectedErrorMessage: "ACTIVE",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Success - Wallet in another currency",
+			userID:               "user123",
+			body:                 `{"currency":"USD"}`,
+			currency:             domain.CurrencyUSD,
+			expectedStatusCode:   http.StatusCreated,
+			expectedErrorMessage: "USD",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Error - Unsupported currency",
+			userID:               "user123",
+			body:                 `{"currency":"XYZ"}`,
+			expectedStatusCode:   http.StatusBadRequest,
+			expectedErrorMessage: "INVALID_REQUEST",
+			walletServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Missing User ID",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+			walletServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Wallet already exists",
+			userID:               "user123",
+			walletServiceError:   domain.ErrWalletAlreadyExists,
+			expectedStatusCode:   http.StatusConflict,
+			expectedErrorMessage: "WALLET_ALREADY_EXISTS",
+			walletServiceTimes:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWalletSvc := mocks.NewMockWalletService(ctrl)
+
+			var wallet *domain.Balance
+			if tt.walletServiceError == nil {
+				wallet = &domain.Balance{UserID: tt.userID, Currency: tt.currency.OrDefault(), Status: domain.WalletActive}
+			}
+			mockWalletSvc.EXPECT().Create(gomock.Any(), tt.userID, tt.currency.OrDefault()).
+				Return(wallet, tt.walletServiceError).Times(tt.walletServiceTimes)
+
+			server := &Server{
+				logger:        slog.Default(),
+				walletService: mockWalletSvc,
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/wallets", strings.NewReader(tt.body))
+			if tt.userID != "" {
+				req = req.WithContext(withUserID(req.Context(), tt.userID))
+			}
+
+			w := httptest.NewRecorder()
+
+			server.createWalletHandler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.expectedErrorMessage) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+			}
+		})
+	}
+}
+
+func TestServer_changeWalletStatusHandlers(t *testing.T) {
+	tests := []struct {
+		name                 string
+		operatorID           string
+		action               string
+		status               domain.WalletStatus
+		walletServiceError   error
+		expectedStatusCode   int
+		expectedErrorMessage string
+		walletServiceTimes   int
+	}{
+		{
+			name:                 "Success - Wallet frozen",
+			operatorID:           "operator-1",
+			action:               "freeze",
+			status:               domain.WalletFrozen,
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: "FROZEN",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Success - Wallet unfrozen",
+			operatorID:           "operator-1",
+			action:               "unfreeze",
+			status:               domain.WalletActive,
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: "ACTIVE",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Success - Wallet closed",
+			operatorID:           "operator-1",
+			action:               "close",
+			status:               domain.WalletClosed,
+			expectedStatusCode:   http.StatusOK,
+			expectedErrorMessage: "CLOSED",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Error - Missing operator",
+			action:               "freeze",
+			expectedStatusCode:   http.StatusUnauthorized,
+			expectedErrorMessage: "UNAUTHORIZED",
+			walletServiceTimes:   0,
+		},
+		{
+			name:                 "Error - Close with reserved funds",
+			operatorID:           "operator-1",
+			action:               "close",
+			walletServiceError:   domain.ErrWalletHasReservedFunds,
+			expectedStatusCode:   http.StatusConflict,
+			expectedErrorMessage: "WALLET_HAS_RESERVED_FUNDS",
+			walletServiceTimes:   1,
+		},
+		{
+			name:                 "Error - Wallet not found",
+			operatorID:           "operator-1",
+			action:               "unfreeze",
+			walletServiceError:   domain.ErrWalletNotFound,
+			expectedStatusCode:   http.StatusNotFound,
+			expectedErrorMessage: "WALLET_NOT_FOUND",
+			walletServiceTimes:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWalletSvc := mocks.NewMockWalletService(ctrl)
+
+			var wallets []domain.Balance
+			if tt.walletServiceError == nil {
+				wallets = []domain.Balance{
+					{UserID: "user123", Currency: domain.CurrencyARS, Status: tt.status},
+					{UserID: "user123", Currency: domain.CurrencyUSD, Status: tt.status},
+				}
+			}
+
+			server := &Server{
+				logger:        slog.Default(),
+				walletService: mockWalletSvc,
+			}
+
+			operator := domain.Operator{ID: tt.operatorID, Roles: []domain.Role{domain.RoleOps}}
+
+			var (
+				handler http.HandlerFunc
+				call    *gomock.Call
+			)
+			switch tt.action {
+			case "freeze":
+				handler = server.freezeWalletHandler
+				call = mockWalletSvc.EXPECT().Freeze(gomock.Any(), operator, "user123")
+			case "unfreeze":
+				handler = server.unfreezeWalletHandler
+				call = mockWalletSvc.EXPECT().Unfreeze(gomock.Any(), operator, "user123")
+			case "close":
+				handler = server.closeWalletHandler
+				call = mockWalletSvc.EXPECT().Close(gomock.Any(), operator, "user123")
+			}
+			call.Return(wallets, tt.walletServiceError).Times(tt.walletServiceTimes)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/v1/wallets/user123/"+tt.action, nil)
+			req = mux.SetURLVars(req, map[string]string{"user_id": "user123"})
+			if tt.operatorID != "" {
+				req = req.WithContext(withOperator(req.Context(), operator))
+			}
+
+			w := httptest.NewRecorder()
+
+			handler(w, req)
+
+			if w.Code != tt.expectedStatusCode {
+				t.Errorf("Expected status code %d, got %d", tt.expectedStatusCode, w.Code)
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, tt.expectedErrorMessage) {
+				t.Errorf("Expected error message to contain '%s', got '%s'", tt.expectedErrorMessage, body)
+			}
+		})
+	}
+}
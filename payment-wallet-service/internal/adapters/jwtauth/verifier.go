@@ -0,0 +1,391 @@
+package jwtauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	_defaultRefreshInterval = time.Minute
+	_defaultFetchTimeout    = 10 * time.Second
+	_p256CoordinateSize     = 32
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrUnknownKey   = errors.New("unknown signing key")
+	ErrNoKeys       = errors.New("no verification keys configured")
+	ErrNoIssuer     = errors.New("no token issuer configured")
+	ErrNoAudience   = errors.New("no token audience configured")
+)
+
+type Config struct {
+	Logger   *slog.Logger
+	Issuer   string
+	Audience string
+	// StaticKeys are PEM encoded public keys by key ID. When set they are the
+	// only keys used, which allows running without an identity provider.
+	StaticKeys map[string]string
+	// JWKSFile and JWKSURL are where the JSON Web Key Set is read from when
+	// there are no static keys, the file taking precedence.
+	JWKSFile string
+	JWKSURL  string
+	// RefreshInterval is the minimum time between reloads of the JWKS URL,
+	// which happen when a token is signed with a key not seen yet.
+	RefreshInterval time.Duration
+	// Leeway is the clock skew tolerated when checking expiry.
+	Leeway     time.Duration
+	HTTPClient *http.Client
+}
+
+// Verifier validates RS256 and ES256 bearer tokens, enforcing their issuer,
+// audience and expiry, and returns the subject they were issued to.
+type Verifier struct {
+	logger          *slog.Logger
+	parser          *jwt.Parser
+	load            func(ctx context.Context) (map[string]crypto.PublicKey, error)
+	refreshable     bool
+	refreshInterval time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	refreshedAt time.Time
+}
+
+// NewVerifier requires an issuer and an audience, since the parser skips the
+// checks of those left empty and would accept tokens meant for anyone.
+func NewVerifier(ctx context.Context, config Config) (*Verifier, error) {
+	if config.Issuer == "" {
+		return nil, ErrNoIssuer
+	}
+	if config.Audience == "" {
+		return nil, ErrNoAudience
+	}
+
+	verifier := &Verifier{
+		logger: config.Logger,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+			jwt.WithIssuer(config.Issuer),
+			jwt.WithAudience(config.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(config.Leeway),
+		),
+		refreshInterval: config.RefreshInterval,
+	}
+
+	if verifier.refreshInterval <= 0 {
+		verifier.refreshInterval = _defaultRefreshInterval
+	}
+
+	switch {
+	case len(config.StaticKeys) > 0:
+		verifier.load = func(context.Context) (map[string]crypto.PublicKey, error) {
+			return parseStaticKeys(config.StaticKeys)
+		}
+	case config.JWKSFile != "":
+		verifier.load = func(context.Context) (map[string]crypto.PublicKey, error) {
+			data, err := os.ReadFile(config.JWKSFile)
+			if err != nil {
+				return nil, err
+			}
+			return parseJWKS(data)
+		}
+	case config.JWKSURL != "":
+		client := config.HTTPClient
+		if client == nil {
+			client = &http.Client{Timeout: _defaultFetchTimeout}
+		}
+		verifier.load = func(ctx context.Context) (map[string]crypto.PublicKey, error) {
+			return fetchJWKS(ctx, client, config.JWKSURL)
+		}
+		verifier.refreshable = true
+	default:
+		return nil, ErrNoKeys
+	}
+
+	if err := verifier.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return verifier, nil
+}
+
+// operatorClaims are the claims of admin tokens, which carry the roles of the
+// operator.
+type operatorClaims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles"`
+}
+
+// Verify returns the subject of a valid token.
+func (v *Verifier) Verify(ctx context.Context, token string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	if err := v.parse(ctx, token, claims); err != nil {
+		return "", err
+	}
+
+	return claims.Subject, nil
+}
+
+// VerifyOperator returns the operator of a valid admin token, its subject with
+// the roles of the roles claim. Roles the admin API does not know are dropped.
+func (v *Verifier) VerifyOperator(ctx context.Context, token string) (*domain.Operator, error) {
+	claims := &operatorClaims{}
+	if err := v.parse(ctx, token, claims); err != nil {
+		return nil, err
+	}
+
+	operator := &domain.Operator{ID: claims.Subject}
+	for _, role := range claims.Roles {
+		switch domain.Role(role) {
+		case domain.RoleSupportReadOnly, domain.RoleOps, domain.RoleFinance:
+			operator.Roles = append(operator.Roles, domain.Role(role))
+		}
+	}
+
+	return operator, nil
+}
+
+func (v *Verifier) parse(ctx context.Context, token string, claims jwt.Claims) error {
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if subject, _ := claims.GetSubject(); subject == "" {
+		return fmt.Errorf("%w: token has no subject", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// key returns the key with the given ID. Tokens without a key ID are accepted
+// only while there is a single key. Unknown keys of a JWKS URL trigger a reload,
+// at most once per refresh interval, so rotated keys are picked up.
+func (v *Verifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+
+	if !v.refreshable || !v.refreshDue() {
+		return nil, ErrUnknownKey
+	}
+
+	if err := v.refresh(ctx); err != nil {
+		v.logger.ErrorContext(ctx, "failed to refresh JWKS", slog.Any("error", err))
+		return nil, ErrUnknownKey
+	}
+
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (v *Verifier) lookup(kid string) (crypto.PublicKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+func (v *Verifier) refreshDue() bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return time.Since(v.refreshedAt) >= v.refreshInterval
+}
+
+func (v *Verifier) refresh(ctx context.Context) error {
+	keys, err := v.load(ctx)
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return ErrNoKeys
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.keys = keys
+	v.refreshedAt = time.Now()
+
+	return nil
+}
+
+func parseStaticKeys(static map[string]string) (map[string]crypto.PublicKey, error) {
+	keys := make(map[string]crypto.PublicKey, len(static))
+	for kid, encoded := range static {
+		block, _ := pem.Decode([]byte(encoded))
+		if block == nil {
+			return nil, fmt.Errorf("static key %s is not PEM encoded", kid)
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("static key %s: %w", kid, err)
+		}
+
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			keys[kid] = k
+		case *ecdsa.PublicKey:
+			if k.Curve != elliptic.P256() {
+				return nil, fmt.Errorf("static key %s: only P-256 curves are supported", kid)
+			}
+			keys[kid] = k
+		default:
+			return nil, fmt.Errorf("static key %s: unsupported key type %T", kid, key)
+		}
+	}
+
+	return keys, nil
+}
+
+func fetchJWKS(ctx context.Context, client *http.Client, url string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseJWKS(data)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS reads the signature keys of a JSON Web Key Set. Keys of other
+// types or uses are skipped, since identity providers publish them together.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parsing JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch {
+		case jwk.Kty == "RSA":
+			key, err = rsaKey(jwk)
+		case jwk.Kty == "EC" && jwk.Crv == "P-256":
+			key, err = p256Key(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %s: %w", jwk.Kid, err)
+		}
+
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func rsaKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA exponent")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
+
+func p256Key(jwk jsonWebKey) (*ecdsa.PublicKey, error) {
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(x) > _p256CoordinateSize || len(y) > _p256CoordinateSize {
+		return nil, errors.New("invalid P-256 coordinates")
+	}
+
+	// uncompressed point: 0x04 followed by both coordinates, left padded
+	point := make([]byte, 1+2*_p256CoordinateSize)
+	point[0] = 4
+	copy(point[1+_p256CoordinateSize-len(x):], x)
+	copy(point[1+2*_p256CoordinateSize-len(y):], y)
+
+	return ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+}
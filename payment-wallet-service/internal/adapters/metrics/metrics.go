@@ -0,0 +1,165 @@
+package metrics
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	_namespace   = "payment_wallet"
+	_defaultPath = "/metrics"
+)
+
+type Config struct {
+	Logger *slog.Logger
+	// Port is where the metrics are served, zero keeps them in memory only.
+	Port int
+	Path string
+	// Pool is the connection pool whose stats are exported, if any.
+	Pool *pgxpool.Pool
+}
+
+// Metrics owns the Prometheus registry of the service and the collectors of
+// its HTTP, database, publishing and payment metrics.
+type Metrics struct {
+	logger   *slog.Logger
+	port     int
+	path     string
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	httpInFlight prometheus.Gauge
+
+	published *prometheus.CounterVec
+
+	payments        *prometheus.CounterVec
+	paymentRefusals *prometheus.CounterVec
+	paymentAmounts  *prometheus.HistogramVec
+
+	mu  sync.Mutex
+	srv *http.Server
+}
+
+func NewMetrics(config Config) *Metrics {
+	path := config.Path
+	if path == "" {
+		path = _defaultPath
+	}
+
+	m := &Metrics{
+		logger:   config.Logger,
+		port:     config.Port,
+		path:     path,
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests served, by method, route template and status code.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: _namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests, by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		httpInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: _namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests being served.",
+		}),
+		published: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Name:      "messages_published_total",
+			Help:      "Outbox messages published to the broker, by event type and result.",
+		}, []string{"event_type", "result"}),
+		payments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Name:      "payments_total",
+			Help:      "Payments that reached each status, by status and currency.",
+		}, []string{"status", "currency"}),
+		paymentRefusals: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: _namespace,
+			Name:      "payment_refusals_total",
+			Help:      "Payments refused before being created, by reason.",
+		}, []string{"reason"}),
+		paymentAmounts: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: _namespace,
+			Name:      "payment_amount",
+			Help:      "Amount of the created payments in the major unit of their currency.",
+			// from one unit up to four million, enough for both dollars and pesos
+			Buckets: prometheus.ExponentialBuckets(1, 4, 12),
+		}, []string{"currency"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.httpInFlight,
+		m.published,
+		m.payments,
+		m.paymentRefusals,
+		m.paymentAmounts,
+	)
+
+	if config.Pool != nil {
+		m.registry.MustRegister(newPoolCollector(config.Pool))
+	}
+
+	return m
+}
+
+// Handler serves the metrics of the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ListenAndServe serves the metrics on their own port, apart from the API so
+// they are never exposed with it. It does nothing when no port is configured.
+func (m *Metrics) ListenAndServe() {
+	if m.port == 0 {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(m.path, m.Handler())
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", m.port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	m.mu.Lock()
+	m.srv = srv
+	m.mu.Unlock()
+
+	go func() {
+		m.logger.Info("starting metrics server", slog.String("addr", srv.Addr), slog.String("path", m.path))
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			m.logger.Error("metrics server crashed", slog.Any("error", err))
+		}
+	}()
+}
+
+// Close stops the metrics server, if it was started.
+func (m *Metrics) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.srv == nil {
+		return nil
+	}
+
+	return m.srv.Close()
+}
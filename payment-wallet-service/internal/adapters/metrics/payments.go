@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"math"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+// PaymentCreated counts the payment in its initial status and observes its
+// amount.
+func (m *Metrics) PaymentCreated(payment domain.Payment) {
+	currency := string(payment.Currency)
+
+	m.payments.WithLabelValues(string(payment.Status), currency).Inc()
+	m.paymentAmounts.WithLabelValues(currency).Observe(float64(payment.Amount) / math.Pow10(payment.Currency.Exponent()))
+}
+
+func (m *Metrics) PaymentRefused(reason string) {
+	m.paymentRefusals.WithLabelValues(reason).Inc()
+}
+
+// PaymentStatusChanged counts the payment in the status it moved to.
+func (m *Metrics) PaymentStatusChanged(payment domain.Payment) {
+	m.payments.WithLabelValues(string(payment.Status), string(payment.Currency)).Inc()
+}
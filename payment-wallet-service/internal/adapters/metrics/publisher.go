@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+)
+
+const (
+	_resultSuccess = "success"
+	_resultFailure = "failure"
+)
+
+type publisher struct {
+	next    ports.Publisher
+	metrics *Metrics
+}
+
+// Publisher wraps next counting the messages it publishes and fails to.
+func (m *Metrics) Publisher(next ports.Publisher) ports.Publisher {
+	return &publisher{next: next, metrics: m}
+}
+
+func (p *publisher) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	err := p.next.Publish(ctx, message)
+
+	result := _resultSuccess
+	if err != nil {
+		result = _resultFailure
+	}
+	p.metrics.published.WithLabelValues(message.EventType, result).Inc()
+
+	return err
+}
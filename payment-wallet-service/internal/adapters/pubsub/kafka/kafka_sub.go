@@ -0,0 +1,157 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	_initialBackoff = 200 * time.Millisecond
+	_maxBackoff     = 30 * time.Second
+)
+
+type Config struct {
+	Logger         *slog.Logger
+	Brokers        []string
+	Topic          string
+	GroupID        string
+	PaymentService ports.PaymentService
+}
+
+type Sub struct {
+	logger         *slog.Logger
+	reader         *kafka.Reader
+	paymentService ports.PaymentService
+	done           chan struct{}
+	closeOnce      sync.Once
+}
+
+func NewKafkaSub(config Config) *Sub {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: config.Brokers,
+		Topic:   config.Topic,
+		GroupID: config.GroupID,
+	})
+
+	return &Sub{
+		logger:         config.Logger,
+		reader:         reader,
+		paymentService: config.PaymentService,
+		done:           make(chan struct{}),
+	}
+}
+
+// Listen consumes PaymentResult events until the context is cancelled or the
+// subscriber is closed. Offsets are committed only after the event was applied,
+// so a crash in between redelivers it and the payment status makes it a no-op.
+// Failed fetches are retried with an exponential backoff, so a broker outage
+// does not spin the loop.
+func (s *Sub) Listen(ctx context.Context) {
+	s.logger.InfoContext(ctx, "listening payment results", slog.String("topic", s.reader.Config().Topic))
+
+	backoff := _initialBackoff
+	for {
+		msg, err := s.reader.FetchMessage(ctx)
+		if err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				s.logger.InfoContext(ctx, "payment results subscriber stopped")
+				return
+			}
+
+			s.logger.ErrorContext(ctx, "failed to fetch message, retrying",
+				slog.Any("error", err),
+				slog.Duration("backoff", backoff))
+
+			if !s.wait(ctx, backoff) {
+				s.logger.InfoContext(ctx, "payment results subscriber stopped")
+				return
+			}
+
+			backoff = min(backoff*2, _maxBackoff)
+			continue
+		}
+		backoff = _initialBackoff
+
+		if !s.process(ctx, msg) {
+			return
+		}
+
+		if err = s.reader.CommitMessages(ctx, msg); err != nil {
+			s.logger.ErrorContext(ctx, "failed to commit message",
+				slog.Any("error", err),
+				slog.Int64("offset", msg.Offset))
+		}
+	}
+}
+
+// process applies a single message, retrying transient failures with an
+// exponential backoff. It returns false when the context was cancelled before
+// the message could be applied.
+func (s *Sub) process(ctx context.Context, msg kafka.Message) bool {
+	var event domain.PaymentResultEvent
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		s.logger.ErrorContext(ctx, "failed to unmarshal payment result, discarding",
+			slog.Any("error", err),
+			slog.Int64("offset", msg.Offset))
+		return true
+	}
+
+	ctx = correlation.WithPaymentID(ctx, event.TransactionID)
+
+	backoff := _initialBackoff
+	for {
+		err := s.paymentService.Update(ctx, event)
+		if err == nil {
+			return true
+		}
+
+		if errors.Is(err, domain.ErrInvalidPaymentResult) ||
+			errors.Is(err, domain.ErrPaymentNotFound) ||
+			errors.Is(err, domain.ErrReservationNotFound) {
+			s.logger.ErrorContext(ctx, "payment result cannot be applied, discarding",
+				slog.Any("error", err))
+			return true
+		}
+
+		s.logger.WarnContext(ctx, "failed to apply payment result, retrying",
+			slog.Any("error", err),
+			slog.Duration("backoff", backoff))
+
+		if !s.wait(ctx, backoff) {
+			return false
+		}
+
+		backoff = min(backoff*2, _maxBackoff)
+	}
+}
+
+// wait sleeps for the backoff. It returns false when the context was cancelled
+// or the subscriber closed in the meantime.
+func (s *Sub) wait(ctx context.Context, backoff time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.done:
+		return false
+	case <-time.After(backoff):
+		return true
+	}
+}
+
+func (s *Sub) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+
+	return s.reader.Close()
+}
@@ -2,44 +2,96 @@ package rabbit
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
-	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
 	"github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	_instrumentationName = "github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/pubsub/rabbit"
+	_requestIDHeader     = "X-Request-ID"
+	_confirmTimeout      = 5 * time.Second
+)
+
+var (
+	errPublisherClosed = errors.New("rabbit publisher closed")
+	errMessageNacked   = errors.New("message not confirmed by the broker")
+	errNoRoutingKey    = errors.New("no routing key for event type")
 )
 
 type Config struct {
-	Logger     *slog.Logger
-	RabbitURL  string
-	Exchange   string
-	RoutingKey string
+	Logger    *slog.Logger
+	RabbitURL string
+	Exchange  string
+	// RoutingKeys are the routing keys by event type. Events without one are
+	// not published, so a new event cannot land in the queue of another.
+	RoutingKeys map[string]string
 }
 
+// Pub publishes on a channel in confirm mode, so a message is only reported
+// as published once the broker acknowledged it. The connection is dialed again
+// on the next publish after the broker closed it.
 type Pub struct {
-	logger     *slog.Logger
-	conn       *amqp091.Connection
-	channel    *amqp091.Channel
-	exchange   string
-	routingKey string
+	logger      *slog.Logger
+	tracer      trace.Tracer
+	rabbitURL   string
+	exchange    string
+	routingKeys map[string]string
+
+	mu      sync.Mutex
+	conn    *amqp091.Connection
+	channel *amqp091.Channel
+	closed  bool
 }
 
 func NewRabbitPub(config Config) (*Pub, error) {
-	conn, err := amqp091.Dial(config.RabbitURL)
-	if err != nil {
+	pub := &Pub{
+		logger:      config.Logger,
+		tracer:      otel.Tracer(_instrumentationName),
+		rabbitURL:   config.RabbitURL,
+		exchange:    config.Exchange,
+		routingKeys: config.RoutingKeys,
+	}
+
+	if err := pub.connect(); err != nil {
 		return nil, err
 	}
 
+	return pub, nil
+}
+
+// connect dials the broker and opens a channel in confirm mode with the
+// exchange declared. It must be called with the lock held.
+func (p *Pub) connect() error {
+	conn, err := amqp091.Dial(p.rabbitURL)
+	if err != nil {
+		return err
+	}
+
 	channel, err := conn.Channel()
 	if err != nil {
 		_ = conn.Close()
-		return nil, err
+		return err
+	}
+
+	if err = channel.Confirm(false); err != nil {
+		_ = channel.Close()
+		_ = conn.Close()
+		return err
 	}
 
 	err = channel.ExchangeDeclare(
-		config.Exchange,
+		p.exchange,
 		"direct",
 		true,
 		false,
@@ -50,55 +102,179 @@ func NewRabbitPub(config Config) (*Pub, error) {
 	if err != nil {
 		_ = channel.Close()
 		_ = conn.Close()
+		return err
+	}
+
+	p.conn = conn
+	p.channel = channel
+
+	return nil
+}
+
+// openChannel returns the channel to publish on, reconnecting when the broker
+// closed the previous one. It must be called with the lock held.
+func (p *Pub) openChannel(ctx context.Context) (*amqp091.Channel, error) {
+	if p.closed {
+		return nil, errPublisherClosed
+	}
+
+	if p.channel != nil && !p.channel.IsClosed() {
+		return p.channel, nil
+	}
+
+	p.disconnect()
+	p.logger.WarnContext(ctx, "rabbit channel closed, reconnecting")
+	if err := p.connect(); err != nil {
 		return nil, err
 	}
 
-	return &Pub{
-		logger:     config.Logger,
-		conn:       conn,
-		channel:    channel,
-		exchange:   config.Exchange,
-		routingKey: config.RoutingKey,
-	}, nil
+	return p.channel, nil
+}
+
+// disconnect closes the channel and the connection. It must be called with the
+// lock held.
+func (p *Pub) disconnect() error {
+	if p.channel != nil {
+		_ = p.channel.Close()
+		p.channel = nil
+	}
+
+	var err error
+	if p.conn != nil {
+		if !p.conn.IsClosed() {
+			err = p.conn.Close()
+		}
+		p.conn = nil
+	}
+
+	return err
 }
 
-func (p *Pub) Publish(ctx context.Context, event *domain.PaymentInitiatedEvent) error {
-	body, err := json.Marshal(event)
+// Publish sends the message, with the routing key of its event type, in a
+// producer span that continues the trace which
+// wrote it, linked to the trace of the caller, and injects the span context in
+// the message headers for the consumer to continue the trace. The ID of the
+// request that wrote the message goes in the X-Request-ID header. It returns
+// once the broker confirmed the message, or with an error when it did not.
+func (p *Pub) Publish(ctx context.Context, message domain.OutboxMessage) error {
+	routingKey, ok := p.routingKeys[message.EventType]
+	if !ok {
+		err := fmt.Errorf("%w %s", errNoRoutingKey, message.EventType)
+		p.logger.ErrorContext(ctx, "failed to publish message", "error", err)
+
+		return err
+	}
+
+	parent := ctx
+	if len(message.TraceContext) > 0 {
+		parent = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(message.TraceContext))
+	}
+
+	ctx, span := p.tracer.Start(parent, p.exchange+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithAttributes(
+			semconv.MessagingSystemRabbitMQ,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(p.exchange),
+			semconv.MessagingRabbitMQDestinationRoutingKey(routingKey),
+			semconv.MessagingMessageID(message.ID),
+		),
+	)
+	defer span.End()
+
+	headers := amqp091.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	if message.RequestID != "" {
+		headers[_requestIDHeader] = message.RequestID
+	}
+
+	err := p.publish(ctx, routingKey, headers, message)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		p.logger.ErrorContext(ctx, "failed to publish message", "error", err)
+
+		return err
+	}
+
+	p.logger.InfoContext(ctx, "message published successfully",
+		"routing_key", routingKey,
+		"message_id", message.ID,
+		"event_type", message.EventType)
+	return nil
+}
+
+// publish sends the message and waits for the broker to confirm it.
+func (p *Pub) publish(ctx context.Context, routingKey string, headers amqp091.Table, message domain.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	channel, err := p.openChannel(ctx)
 	if err != nil {
-		p.logger.Error("failed to marshal event", "error", err)
 		return err
 	}
 
-	err = p.channel.PublishWithContext(
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(
 		ctx,
 		p.exchange,
-		p.routingKey,
+		routingKey,
 		false,
 		false,
 		amqp091.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			Timestamp:   time.Now(),
-			MessageId:   uidgen.NewUUID(),
+			Headers:      headers,
+			ContentType:  "application/json",
+			DeliveryMode: amqp091.Persistent,
+			Body:         message.Payload,
+			Timestamp:    time.Now(),
+			MessageId:    message.ID,
+			Type:         message.EventType,
 		},
 	)
-
 	if err != nil {
-		p.logger.Error("failed to publish message", "error", err)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, _confirmTimeout)
+	defer cancel()
 
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
 		return err
 	}
+	if !acked {
+		return errMessageNacked
+	}
 
-	p.logger.Info("message published successfully", "routing_key", p.routingKey)
 	return nil
 }
 
 func (p *Pub) Close() error {
-	if p.channel != nil {
-		_ = p.channel.Close()
-	}
-	if p.conn != nil {
-		return p.conn.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	return p.disconnect()
+}
+
+// headerCarrier lets the propagator read and write AMQP message headers.
+type headerCarrier amqp091.Table
+
+func (c headerCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
 	}
-	return nil
+
+	return keys
 }
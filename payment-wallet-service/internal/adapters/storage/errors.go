@@ -1,7 +0,0 @@
-package storage
-
-import "errors"
-
-var (
-	ErrInsufficientFunds = errors.New("insufficient funds")
-)
@@ -0,0 +1,54 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Append inserts the entry. The table rejects updates and deletes, so entries
+// can only be added.
+func (a *AuditRepository) Append(ctx context.Context, tx pgx.Tx, entry domain.AuditEntry) error {
+	query := `
+		INSERT INTO admin_audit (
+			id,
+			operator_id,
+			operator_roles,
+			action,
+			target_id,
+			reason,
+			details,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8
+		)
+	`
+
+	roles := make([]string, len(entry.OperatorRoles))
+	for i, role := range entry.OperatorRoles {
+		roles[i] = string(role)
+	}
+
+	_, err := tx.Exec(ctx, query,
+		entry.ID,
+		entry.OperatorID,
+		roles,
+		entry.Action,
+		entry.TargetID,
+		entry.Reason,
+		entry.Details,
+		entry.CreatedAt,
+	)
+
+	return err
+}
@@ -2,14 +2,16 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 
-	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/adapters/storage"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const _balanceColumns = "user_id, currency, available_balance, reserved_balance, status, updated_at"
+
 type BalanceRepository struct {
 	db *pgxpool.Pool
 }
@@ -18,28 +20,144 @@ func NewPgBalanceRepository(db *pgxpool.Pool) *BalanceRepository {
 	return &BalanceRepository{db: db}
 }
 
-func (r *BalanceRepository) Get(ctx context.Context, userID string) (*domain.Balance, error) {
+func (r *BalanceRepository) Create(ctx context.Context, tx pgx.Tx, balance domain.Balance) error {
+	uid, err := uuid.Parse(balance.UserID)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO balance (user_id, currency, available_balance, reserved_balance, status, updated_at) " +
+		"VALUES ($1, $2, $3, $4, $5, $6)"
+
+	_, errExec := tx.Exec(ctx, query, uid, balance.Currency, balance.Available, balance.Reserved,
+		balance.Status, balance.UpdatedAt)
+	if errExec != nil {
+		if isUniqueViolation(errExec) {
+			return domain.ErrWalletAlreadyExists
+		}
+		return errExec
+	}
+
+	return nil
+}
+
+func (r *BalanceRepository) Get(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error) {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, err
+		return nil, domain.ErrWalletNotFound
 	}
 
-	query := "SELECT user_id, available_balance, reserved_balance, updated_at FROM balance WHERE user_id = $1"
-	var balance domain.Balance
-	err = r.db.QueryRow(ctx, query, uid).Scan(
-		&balance.UserID,
-		&balance.Available,
-		&balance.Reserved,
-		&balance.UpdatedAt,
-	)
+	query := "SELECT " + _balanceColumns + " FROM balance WHERE user_id = $1 AND currency = $2"
+
+	balance, err := scanBalance(r.db.QueryRow(ctx, query, uid, currency))
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return nil, r.missingWallet(ctx, r.db, uid)
+	}
+
+	return balance, err
+}
+
+// ReadByUserID reads the wallet of the user in the currency within the
+// transaction, without locking it.
+func (r *BalanceRepository) ReadByUserID(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.Balance, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	query := "SELECT " + _balanceColumns + " FROM balance WHERE user_id = $1 AND currency = $2"
+
+	balance, err := scanBalance(tx.QueryRow(ctx, query, uid, currency))
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return nil, r.missingWallet(ctx, tx, uid)
+	}
+
+	return balance, err
+}
+
+// FindByUserID reads the wallet of the user in the currency locking it until
+// the transaction ends.
+func (r *BalanceRepository) FindByUserID(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.Balance, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	query := "SELECT " + _balanceColumns + " FROM balance WHERE user_id = $1 AND currency = $2 FOR UPDATE"
+
+	balance, err := scanBalance(tx.QueryRow(ctx, query, uid, currency))
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return nil, r.missingWallet(ctx, tx, uid)
+	}
+
+	return balance, err
+}
+
+// FindAllByUserID reads the wallets of the user in every currency, locking them
+// in currency order until the transaction ends.
+func (r *BalanceRepository) FindAllByUserID(ctx context.Context, tx pgx.Tx, userID string) ([]domain.Balance, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	query := "SELECT " + _balanceColumns + " FROM balance WHERE user_id = $1 ORDER BY currency FOR UPDATE"
+
+	rows, err := tx.Query(ctx, query, uid)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return &balance, nil
+	balances := make([]domain.Balance, 0)
+	for rows.Next() {
+		balance, errScan := scanBalance(rows)
+		if errScan != nil {
+			return nil, errScan
+		}
+		balances = append(balances, *balance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(balances) == 0 {
+		return nil, domain.ErrWalletNotFound
+	}
+
+	return balances, nil
 }
 
-func (r *BalanceRepository) ReserveFunds(ctx context.Context, tx pgx.Tx, userID string, amount int64) error {
+func (r *BalanceRepository) UpdateStatus(ctx context.Context, tx pgx.Tx, userID string, status domain.WalletStatus) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.ErrWalletNotFound
+	}
+
+	query := "UPDATE balance " +
+		"SET " +
+		"status = $1, " +
+		"updated_at = NOW() " +
+		"WHERE user_id = $2"
+
+	result, errExec := tx.Exec(ctx, query, status, uid)
+	if errExec != nil {
+		return errExec
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrWalletNotFound
+	}
+
+	return nil
+}
+
+// ReserveFunds moves amount from the available to the reserved balance of an
+// active wallet. The status is checked by the update itself, so a wallet frozen
+// or closed concurrently cannot take the reservation. When nothing is reserved
+// the wallet is read again to tell why.
+func (r *BalanceRepository) ReserveFunds(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error {
 	uid, err := uuid.Parse(userID)
 	if err != nil {
 		return err
@@ -51,23 +169,172 @@ func (r *BalanceRepository) ReserveFunds(ctx context.Context, tx pgx.Tx, userID
 		"reserved_balance = reserved_balance + $1, " +
 		"updated_at = NOW() " +
 		"WHERE user_id = $2 " +
+		"AND currency = $3 " +
+		"AND status = $4 " +
 		"AND available_balance >= $1"
 
-	result, errExec := tx.Exec(ctx, query, amount, uid)
+	result, errExec := tx.Exec(ctx, query, amount.Amount, uid, amount.Currency, domain.WalletActive)
 	if errExec != nil {
 		return errExec
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return storage.ErrInsufficientFunds
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	wallet, err := r.FindByUserID(ctx, tx, userID, amount.Currency)
+	if err != nil {
+		return err
+	}
+
+	switch wallet.Status {
+	case domain.WalletFrozen:
+		return domain.ErrWalletFrozen
+	case domain.WalletClosed:
+		return domain.ErrWalletClosed
+	}
+
+	return domain.ErrInsufficientFunds
+}
+
+func (r *BalanceRepository) ReleaseFunds(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE balance " +
+		"SET " +
+		"available_balance = available_balance + $1, " +
+		"reserved_balance = reserved_balance - $1, " +
+		"updated_at = NOW() " +
+		"WHERE user_id = $2 " +
+		"AND currency = $3 " +
+		"AND reserved_balance >= $1"
+
+	result, errExec := tx.Exec(ctx, query, amount.Amount, uid, amount.Currency)
+	if errExec != nil {
+		return errExec
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrReservationNotFound
 	}
 
 	return nil
 }
-func (r *BalanceRepository) ReleaseFunds(ctx context.Context, userID string, amount int64) error {
+
+func (r *BalanceRepository) Credit(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.ErrWalletNotFound
+	}
+
+	query := "UPDATE balance " +
+		"SET " +
+		"available_balance = available_balance + $1, " +
+		"updated_at = NOW() " +
+		"WHERE user_id = $2 " +
+		"AND currency = $3"
+
+	result, errExec := tx.Exec(ctx, query, amount.Amount, uid, amount.Currency)
+	if errExec != nil {
+		return errExec
+	}
+
+	if result.RowsAffected() == 0 {
+		return r.missingWallet(ctx, tx, uid)
+	}
+
 	return nil
 }
-func (r *BalanceRepository) ConfirmReserve(ctx context.Context, userID string, amount int64) error {
+
+// Debit subtracts amount from the available balance, failing with
+// ErrInsufficientFunds instead of letting it go negative.
+func (r *BalanceRepository) Debit(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return domain.ErrWalletNotFound
+	}
+
+	query := "UPDATE balance " +
+		"SET " +
+		"available_balance = available_balance - $1, " +
+		"updated_at = NOW() " +
+		"WHERE user_id = $2 " +
+		"AND currency = $3 " +
+		"AND available_balance >= $1"
+
+	result, errExec := tx.Exec(ctx, query, amount.Amount, uid, amount.Currency)
+	if errExec != nil {
+		return errExec
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrInsufficientFunds
+	}
+
+	return nil
+}
+
+func (r *BalanceRepository) ConfirmReserve(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE balance " +
+		"SET " +
+		"reserved_balance = reserved_balance - $1, " +
+		"updated_at = NOW() " +
+		"WHERE user_id = $2 " +
+		"AND currency = $3 " +
+		"AND reserved_balance >= $1"
+
+	result, errExec := tx.Exec(ctx, query, amount.Amount, uid, amount.Currency)
+	if errExec != nil {
+		return errExec
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrReservationNotFound
+	}
+
 	return nil
 }
+
+// missingWallet tells apart a user without any wallet from one lacking the
+// wallet of the requested currency.
+func (r *BalanceRepository) missingWallet(ctx context.Context, q querier, uid uuid.UUID) error {
+	var exists bool
+	err := q.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM balance WHERE user_id = $1)", uid).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return domain.ErrCurrencyNotHeld
+	}
+
+	return domain.ErrWalletNotFound
+}
+
+func scanBalance(row pgx.Row) (*domain.Balance, error) {
+	var balance domain.Balance
+	err := row.Scan(
+		&balance.UserID,
+		&balance.Currency,
+		&balance.Available,
+		&balance.Reserved,
+		&balance.Status,
+		&balance.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrWalletNotFound
+		}
+		return nil, err
+	}
+
+	return &balance, nil
+}
@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const _billerColumns = `
+	id,
+	name,
+	currency,
+	min_amount,
+	max_amount,
+	enabled,
+	COALESCE(client_number_pattern, ''),
+	COALESCE(check_digit, '')`
+
+type BillerRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgBillerRepository(db *pgxpool.Pool) *BillerRepository {
+	return &BillerRepository{db: db}
+}
+
+func (b *BillerRepository) Get(ctx context.Context, billerID string) (*domain.Biller, error) {
+	query := "SELECT " + _billerColumns + " FROM billers WHERE id = $1"
+
+	return scanBiller(b.db.QueryRow(ctx, query, billerID))
+}
+
+// ListEnabled returns the billers accepting payments, by name.
+func (b *BillerRepository) ListEnabled(ctx context.Context) ([]domain.Biller, error) {
+	query := "SELECT " + _billerColumns + " FROM billers WHERE enabled ORDER BY name, id"
+
+	rows, err := b.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	billers := make([]domain.Biller, 0)
+	for rows.Next() {
+		biller, errScan := scanBiller(rows)
+		if errScan != nil {
+			return nil, errScan
+		}
+		billers = append(billers, *biller)
+	}
+
+	return billers, rows.Err()
+}
+
+func scanBiller(row pgx.Row) (*domain.Biller, error) {
+	var biller domain.Biller
+	err := row.Scan(
+		&biller.ID,
+		&biller.Name,
+		&biller.Currency,
+		&biller.MinAmount,
+		&biller.MaxAmount,
+		&biller.Enabled,
+		&biller.ClientNumberPattern,
+		&biller.CheckDigit,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrBillerNotFound
+		}
+		return nil, err
+	}
+
+	return &biller, nil
+}
@@ -2,10 +2,19 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	_uniqueViolationCode     = "23505"
+	_foreignKeyViolationCode = "23503"
 )
 
 var (
@@ -13,6 +22,12 @@ var (
 	_pool *pgxpool.Pool
 )
 
+// querier is satisfied by both the pool and a transaction, for reads that run
+// either way.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 type Database struct {
 	DB *pgxpool.Pool
 }
@@ -23,8 +38,30 @@ func NewDatabase(ctx context.Context, dsn string) (*Database, error) {
 	return &Database{DB: db}, err
 }
 
+// WithTx runs fn in a transaction traced as a span of its own. fn does not
+// receive the span context, so its queries are traced as siblings of the
+// transaction within the caller's trace.
 func (d *Database) WithTx(ctx context.Context, fn func(*pgx.Tx) error) error {
-	tx, err := d.DB.Begin(ctx)
+	return d.withTx(ctx, pgx.TxOptions{}, fn)
+}
+
+// WithSnapshot runs fn in a read-only repeatable read transaction, so all its
+// reads see the database as of the first one.
+func (d *Database) WithSnapshot(ctx context.Context, fn func(*pgx.Tx) error) error {
+	return d.withTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly}, fn)
+}
+
+func (d *Database) withTx(ctx context.Context, options pgx.TxOptions, fn func(*pgx.Tx) error) (err error) {
+	ctx, span := _tracer.Start(ctx, "TRANSACTION", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	tx, err := d.DB.BeginTx(ctx, options)
 	if err != nil {
 		return err
 	}
@@ -48,7 +85,27 @@ func (d *Database) WithTx(ctx context.Context, fn func(*pgx.Tx) error) error {
 func Connect(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	var err error
 	_once.Do(func() {
-		_pool, err = pgxpool.New(ctx, dsn)
+		var config *pgxpool.Config
+		config, err = pgxpool.ParseConfig(dsn)
+		if err != nil {
+			return
+		}
+
+		config.ConnConfig.Tracer = queryTracer{}
+		_pool, err = pgxpool.NewWithConfig(ctx, config)
 	})
 	return _pool, err
 }
+
+func isUniqueViolation(err error) bool {
+	return hasErrorCode(err, _uniqueViolationCode)
+}
+
+func isForeignKeyViolation(err error) bool {
+	return hasErrorCode(err, _foreignKeyViolationCode)
+}
+
+func hasErrorCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
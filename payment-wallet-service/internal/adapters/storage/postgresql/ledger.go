@@ -0,0 +1,148 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LedgerRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgLedgerRepository(db *pgxpool.Pool) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+func (l *LedgerRepository) Post(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error {
+	if !transaction.Balanced() {
+		return domain.ErrUnbalancedLedgerTransaction
+	}
+
+	query := `
+		INSERT INTO ledger_entries (
+			transaction_id,
+			movement,
+			user_id,
+			account,
+			direction,
+			amount,
+			currency,
+			reference_id,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+
+	batch := &pgx.Batch{}
+	for _, entry := range transaction.Entries {
+		uid, err := uuid.Parse(entry.UserID)
+		if err != nil {
+			return err
+		}
+
+		batch.Queue(query,
+			transaction.ID,
+			entry.Movement,
+			uid,
+			entry.Account,
+			entry.Direction,
+			entry.Amount,
+			entry.Currency,
+			entry.ReferenceID,
+			entry.CreatedAt,
+		)
+	}
+
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+func (l *LedgerRepository) GetEntries(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency, limit int) ([]domain.LedgerEntry, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			id,
+			transaction_id,
+			movement,
+			user_id,
+			account,
+			direction,
+			amount,
+			currency,
+			reference_id,
+			created_at
+		FROM ledger_entries
+		WHERE user_id = $1
+		AND currency = $2
+		AND account IN ('available', 'reserved')
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	rows, err := tx.Query(ctx, query, uid, currency, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]domain.LedgerEntry, 0)
+	for rows.Next() {
+		var entry domain.LedgerEntry
+		if err = rows.Scan(
+			&entry.ID,
+			&entry.TransactionID,
+			&entry.Movement,
+			&entry.UserID,
+			&entry.Account,
+			&entry.Direction,
+			&entry.Amount,
+			&entry.Currency,
+			&entry.ReferenceID,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// GetBalance derives the wallet balance in the currency from the postings of
+// its accounts.
+func (l *LedgerRepository) GetBalance(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.Balance, error) {
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			COALESCE(SUM(CASE WHEN direction = 'CREDIT' THEN amount ELSE -amount END)
+				FILTER (WHERE account = 'available'), 0),
+			COALESCE(SUM(CASE WHEN direction = 'CREDIT' THEN amount ELSE -amount END)
+				FILTER (WHERE account = 'reserved'), 0)
+		FROM ledger_entries
+		WHERE user_id = $1
+		AND currency = $2
+	`
+
+	balance := domain.Balance{UserID: userID, Currency: currency}
+	err = tx.QueryRow(ctx, query, uid, currency).Scan(
+		&balance.Available,
+		&balance.Reserved,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &balance, nil
+}
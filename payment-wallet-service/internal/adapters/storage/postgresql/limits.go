@@ -0,0 +1,90 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LimitRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgLimitRepository(db *pgxpool.Pool) *LimitRepository {
+	return &LimitRepository{db: db}
+}
+
+// FindOverride returns the limits configuration of the wallet, or nil when it
+// has none.
+func (l *LimitRepository) FindOverride(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.LimitOverride, error) {
+	query := `
+		SELECT
+			COALESCE(tier, ''),
+			per_transaction,
+			daily_amount,
+			monthly_amount,
+			hourly_count
+		FROM user_limits
+		WHERE user_id = $1 AND currency = $2
+	`
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var override domain.LimitOverride
+	err = tx.QueryRow(ctx, query, uid, currency).Scan(
+		&override.Tier,
+		&override.PerTransaction,
+		&override.DailyAmount,
+		&override.MonthlyAmount,
+		&override.HourlyCount,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &override, nil
+}
+
+// Usage adds up the payments funded from the wallet in each window. Payments
+// that were rejected, cancelled or expired never moved money and are left out.
+func (l *LimitRepository) Usage(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency, windows domain.LimitWindows) (*domain.LimitUsage, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(funding_amount) FILTER (WHERE created_at >= $3), 0),
+			COALESCE(SUM(funding_amount) FILTER (WHERE created_at >= $4), 0),
+			COUNT(*) FILTER (WHERE created_at >= $5)
+		FROM payments
+		WHERE user_id = $1
+		  AND funding_currency = $2
+		  AND created_at >= LEAST($3::timestamptz, $4::timestamptz, $5::timestamptz)
+		  AND status NOT IN ($6, $7, $8)
+	`
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var usage domain.LimitUsage
+	err = tx.QueryRow(ctx, query, uid, currency, windows.Day, windows.Month, windows.Hour,
+		domain.StatusRejected, domain.StatusCancelled, domain.StatusExpired).Scan(
+		&usage.DailyAmount,
+		&usage.MonthlyAmount,
+		&usage.HourlyCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
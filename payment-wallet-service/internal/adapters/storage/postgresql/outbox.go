@@ -0,0 +1,150 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+type OutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Create writes the message along with the trace context and the request ID
+// of ctx, when it has them, for the relay to publish the message within the
+// trace and tagged with the request that wrote it.
+func (o *OutboxRepository) Create(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+	query := `
+		INSERT INTO outbox (
+			id,
+			aggregate_id,
+			event_type,
+			payload,
+			created_at,
+			trace_context,
+			request_id
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, NULLIF($7, '')
+		)
+	`
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	var traceContext any
+	if len(carrier) > 0 {
+		traceContext = map[string]string(carrier)
+	}
+
+	_, err := tx.Exec(ctx, query,
+		message.ID,
+		message.AggregateID,
+		message.EventType,
+		message.Payload,
+		message.CreatedAt,
+		traceContext,
+		correlation.RequestID(ctx),
+	)
+
+	return err
+}
+
+// ClaimPending claims the next batch of unsent messages whose retry time has
+// come, moving their retry time a lease ahead so no other relay takes them
+// once the transaction commits. Rows locked by another relay are skipped, so
+// several replicas can drain the outbox concurrently. A message whose relay
+// stopped before marking it is claimed again when the lease runs out.
+func (o *OutboxRepository) ClaimPending(ctx context.Context, tx pgx.Tx, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE outbox
+			SET next_attempt_at = NOW() + $2::interval
+			WHERE id IN (
+				SELECT id
+				FROM outbox
+				WHERE sent_at IS NULL
+				AND next_attempt_at <= NOW()
+				ORDER BY created_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING
+				id,
+				aggregate_id,
+				event_type,
+				payload,
+				attempts,
+				created_at,
+				trace_context,
+				COALESCE(request_id, '') AS request_id
+		)
+		SELECT * FROM claimed ORDER BY created_at
+	`
+
+	rows, err := tx.Query(ctx, query, limit, lease)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []domain.OutboxMessage
+	for rows.Next() {
+		var message domain.OutboxMessage
+		if err = rows.Scan(
+			&message.ID,
+			&message.AggregateID,
+			&message.EventType,
+			&message.Payload,
+			&message.Attempts,
+			&message.CreatedAt,
+			&message.TraceContext,
+			&message.RequestID,
+		); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+func (o *OutboxRepository) MarkSent(ctx context.Context, tx pgx.Tx, messageID string) error {
+	query := "UPDATE outbox SET sent_at = NOW(), last_error = NULL WHERE id = $1"
+
+	_, err := tx.Exec(ctx, query, messageID)
+
+	return err
+}
+
+func (o *OutboxRepository) MarkFailed(ctx context.Context, tx pgx.Tx, messageID string, nextAttemptAt time.Time, reason string) error {
+	query := "UPDATE outbox " +
+		"SET " +
+		"attempts = attempts + 1, " +
+		"next_attempt_at = $1, " +
+		"last_error = $2 " +
+		"WHERE id = $3"
+
+	_, err := tx.Exec(ctx, query, nextAttemptAt, reason, messageID)
+
+	return err
+}
+
+// Release hands claimed messages back before their lease runs out, so the
+// next relay publishes them right away.
+func (o *OutboxRepository) Release(ctx context.Context, tx pgx.Tx, messageIDs []string) error {
+	query := "UPDATE outbox SET next_attempt_at = NOW() WHERE id = ANY($1) AND sent_at IS NULL"
+
+	_, err := tx.Exec(ctx, query, messageIDs)
+
+	return err
+}
@@ -2,6 +2,11 @@ package postgresql
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
 	"github.com/google/uuid"
@@ -9,6 +14,27 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const _paymentColumns = `
+	id,
+	idempotency_key,
+	user_id,
+	amount,
+	currency,
+	status,
+	service_id,
+	client_number,
+	COALESCE(processor_reference, ''),
+	COALESCE(failure_reason, ''),
+	refunded_amount,
+	funding_amount,
+	funding_currency,
+	COALESCE(fx_rate::text, ''),
+	COALESCE(fx_quote_id::text, ''),
+	created_at,
+	updated_at,
+	version
+`
+
 type PaymentsRepository struct {
 	db *pgxpool.Pool
 }
@@ -17,15 +43,18 @@ func NewPgPaymentsRepository(db *pgxpool.Pool) *PaymentsRepository {
 	return &PaymentsRepository{db: db}
 }
 
-func (p *PaymentsRepository) CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (bool, error) {
-	query := "SELECT COUNT(*) FROM payments WHERE idempotency_key = $1"
+func (p *PaymentsRepository) CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.Payment, error) {
+	query := "SELECT " + _paymentColumns + " FROM payments WHERE idempotency_key = $1"
 
-	var count int
-	err := tx.QueryRow(ctx, query, idempotencyKey).Scan(&count)
+	payment, err := scanPayment(tx.QueryRow(ctx, query, idempotencyKey))
 	if err != nil {
-		return false, err
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	return count > 0, nil
+
+	return payment, nil
 }
 
 func (p *PaymentsRepository) Create(ctx context.Context, tx pgx.Tx, payment domain.Payment) error {
@@ -35,13 +64,19 @@ func (p *PaymentsRepository) Create(ctx context.Context, tx pgx.Tx, payment doma
 			idempotency_key,
 			user_id,
 			amount,
+			currency,
 			status,
 			service_id,
 			client_number,
+			funding_amount,
+			funding_currency,
+			fx_rate,
+			fx_quote_id,
 			created_at,
 			updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10,
+			NULLIF($11, '')::numeric, NULLIF($12, '')::uuid, $13, $14
 		)
 	`
 
@@ -55,19 +90,309 @@ func (p *PaymentsRepository) Create(ctx context.Context, tx pgx.Tx, payment doma
 		payment.IdempotencyKey,
 		uid,
 		payment.Amount,
+		payment.Currency,
 		payment.Status,
 		payment.ServiceID,
 		payment.ClientNumber,
+		payment.FundingAmount,
+		payment.FundingCurrency,
+		payment.FXRate,
+		payment.FXQuoteID,
 		payment.CreatedAt,
 		payment.UpdatedAt,
 	)
 	if errCreate != nil {
+		if isUniqueViolation(errCreate) {
+			return domain.ErrIdempotencyKeyConflict
+		}
 		return errCreate
 	}
 
-	return nil
+	return recordStatusChange(ctx, tx, payment.ID, "", payment.Status, payment.CreatedAt)
+}
+
+func (p *PaymentsRepository) FindByID(ctx context.Context, tx pgx.Tx, paymentID string) (*domain.Payment, error) {
+	query := "SELECT " + _paymentColumns + " FROM payments WHERE id = $1 FOR UPDATE"
+
+	id, err := uuid.Parse(paymentID)
+	if err != nil {
+		return nil, domain.ErrPaymentNotFound
+	}
+
+	return scanPayment(tx.QueryRow(ctx, query, id))
+}
+
+// FindExpired locks and returns the oldest payment still pending since before
+// pendingBefore, or ErrPaymentNotFound if there is none. A payment is pending
+// since it was last updated, so the ones approved after a review get the whole
+// TTL too. The row stays locked until the transaction ends, so it is expired
+// in the same one, and rows locked by another transaction, such as a payment
+// another sweeper is expiring, are skipped instead of blocking on them.
+func (p *PaymentsRepository) FindExpired(ctx context.Context, tx pgx.Tx, pendingBefore time.Time) (*domain.Payment, error) {
+	query := "SELECT " + _paymentColumns + ` FROM payments
+		WHERE status = $1 AND updated_at < $2
+		ORDER BY updated_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`
+
+	return scanPayment(tx.QueryRow(ctx, query, domain.StatusPending, pendingBefore))
+}
+
+// PostponeExpiry moves the pending since time of a pending payment to at, which
+// sends it to the back of the expiry queue. The version is left alone, as the
+// payment itself does not change.
+func (p *PaymentsRepository) PostponeExpiry(ctx context.Context, tx pgx.Tx, paymentID string, at time.Time) error {
+	query := "UPDATE payments SET updated_at = $1 WHERE id = $2 AND status = $3"
+
+	_, err := tx.Exec(ctx, query, at, paymentID, domain.StatusPending)
+	return err
+}
+
+// Update persists the transition of the payment from the given status. It is
+// applied only if the payment still has that status and the version it was
+// read with, so concurrent writers cannot overwrite each other's changes.
+func (p *PaymentsRepository) Update(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+	query := `
+		UPDATE payments
+		SET
+			status = $1,
+			processor_reference = NULLIF($2, ''),
+			failure_reason = NULLIF($3, ''),
+			updated_at = $4,
+			refunded_amount = $5,
+			version = version + 1
+		WHERE id = $6 AND version = $7 AND status = $8
+	`
+
+	result, err := tx.Exec(ctx, query,
+		payment.Status,
+		payment.ProcessorReference,
+		payment.FailureReason,
+		payment.UpdatedAt,
+		payment.RefundedAmount,
+		payment.ID,
+		payment.Version,
+		from,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return domain.ErrPaymentConflict
+	}
+
+	if from == payment.Status {
+		return nil
+	}
+
+	return recordStatusChange(ctx, tx, payment.ID, from, payment.Status, payment.UpdatedAt)
+}
+
+// History returns the status changes of the payment from oldest to newest.
+func (p *PaymentsRepository) History(ctx context.Context, paymentID string) ([]domain.PaymentStatusChange, error) {
+	query := `
+		SELECT
+			payment_id,
+			COALESCE(from_status, ''),
+			to_status,
+			created_at
+		FROM payment_status_history
+		WHERE payment_id = $1
+		ORDER BY id
+	`
+
+	id, err := uuid.Parse(paymentID)
+	if err != nil {
+		return nil, domain.ErrPaymentNotFound
+	}
+
+	rows, err := p.db.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make([]domain.PaymentStatusChange, 0)
+	for rows.Next() {
+		var change domain.PaymentStatusChange
+		if err = rows.Scan(&change.PaymentID, &change.From, &change.To, &change.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, change)
+	}
+
+	return history, rows.Err()
 }
 
-func (p *PaymentsRepository) Update(ctx context.Context, payment domain.Payment) error {
-	return nil
+func recordStatusChange(ctx context.Context, tx pgx.Tx, paymentID string, from, to domain.PaymentStatus, at time.Time) error {
+	query := `
+		INSERT INTO payment_status_history (
+			payment_id,
+			from_status,
+			to_status,
+			created_at
+		) VALUES (
+			$1, NULLIF($2, ''), $3, $4
+		)
+	`
+
+	_, err := tx.Exec(ctx, query, paymentID, from, to, at)
+	return err
+}
+
+// Get returns the payment only when it belongs to the given user, so callers
+// cannot probe payments of other users.
+func (p *PaymentsRepository) Get(ctx context.Context, userID, paymentID string) (*domain.Payment, error) {
+	query := "SELECT " + _paymentColumns + " FROM payments WHERE id = $1 AND user_id = $2"
+
+	id, err := uuid.Parse(paymentID)
+	if err != nil {
+		return nil, domain.ErrPaymentNotFound
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, domain.ErrPaymentNotFound
+	}
+
+	return scanPayment(p.db.QueryRow(ctx, query, id, uid))
+}
+
+// List pages through the user's payments from newest to oldest using keyset
+// pagination on (created_at, id).
+func (p *PaymentsRepository) List(ctx context.Context, filter domain.PaymentFilter) (*domain.PaymentPage, error) {
+	uid, err := uuid.Parse(filter.UserID)
+	if err != nil {
+		return &domain.PaymentPage{Payments: []domain.Payment{}}, nil
+	}
+
+	args := []any{uid}
+	conditions := []string{"user_id = $1"}
+	where := func(condition string, values ...any) {
+		placeholders := make([]any, 0, len(values))
+		for _, value := range values {
+			args = append(args, value)
+			placeholders = append(placeholders, len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	if filter.Status != "" {
+		where("status = $%d", filter.Status)
+	}
+	if filter.ServiceID != "" {
+		where("service_id = $%d", filter.ServiceID)
+	}
+	if filter.MinAmount > 0 {
+		where("amount >= $%d", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		where("amount <= $%d", filter.MaxAmount)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		where("created_at >= $%d", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		where("created_at < $%d", filter.CreatedTo)
+	}
+	if filter.Cursor != "" {
+		createdAt, id, errCursor := decodeCursor(filter.Cursor)
+		if errCursor != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		where("(created_at, id) < ($%d, $%d)", createdAt, id)
+	}
+
+	args = append(args, filter.Limit+1)
+	query := "SELECT " + _paymentColumns + " FROM payments" +
+		" WHERE " + strings.Join(conditions, " AND ") +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &domain.PaymentPage{Payments: make([]domain.Payment, 0, filter.Limit)}
+	for rows.Next() {
+		payment, errScan := scanPayment(rows)
+		if errScan != nil {
+			return nil, errScan
+		}
+		page.Payments = append(page.Payments, *payment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Payments) > filter.Limit {
+		page.Payments = page.Payments[:filter.Limit]
+		last := page.Payments[filter.Limit-1]
+		page.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return page, nil
+}
+
+func scanPayment(row pgx.Row) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := row.Scan(
+		&payment.ID,
+		&payment.IdempotencyKey,
+		&payment.UserID,
+		&payment.Amount,
+		&payment.Currency,
+		&payment.Status,
+		&payment.ServiceID,
+		&payment.ClientNumber,
+		&payment.ProcessorReference,
+		&payment.FailureReason,
+		&payment.RefundedAmount,
+		&payment.FundingAmount,
+		&payment.FundingCurrency,
+		&payment.FXRate,
+		&payment.FXQuoteID,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+		&payment.Version,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func encodeCursor(createdAt time.Time, id string) string {
+	raw := createdAt.Format(time.RFC3339Nano) + "|" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return time.Time{}, uuid.Nil, errors.New("malformed cursor")
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	return t, uid, nil
 }
@@ -0,0 +1,92 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RefundRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgRefundRepository(db *pgxpool.Pool) *RefundRepository {
+	return &RefundRepository{db: db}
+}
+
+func (r *RefundRepository) CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.Refund, error) {
+	query := `
+		SELECT
+			id,
+			payment_id,
+			user_id,
+			amount,
+			currency,
+			idempotency_key,
+			created_at
+		FROM refunds
+		WHERE idempotency_key = $1
+	`
+
+	var refund domain.Refund
+	err := tx.QueryRow(ctx, query, idempotencyKey).Scan(
+		&refund.ID,
+		&refund.PaymentID,
+		&refund.UserID,
+		&refund.Amount,
+		&refund.Currency,
+		&refund.IdempotencyKey,
+		&refund.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+func (r *RefundRepository) Create(ctx context.Context, tx pgx.Tx, refund domain.Refund) error {
+	query := `
+		INSERT INTO refunds (
+			id,
+			payment_id,
+			user_id,
+			amount,
+			currency,
+			idempotency_key,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+	`
+
+	uid, err := uuid.Parse(refund.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, errCreate := tx.Exec(ctx, query,
+		refund.ID,
+		refund.PaymentID,
+		uid,
+		refund.Amount,
+		refund.Currency,
+		refund.IdempotencyKey,
+		refund.CreatedAt,
+	)
+	if errCreate != nil {
+		if isUniqueViolation(errCreate) {
+			return domain.ErrIdempotencyKeyConflict
+		}
+		return errCreate
+	}
+
+	return nil
+}
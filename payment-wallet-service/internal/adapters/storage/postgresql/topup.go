@@ -0,0 +1,91 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TopUpRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgTopUpRepository(db *pgxpool.Pool) *TopUpRepository {
+	return &TopUpRepository{db: db}
+}
+
+func (t *TopUpRepository) CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.TopUp, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			amount,
+			currency,
+			idempotency_key,
+			created_at
+		FROM topups
+		WHERE idempotency_key = $1
+	`
+
+	var topUp domain.TopUp
+	err := tx.QueryRow(ctx, query, idempotencyKey).Scan(
+		&topUp.ID,
+		&topUp.UserID,
+		&topUp.Amount,
+		&topUp.Currency,
+		&topUp.IdempotencyKey,
+		&topUp.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &topUp, nil
+}
+
+func (t *TopUpRepository) Create(ctx context.Context, tx pgx.Tx, topUp domain.TopUp) error {
+	query := `
+		INSERT INTO topups (
+			id,
+			user_id,
+			amount,
+			currency,
+			idempotency_key,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+	`
+
+	uid, err := uuid.Parse(topUp.UserID)
+	if err != nil {
+		return domain.ErrWalletNotFound
+	}
+
+	_, errCreate := tx.Exec(ctx, query,
+		topUp.ID,
+		uid,
+		topUp.Amount,
+		topUp.Currency,
+		topUp.IdempotencyKey,
+		topUp.CreatedAt,
+	)
+	if errCreate != nil {
+		if isUniqueViolation(errCreate) {
+			return domain.ErrIdempotencyKeyConflict
+		}
+		if isForeignKeyViolation(errCreate) {
+			return domain.ErrWalletNotFound
+		}
+		return errCreate
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TransferRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPgTransferRepository(db *pgxpool.Pool) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+func (t *TransferRepository) CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.Transfer, error) {
+	query := `
+		SELECT
+			id,
+			from_user_id,
+			to_user_id,
+			amount,
+			currency,
+			idempotency_key,
+			created_at
+		FROM transfers
+		WHERE idempotency_key = $1
+	`
+
+	var transfer domain.Transfer
+	err := tx.QueryRow(ctx, query, idempotencyKey).Scan(
+		&transfer.ID,
+		&transfer.FromUserID,
+		&transfer.ToUserID,
+		&transfer.Amount,
+		&transfer.Currency,
+		&transfer.IdempotencyKey,
+		&transfer.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func (t *TransferRepository) Create(ctx context.Context, tx pgx.Tx, transfer domain.Transfer) error {
+	query := `
+		INSERT INTO transfers (
+			id,
+			from_user_id,
+			to_user_id,
+			amount,
+			currency,
+			idempotency_key,
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+	`
+
+	fromUID, err := uuid.Parse(transfer.FromUserID)
+	if err != nil {
+		return domain.ErrWalletNotFound
+	}
+
+	toUID, err := uuid.Parse(transfer.ToUserID)
+	if err != nil {
+		return domain.ErrWalletNotFound
+	}
+
+	_, errCreate := tx.Exec(ctx, query,
+		transfer.ID,
+		fromUID,
+		toUID,
+		transfer.Amount,
+		transfer.Currency,
+		transfer.IdempotencyKey,
+		transfer.CreatedAt,
+	)
+	if errCreate != nil {
+		if isUniqueViolation(errCreate) {
+			return domain.ErrIdempotencyKeyConflict
+		}
+		if isForeignKeyViolation(errCreate) {
+			return domain.ErrWalletNotFound
+		}
+		return errCreate
+	}
+
+	return nil
+}
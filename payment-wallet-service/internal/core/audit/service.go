@@ -0,0 +1,65 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
+	"github.com/jackc/pgx/v5"
+)
+
+type ServiceConfig struct {
+	Logger          *slog.Logger
+	DB              ports.Database
+	AuditRepository ports.AuditRepository
+}
+
+type Service struct {
+	logger    *slog.Logger
+	db        ports.Database
+	auditRepo ports.AuditRepository
+}
+
+func NewAuditService(config ServiceConfig) *Service {
+	return &Service{
+		logger:    config.Logger,
+		db:        config.DB,
+		auditRepo: config.AuditRepository,
+	}
+}
+
+// Append records an action of the operator inside the caller's transaction, so
+// the entry is stored if and only if the change it describes is.
+func (s *Service) Append(ctx context.Context, tx pgx.Tx, operator domain.Operator, action, targetID, reason string, details any) error {
+	entry, err := domain.NewAuditEntry(uidgen.NewUUID(), operator, action, targetID, reason, details)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to build audit entry",
+			slog.Any("error", err),
+			slog.String("action", action),
+			slog.String("target_id", targetID))
+
+		return domain.ErrRecordAudit
+	}
+
+	if err = s.auditRepo.Append(ctx, tx, entry); err != nil {
+		s.logger.ErrorContext(ctx, "failed to append audit entry",
+			slog.Any("error", err),
+			slog.String("operator_id", operator.ID),
+			slog.String("action", action),
+			slog.String("target_id", targetID))
+
+		return domain.ErrRecordAudit
+	}
+
+	return nil
+}
+
+// Record stores an action of the operator that changes nothing, such as a
+// lookup, on its own.
+func (s *Service) Record(ctx context.Context, operator domain.Operator, action, targetID string, details any) error {
+	return s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		return s.Append(ctx, *tx, operator, action, targetID, "", details)
+	})
+}
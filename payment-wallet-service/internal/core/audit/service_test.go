@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewAuditService(t *testing.T) {
+	logger := slog.Default()
+	mockDB := mocks.NewMockDatabase(gomock.NewController(t))
+	mockAuditRepo := mocks.NewMockAuditRepository(gomock.NewController(t))
+
+	service := NewAuditService(ServiceConfig{
+		Logger:          logger,
+		DB:              mockDB,
+		AuditRepository: mockAuditRepo,
+	})
+
+	assert.NotNil(t, service)
+	assert.Equal(t, logger, service.logger)
+	assert.Equal(t, mockDB, service.db)
+	assert.Equal(t, mockAuditRepo, service.auditRepo)
+}
+
+func TestService_Append(t *testing.T) {
+	ctx := context.Background()
+	tx := new(pgx.Tx)
+	ctrl := gomock.NewController(t)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+
+	service := NewAuditService(ServiceConfig{
+		Logger:          slog.Default(),
+		AuditRepository: mockAuditRepo,
+	})
+	operator := domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleFinance}}
+
+	t.Run("entry appended", func(t *testing.T) {
+		mockAuditRepo.EXPECT().Append(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, entry domain.AuditEntry) error {
+				assert.NotEmpty(t, entry.ID)
+				assert.Equal(t, "operator-1", entry.OperatorID)
+				assert.Equal(t, []domain.Role{domain.RoleFinance}, entry.OperatorRoles)
+				assert.Equal(t, domain.AuditAdjustBalance, entry.Action)
+				assert.Equal(t, "user-123", entry.TargetID)
+				assert.Equal(t, "duplicated charge", entry.Reason)
+				assert.False(t, entry.CreatedAt.IsZero())
+
+				var details map[string]any
+				assert.NoError(t, json.Unmarshal(entry.Details, &details))
+				assert.Equal(t, float64(250), details["amount"])
+				return nil
+			}).Times(1)
+
+		err := service.Append(ctx, *tx, operator, domain.AuditAdjustBalance, "user-123", "duplicated charge",
+			map[string]any{"amount": 250})
+		assert.NoError(t, err)
+	})
+
+	t.Run("entry without details", func(t *testing.T) {
+		mockAuditRepo.EXPECT().Append(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, entry domain.AuditEntry) error {
+				assert.Nil(t, entry.Details)
+				return nil
+			}).Times(1)
+
+		err := service.Append(ctx, *tx, operator, domain.AuditLookupBalance, "user-123", "", nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error appending the entry", func(t *testing.T) {
+		mockAuditRepo.EXPECT().Append(ctx, gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+
+		err := service.Append(ctx, *tx, operator, domain.AuditAdjustBalance, "user-123", "duplicated charge", nil)
+		assert.Equal(t, domain.ErrRecordAudit, err)
+	})
+}
+
+func TestService_Record(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+
+	service := NewAuditService(ServiceConfig{
+		Logger:          slog.Default(),
+		DB:              mockDB,
+		AuditRepository: mockAuditRepo,
+	})
+	operator := domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleSupportReadOnly}}
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+
+	t.Run("lookup recorded in its own transaction", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockAuditRepo.EXPECT().Append(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, entry domain.AuditEntry) error {
+				assert.Equal(t, domain.AuditLookupPayments, entry.Action)
+				assert.Empty(t, entry.Reason)
+				return nil
+			}).Times(1)
+
+		err := service.Record(ctx, operator, domain.AuditLookupPayments, "user-123", map[string]string{"status": "PENDING"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("error recording the lookup", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockAuditRepo.EXPECT().Append(ctx, gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+
+		err := service.Record(ctx, operator, domain.AuditLookupPayments, "user-123", nil)
+		assert.Equal(t, domain.ErrRecordAudit, err)
+	})
+}
@@ -2,56 +2,346 @@ package balance
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sort"
 
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
 	"github.com/jackc/pgx/v5"
 )
 
 type ServiceConfig struct {
 	Logger            *slog.Logger
+	DB                ports.Database
 	BalanceRepository ports.BalanceRepository
+	LedgerRepository  ports.LedgerRepository
 }
 
 type Service struct {
 	logger      *slog.Logger
+	db          ports.Database
 	balanceRepo ports.BalanceRepository
+	ledgerRepo  ports.LedgerRepository
 }
 
 func NewBalanceService(config *ServiceConfig) *Service {
 	return &Service{
 		logger:      config.Logger,
+		db:          config.DB,
 		balanceRepo: config.BalanceRepository,
+		ledgerRepo:  config.LedgerRepository,
 	}
 }
 
-func (s *Service) ReserveFunds(ctx context.Context, tx pgx.Tx, userID string, amount int64) error {
-	balance, errGetBalance := s.balanceRepo.Get(ctx, userID)
+func (s *Service) Get(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error) {
+	balance, err := s.balanceRepo.Get(ctx, userID, currency)
+	if err != nil {
+		return nil, s.getError(ctx, err, userID, currency)
+	}
+
+	return balance, nil
+}
+
+func (s *Service) getError(ctx context.Context, err error, userID string, currency domain.Currency) error {
+	if errors.Is(err, domain.ErrWalletNotFound) {
+		return domain.ErrWalletNotFound
+	}
+
+	if errors.Is(err, domain.ErrCurrencyNotHeld) {
+		return domain.ErrCurrencyNotHeld
+	}
+
+	s.logger.ErrorContext(ctx, "failed to get user balance",
+		slog.Any("error", err),
+		slog.String("user_id", userID),
+		slog.String("currency", string(currency)))
+
+	return domain.ErrGetBalance
+}
+
+// ReserveFunds moves amount from the available to the reserved balance of the
+// user, in the currency of the amount. Frozen and closed wallets cannot take
+// new payments; the reservation checks the status again, so a wallet frozen or
+// closed after it was read is refused too.
+func (s *Service) ReserveFunds(ctx context.Context, tx pgx.Tx, userID, paymentID string, amount domain.Money) error {
+	balance, errGetBalance := s.Get(ctx, userID, amount.Currency)
 	if errGetBalance != nil {
-		s.logger.Error("failed to get user balance",
-			slog.Any("error", errGetBalance),
-			slog.String("user_id", userID))
+		return errGetBalance
+	}
 
-		return domain.ErrGetBalance
+	switch balance.Status {
+	case domain.WalletFrozen:
+		return domain.ErrWalletFrozen
+	case domain.WalletClosed:
+		return domain.ErrWalletClosed
 	}
 
-	if balance.Available < amount {
+	if balance.Available < amount.Amount {
 		return domain.ErrInsufficientFunds
 	}
 
 	errReserve := s.balanceRepo.ReserveFunds(ctx, tx, userID, amount)
 	if errReserve != nil {
-		s.logger.Error("failed to reserve funds",
-			slog.Any("error", errReserve),
-			slog.String("user_id", userID))
+		// a concurrent payment took the funds, or the wallet was frozen or
+		// closed, after they were checked
+		if errors.Is(errReserve, domain.ErrInsufficientFunds) {
+			return domain.ErrInsufficientFunds
+		}
+
+		if errors.Is(errReserve, domain.ErrWalletFrozen) {
+			return domain.ErrWalletFrozen
+		}
+
+		if errors.Is(errReserve, domain.ErrWalletClosed) {
+			return domain.ErrWalletClosed
+		}
+
+		s.logger.ErrorContext(ctx, "failed to reserve funds",
+			slog.Any("error", errReserve))
 
 		return domain.ErrReserveFunds
 	}
 
-	return nil
+	return s.post(ctx, tx, domain.MovementReserve, userID, paymentID, amount)
+}
+
+// Update settles a reservation once the payment reached a final state: approved
+// payments consume the reserved funds, any other outcome returns them to the
+// available balance. It runs inside the caller's transaction so the balance
+// moves together with the payment status.
+func (s *Service) Update(ctx context.Context, tx pgx.Tx, userID, paymentID string, amount domain.Money, approved bool) error {
+	var err error
+	movement := domain.MovementConfirm
+	if approved {
+		err = s.balanceRepo.ConfirmReserve(ctx, tx, userID, amount)
+	} else {
+		movement = domain.MovementRelease
+		err = s.balanceRepo.ReleaseFunds(ctx, tx, userID, amount)
+	}
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to update balance",
+			slog.Any("error", err),
+			slog.String("user_id", userID),
+			slog.Bool("approved", approved))
+
+		if errors.Is(err, domain.ErrReservationNotFound) {
+			return domain.ErrReservationNotFound
+		}
+
+		return domain.ErrUpdateBalance
+	}
+
+	return s.post(ctx, tx, movement, userID, paymentID, amount)
+}
+
+// Credit adds amount to the available balance of the user in its currency,
+// recording it in the ledger as the given movement (refund, top-up) of the
+// referenced operation.
+func (s *Service) Credit(ctx context.Context, tx pgx.Tx, userID, referenceID, movement string, amount domain.Money) error {
+	err := s.balanceRepo.Credit(ctx, tx, userID, amount)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to credit balance",
+			slog.Any("error", err),
+			slog.String("user_id", userID),
+			slog.String("movement", movement))
+
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return domain.ErrWalletNotFound
+		}
+
+		if errors.Is(err, domain.ErrCurrencyNotHeld) {
+			return domain.ErrCurrencyNotHeld
+		}
+
+		return domain.ErrCreditBalance
+	}
+
+	return s.post(ctx, tx, movement, userID, referenceID, amount)
+}
+
+// Debit takes amount from the available balance of the user in its currency,
+// recording it in the ledger as the given movement of the referenced operation.
+// It fails with ErrInsufficientFunds rather than leaving the balance negative.
+func (s *Service) Debit(ctx context.Context, tx pgx.Tx, userID, referenceID, movement string, amount domain.Money) error {
+	err := s.balanceRepo.Debit(ctx, tx, userID, amount)
+	if err != nil {
+		if errors.Is(err, domain.ErrInsufficientFunds) {
+			return domain.ErrInsufficientFunds
+		}
+
+		s.logger.ErrorContext(ctx, "failed to debit balance",
+			slog.Any("error", err),
+			slog.String("user_id", userID),
+			slog.String("movement", movement))
+
+		if errors.Is(err, domain.ErrWalletNotFound) {
+			return domain.ErrWalletNotFound
+		}
+
+		if errors.Is(err, domain.ErrCurrencyNotHeld) {
+			return domain.ErrCurrencyNotHeld
+		}
+
+		return domain.ErrUpdateBalance
+	}
+
+	return s.post(ctx, tx, movement, userID, referenceID, amount)
 }
 
-func (s *Service) Update(ctx context.Context, userID string, amount int64) error {
+// Transfer moves amount from the available balance of one user to the other,
+// posting both legs to the ledger. Both wallets are locked in user id order,
+// whichever way the money goes, so two opposite transfers between the same
+// users cannot deadlock. The sender must be active; the receiver only needs
+// not to be closed, as frozen wallets still accept credits. Both must hold the
+// currency of the amount; transfers never convert between currencies.
+func (s *Service) Transfer(ctx context.Context, tx pgx.Tx, fromUserID, toUserID, transferID string, amount domain.Money) error {
+	lockOrder := []string{fromUserID, toUserID}
+	sort.Strings(lockOrder)
+
+	wallets := make(map[string]*domain.Balance, len(lockOrder))
+	for _, userID := range lockOrder {
+		wallet, err := s.balanceRepo.FindByUserID(ctx, tx, userID, amount.Currency)
+		if err != nil {
+			if errors.Is(err, domain.ErrWalletNotFound) {
+				return domain.ErrWalletNotFound
+			}
+
+			if errors.Is(err, domain.ErrCurrencyNotHeld) {
+				return domain.ErrCurrencyNotHeld
+			}
+
+			s.logger.ErrorContext(ctx, "failed to lock wallet",
+				slog.Any("error", err),
+				slog.String("user_id", userID))
+
+			return domain.ErrGetBalance
+		}
+		wallets[userID] = wallet
+	}
+
+	switch wallets[fromUserID].Status {
+	case domain.WalletFrozen:
+		return domain.ErrWalletFrozen
+	case domain.WalletClosed:
+		return domain.ErrWalletClosed
+	}
+
+	if wallets[toUserID].Status == domain.WalletClosed {
+		return domain.ErrWalletClosed
+	}
+
+	if wallets[fromUserID].Available < amount.Amount {
+		return domain.ErrInsufficientFunds
+	}
+
+	errDebit := s.balanceRepo.Debit(ctx, tx, fromUserID, amount)
+	if errDebit != nil {
+		s.logger.ErrorContext(ctx, "failed to debit balance",
+			slog.Any("error", errDebit),
+			slog.String("transfer_id", transferID))
+
+		if errors.Is(errDebit, domain.ErrInsufficientFunds) {
+			return domain.ErrInsufficientFunds
+		}
+
+		return domain.ErrUpdateBalance
+	}
+
+	errCredit := s.balanceRepo.Credit(ctx, tx, toUserID, amount)
+	if errCredit != nil {
+		s.logger.ErrorContext(ctx, "failed to credit balance",
+			slog.Any("error", errCredit),
+			slog.String("to_user_id", toUserID),
+			slog.String("transfer_id", transferID))
+
+		return domain.ErrCreditBalance
+	}
+
+	if err := s.post(ctx, tx, domain.MovementTransferOut, fromUserID, transferID, amount); err != nil {
+		return err
+	}
+
+	return s.post(ctx, tx, domain.MovementTransferIn, toUserID, transferID, amount)
+}
+
+// Statement returns the latest ledger entries of the user in the currency
+// together with the balance derived from them, flagging whether it matches the
+// balance table. All three are read from one snapshot, so a movement committed
+// in between cannot make them disagree.
+func (s *Service) Statement(ctx context.Context, userID string, currency domain.Currency, limit int) (*domain.Statement, error) {
+	var (
+		balance *domain.Balance
+		derived *domain.Balance
+		entries []domain.LedgerEntry
+	)
+
+	err := s.db.WithSnapshot(ctx, func(tx *pgx.Tx) error {
+		var err error
+		balance, err = s.balanceRepo.ReadByUserID(ctx, *tx, userID, currency)
+		if err != nil {
+			return s.getError(ctx, err, userID, currency)
+		}
+
+		derived, err = s.ledgerRepo.GetBalance(ctx, *tx, userID, currency)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to get ledger balance",
+				slog.Any("error", err),
+				slog.String("user_id", userID))
+
+			return domain.ErrGetStatement
+		}
+
+		entries, err = s.ledgerRepo.GetEntries(ctx, *tx, userID, currency, limit)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to get ledger entries",
+				slog.Any("error", err),
+				slog.String("user_id", userID))
+
+			return domain.ErrGetStatement
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reconciled := derived.Available == balance.Available && derived.Reserved == balance.Reserved
+	if !reconciled {
+		s.logger.ErrorContext(ctx, "balance does not match ledger",
+			slog.String("user_id", userID),
+			slog.String("currency", string(currency)),
+			slog.Int64("available", balance.Available),
+			slog.Int64("ledger_available", derived.Available),
+			slog.Int64("reserved", balance.Reserved),
+			slog.Int64("ledger_reserved", derived.Reserved))
+	}
+
+	return &domain.Statement{
+		UserID:     userID,
+		Available:  derived.Available,
+		Reserved:   derived.Reserved,
+		Currency:   currency,
+		Reconciled: reconciled,
+		Entries:    entries,
+	}, nil
+}
+
+func (s *Service) post(ctx context.Context, tx pgx.Tx, movement, userID, referenceID string, amount domain.Money) error {
+	transaction := domain.NewLedgerTransaction(uidgen.NewUUID(), movement, userID, referenceID, amount)
+
+	if err := s.ledgerRepo.Post(ctx, tx, transaction); err != nil {
+		s.logger.ErrorContext(ctx, "failed to post ledger entries",
+			slog.Any("error", err),
+			slog.String("user_id", userID),
+			slog.String("movement", movement),
+			slog.String("reference_id", referenceID))
+
+		return domain.ErrPostLedger
+	}
+
 	return nil
 }
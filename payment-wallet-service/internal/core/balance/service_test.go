@@ -16,39 +16,95 @@ import (
 func TestNewBalanceService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockLedgerRepo := mocks.NewMockLedgerRepository(ctrl)
+	mockDB := mocks.NewMockDatabase(ctrl)
 	logger := slog.Default()
 
 	config := &ServiceConfig{
 		Logger:            logger,
+		DB:                mockDB,
 		BalanceRepository: mockRepo,
+		LedgerRepository:  mockLedgerRepo,
 	}
 
 	service := NewBalanceService(config)
 
 	assert.NotNil(t, service)
 	assert.Equal(t, logger, service.logger)
+	assert.Equal(t, mockDB, service.db)
 	assert.Equal(t, mockRepo, service.balanceRepo)
+	assert.Equal(t, mockLedgerRepo, service.ledgerRepo)
+}
+
+func TestService_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		balanceRepo: mockBalanceRepo,
+	}
+
+	ctx := context.Background()
+	userID := "valid-user-id"
+	currency := domain.CurrencyUSD
+
+	t.Run("wallet exists", func(t *testing.T) {
+		expected := &domain.Balance{UserID: userID, Available: 100, Reserved: 10}
+		mockBalanceRepo.EXPECT().Get(ctx, userID, currency).Return(expected, nil).Times(1)
+
+		balance, err := service.Get(ctx, userID, currency)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, balance)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, currency).Return(nil, domain.ErrWalletNotFound).Times(1)
+
+		balance, err := service.Get(ctx, userID, currency)
+		assert.Nil(t, balance)
+		assert.Equal(t, domain.ErrWalletNotFound, err)
+	})
+
+	t.Run("currency not held", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, currency).Return(nil, domain.ErrCurrencyNotHeld).Times(1)
+
+		balance, err := service.Get(ctx, userID, currency)
+		assert.Nil(t, balance)
+		assert.Equal(t, domain.ErrCurrencyNotHeld, err)
+	})
+
+	t.Run("failed to get user balance", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, currency).Return(nil, errors.New("database error")).Times(1)
+
+		balance, err := service.Get(ctx, userID, currency)
+		assert.Nil(t, balance)
+		assert.Equal(t, domain.ErrGetBalance, err)
+	})
 }
 
 func TestService_ReserveFunds(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockLedgerRepo := mocks.NewMockLedgerRepository(ctrl)
 	logger := slog.Default()
 
 	service := &Service{
 		logger:      logger,
 		balanceRepo: mockBalanceRepo,
+		ledgerRepo:  mockLedgerRepo,
 	}
 
 	ctx := context.Background()
 	tx := new(pgx.Tx)
 	userID := "valid-user-id"
-	amount := int64(10)
+	paymentID := "payment-id"
+	amount := domain.NewMoney(10, domain.CurrencyUSD)
 
 	t.Run("successful reserve", func(t *testing.T) {
-		mockBalanceRepo.EXPECT().Get(ctx, userID).Return(&domain.Balance{
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
 			UserID:    userID,
-			Available: amount,
+			Available: amount.Amount,
 			Reserved:  0,
 			UpdatedAt: time.Time{},
 		}, nil).Times(1)
@@ -56,42 +112,501 @@ func TestService_ReserveFunds(t *testing.T) {
 		mockBalanceRepo.EXPECT().
 			ReserveFunds(ctx, gomock.Any(), userID, amount).Return(nil).Times(1)
 
-		err := service.ReserveFunds(ctx, *tx, userID, amount)
+		mockLedgerRepo.EXPECT().Post(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error {
+				assert.True(t, transaction.Balanced())
+				assert.Equal(t, domain.MovementReserve, transaction.Entries[0].Movement)
+				assert.Equal(t, domain.AccountAvailable, transaction.Entries[0].Account)
+				assert.Equal(t, domain.EntryDebit, transaction.Entries[0].Direction)
+				assert.Equal(t, domain.AccountReserved, transaction.Entries[1].Account)
+				assert.Equal(t, domain.EntryCredit, transaction.Entries[1].Direction)
+				assert.Equal(t, paymentID, transaction.Entries[0].ReferenceID)
+				assert.Equal(t, amount.Currency, transaction.Entries[0].Currency)
+				assert.Equal(t, amount.Currency, transaction.Entries[1].Currency)
+				return nil
+			}).Times(1)
+
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
 		assert.NoError(t, err)
 	})
 
+	t.Run("failed to post ledger entries", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
+			UserID:    userID,
+			Available: amount.Amount,
+		}, nil).Times(1)
+		mockBalanceRepo.EXPECT().
+			ReserveFunds(ctx, gomock.Any(), userID, amount).Return(nil).Times(1)
+		mockLedgerRepo.EXPECT().Post(ctx, gomock.Any(), gomock.Any()).
+			Return(errors.New("error posting entries")).Times(1)
+
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrPostLedger, err)
+	})
+
 	t.Run("failed to get user balance", func(t *testing.T) {
-		mockBalanceRepo.EXPECT().Get(ctx, userID).Return(nil, pgx.ErrNoRows)
-		err := service.ReserveFunds(ctx, *tx, userID, amount)
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(nil, pgx.ErrNoRows)
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
 		assert.Error(t, err)
 		assert.Equal(t, err, domain.ErrGetBalance)
 	})
 
+	t.Run("currency not held", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(nil, domain.ErrCurrencyNotHeld).Times(1)
+		mockBalanceRepo.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
+		assert.Equal(t, domain.ErrCurrencyNotHeld, err)
+	})
+
 	t.Run("insufficient funds - amount exceeds available", func(t *testing.T) {
-		mockBalanceRepo.EXPECT().Get(ctx, userID).Return(&domain.Balance{
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
 			UserID:    userID,
 			Available: 5,
 			Reserved:  0,
 			UpdatedAt: time.Time{},
 		}, nil).Times(1)
 
-		err := service.ReserveFunds(ctx, *tx, userID, amount)
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
 		assert.Error(t, err)
 		assert.Equal(t, err, domain.ErrInsufficientFunds)
 	})
 
+	t.Run("insufficient funds - taken by a concurrent payment", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
+			UserID:    userID,
+			Available: amount.Amount,
+		}, nil).Times(1)
+		mockBalanceRepo.EXPECT().ReserveFunds(ctx, gomock.Any(), userID, amount).
+			Return(domain.ErrInsufficientFunds).Times(1)
+		mockLedgerRepo.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
+		assert.Equal(t, domain.ErrInsufficientFunds, err)
+	})
+
+	t.Run("wallet frozen after it was read", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
+			UserID:    userID,
+			Available: amount.Amount,
+			Status:    domain.WalletActive,
+		}, nil).Times(1)
+		mockBalanceRepo.EXPECT().ReserveFunds(ctx, gomock.Any(), userID, amount).
+			Return(domain.ErrWalletFrozen).Times(1)
+		mockLedgerRepo.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
+		assert.Equal(t, domain.ErrWalletFrozen, err)
+	})
+
+	t.Run("wallet closed after it was read", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
+			UserID:    userID,
+			Available: amount.Amount,
+			Status:    domain.WalletActive,
+		}, nil).Times(1)
+		mockBalanceRepo.EXPECT().ReserveFunds(ctx, gomock.Any(), userID, amount).
+			Return(domain.ErrWalletClosed).Times(1)
+		mockLedgerRepo.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
+		assert.Equal(t, domain.ErrWalletClosed, err)
+	})
+
+	t.Run("frozen wallet", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
+			UserID:    userID,
+			Available: amount.Amount,
+			Status:    domain.WalletFrozen,
+		}, nil).Times(1)
+		mockBalanceRepo.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
+		assert.Equal(t, domain.ErrWalletFrozen, err)
+	})
+
+	t.Run("closed wallet", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
+			UserID:    userID,
+			Available: amount.Amount,
+			Status:    domain.WalletClosed,
+		}, nil).Times(1)
+		mockBalanceRepo.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
+		assert.Equal(t, domain.ErrWalletClosed, err)
+	})
+
 	t.Run("failed to reserve funds in repository", func(t *testing.T) {
-		mockBalanceRepo.EXPECT().Get(ctx, userID).Return(&domain.Balance{
+		mockBalanceRepo.EXPECT().Get(ctx, userID, amount.Currency).Return(&domain.Balance{
 			UserID:    userID,
-			Available: amount,
+			Available: amount.Amount,
 			Reserved:  0,
 			UpdatedAt: time.Time{},
 		}, nil).Times(1)
 		mockBalanceRepo.EXPECT().ReserveFunds(ctx, gomock.Any(), userID, amount).
 			Return(errors.New("error reserving funds")).Times(1)
 
-		err := service.ReserveFunds(ctx, *tx, userID, amount)
+		err := service.ReserveFunds(ctx, *tx, userID, paymentID, amount)
 		assert.Error(t, err)
 		assert.Equal(t, err, domain.ErrReserveFunds)
 	})
 }
+
+func TestService_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockLedgerRepo := mocks.NewMockLedgerRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		balanceRepo: mockBalanceRepo,
+		ledgerRepo:  mockLedgerRepo,
+	}
+
+	ctx := context.Background()
+	tx := new(pgx.Tx)
+	userID := "valid-user-id"
+	paymentID := "payment-id"
+	amount := domain.NewMoney(10, domain.CurrencyUSD)
+	expectMovement := func(movement string) {
+		mockLedgerRepo.EXPECT().Post(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error {
+				assert.True(t, transaction.Balanced())
+				assert.Equal(t, movement, transaction.Entries[0].Movement)
+				assert.Equal(t, paymentID, transaction.Entries[0].ReferenceID)
+				return nil
+			}).Times(1)
+	}
+
+	t.Run("approved payment confirms reserve", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().ConfirmReserve(ctx, gomock.Any(), userID, amount).Return(nil).Times(1)
+		mockBalanceRepo.EXPECT().ReleaseFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		expectMovement(domain.MovementConfirm)
+
+		err := service.Update(ctx, *tx, userID, paymentID, amount, true)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejected payment releases funds", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().ReleaseFunds(ctx, gomock.Any(), userID, amount).Return(nil).Times(1)
+		mockBalanceRepo.EXPECT().ConfirmReserve(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		expectMovement(domain.MovementRelease)
+
+		err := service.Update(ctx, *tx, userID, paymentID, amount, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("reservation not found", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().ReleaseFunds(ctx, gomock.Any(), userID, amount).
+			Return(domain.ErrReservationNotFound).Times(1)
+
+		err := service.Update(ctx, *tx, userID, paymentID, amount, false)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrReservationNotFound, err)
+	})
+
+	t.Run("failed to update balance in repository", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().ConfirmReserve(ctx, gomock.Any(), userID, amount).
+			Return(errors.New("error confirming reserve")).Times(1)
+
+		err := service.Update(ctx, *tx, userID, paymentID, amount, true)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrUpdateBalance, err)
+	})
+}
+
+func TestService_Credit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockLedgerRepo := mocks.NewMockLedgerRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		balanceRepo: mockBalanceRepo,
+		ledgerRepo:  mockLedgerRepo,
+	}
+
+	ctx := context.Background()
+	tx := new(pgx.Tx)
+	userID := "valid-user-id"
+	refundID := "refund-id"
+	amount := domain.NewMoney(10, domain.CurrencyUSD)
+
+	t.Run("refund credits available balance", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Credit(ctx, gomock.Any(), userID, amount).Return(nil).Times(1)
+		mockLedgerRepo.EXPECT().Post(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error {
+				assert.True(t, transaction.Balanced())
+				assert.Equal(t, domain.MovementRefund, transaction.Entries[0].Movement)
+				assert.Equal(t, domain.AccountSettlement, transaction.Entries[0].Account)
+				assert.Equal(t, domain.AccountAvailable, transaction.Entries[1].Account)
+				assert.Equal(t, refundID, transaction.Entries[0].ReferenceID)
+				return nil
+			}).Times(1)
+
+		err := service.Credit(ctx, *tx, userID, refundID, domain.MovementRefund, amount)
+		assert.NoError(t, err)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Credit(ctx, gomock.Any(), userID, amount).Return(domain.ErrWalletNotFound).Times(1)
+		mockLedgerRepo.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.Credit(ctx, *tx, userID, refundID, domain.MovementRefund, amount)
+		assert.Equal(t, domain.ErrWalletNotFound, err)
+	})
+
+	t.Run("failed to credit balance in repository", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Credit(ctx, gomock.Any(), userID, amount).Return(errors.New("db error")).Times(1)
+		mockLedgerRepo.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.Credit(ctx, *tx, userID, refundID, domain.MovementRefund, amount)
+		assert.Equal(t, domain.ErrCreditBalance, err)
+	})
+}
+
+func TestService_Debit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockLedgerRepo := mocks.NewMockLedgerRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		balanceRepo: mockBalanceRepo,
+		ledgerRepo:  mockLedgerRepo,
+	}
+
+	ctx := context.Background()
+	tx := new(pgx.Tx)
+	userID := "valid-user-id"
+	adjustmentID := "adjustment-id"
+	amount := domain.NewMoney(10, domain.CurrencyUSD)
+
+	t.Run("adjustment debits available balance", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Debit(ctx, gomock.Any(), userID, amount).Return(nil).Times(1)
+		mockLedgerRepo.EXPECT().Post(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error {
+				assert.True(t, transaction.Balanced())
+				assert.Equal(t, domain.MovementAdjustmentDebit, transaction.Entries[0].Movement)
+				assert.Equal(t, domain.AccountAvailable, transaction.Entries[0].Account)
+				assert.Equal(t, domain.AccountAdjustments, transaction.Entries[1].Account)
+				assert.Equal(t, adjustmentID, transaction.Entries[0].ReferenceID)
+				return nil
+			}).Times(1)
+
+		err := service.Debit(ctx, *tx, userID, adjustmentID, domain.MovementAdjustmentDebit, amount)
+		assert.NoError(t, err)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Debit(ctx, gomock.Any(), userID, amount).Return(domain.ErrInsufficientFunds).Times(1)
+		mockLedgerRepo.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.Debit(ctx, *tx, userID, adjustmentID, domain.MovementAdjustmentDebit, amount)
+		assert.Equal(t, domain.ErrInsufficientFunds, err)
+	})
+
+	t.Run("failed to debit balance in repository", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().Debit(ctx, gomock.Any(), userID, amount).Return(errors.New("db error")).Times(1)
+		mockLedgerRepo.EXPECT().Post(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.Debit(ctx, *tx, userID, adjustmentID, domain.MovementAdjustmentDebit, amount)
+		assert.Equal(t, domain.ErrUpdateBalance, err)
+	})
+}
+
+func TestService_Transfer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockLedgerRepo := mocks.NewMockLedgerRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		balanceRepo: mockBalanceRepo,
+		ledgerRepo:  mockLedgerRepo,
+	}
+
+	ctx := context.Background()
+	tx := new(pgx.Tx)
+	sender := "user-b"
+	receiver := "user-a"
+	transferID := "transfer-id"
+	amount := domain.NewMoney(10, domain.CurrencyUSD)
+	wallet := func(userID string, available int64, status domain.WalletStatus) *domain.Balance {
+		return &domain.Balance{UserID: userID, Available: available, Status: status}
+	}
+	lockWallets := func(from, to *domain.Balance) {
+		// wallets are always locked in user id order
+		gomock.InOrder(
+			mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), receiver, amount.Currency).Return(to, nil),
+			mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), sender, amount.Currency).Return(from, nil),
+		)
+	}
+
+	t.Run("successful transfer", func(t *testing.T) {
+		lockWallets(wallet(sender, amount.Amount, domain.WalletActive), wallet(receiver, 0, domain.WalletFrozen))
+		mockBalanceRepo.EXPECT().Debit(ctx, gomock.Any(), sender, amount).Return(nil).Times(1)
+		mockBalanceRepo.EXPECT().Credit(ctx, gomock.Any(), receiver, amount).Return(nil).Times(1)
+		gomock.InOrder(
+			mockLedgerRepo.EXPECT().Post(ctx, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error {
+					assert.True(t, transaction.Balanced())
+					assert.Equal(t, domain.MovementTransferOut, transaction.Entries[0].Movement)
+					assert.Equal(t, sender, transaction.Entries[0].UserID)
+					assert.Equal(t, domain.AccountAvailable, transaction.Entries[0].Account)
+					assert.Equal(t, domain.AccountTransfers, transaction.Entries[1].Account)
+					assert.Equal(t, transferID, transaction.Entries[0].ReferenceID)
+					return nil
+				}),
+			mockLedgerRepo.EXPECT().Post(ctx, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error {
+					assert.True(t, transaction.Balanced())
+					assert.Equal(t, domain.MovementTransferIn, transaction.Entries[0].Movement)
+					assert.Equal(t, receiver, transaction.Entries[0].UserID)
+					assert.Equal(t, domain.AccountTransfers, transaction.Entries[0].Account)
+					assert.Equal(t, domain.AccountAvailable, transaction.Entries[1].Account)
+					return nil
+				}),
+		)
+
+		err := service.Transfer(ctx, *tx, sender, receiver, transferID, amount)
+		assert.NoError(t, err)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		lockWallets(wallet(sender, amount.Amount-1, domain.WalletActive), wallet(receiver, 0, domain.WalletActive))
+		mockBalanceRepo.EXPECT().Debit(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.Transfer(ctx, *tx, sender, receiver, transferID, amount)
+		assert.Equal(t, domain.ErrInsufficientFunds, err)
+	})
+
+	t.Run("frozen sender", func(t *testing.T) {
+		lockWallets(wallet(sender, amount.Amount, domain.WalletFrozen), wallet(receiver, 0, domain.WalletActive))
+
+		err := service.Transfer(ctx, *tx, sender, receiver, transferID, amount)
+		assert.Equal(t, domain.ErrWalletFrozen, err)
+	})
+
+	t.Run("closed receiver", func(t *testing.T) {
+		lockWallets(wallet(sender, amount.Amount, domain.WalletActive), wallet(receiver, 0, domain.WalletClosed))
+
+		err := service.Transfer(ctx, *tx, sender, receiver, transferID, amount)
+		assert.Equal(t, domain.ErrWalletClosed, err)
+	})
+
+	t.Run("receiver does not hold the currency", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), receiver, amount.Currency).
+			Return(nil, domain.ErrCurrencyNotHeld).Times(1)
+
+		err := service.Transfer(ctx, *tx, sender, receiver, transferID, amount)
+		assert.Equal(t, domain.ErrCurrencyNotHeld, err)
+	})
+
+	t.Run("receiver wallet not found", func(t *testing.T) {
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), receiver, amount.Currency).Return(nil, domain.ErrWalletNotFound).Times(1)
+
+		err := service.Transfer(ctx, *tx, sender, receiver, transferID, amount)
+		assert.Equal(t, domain.ErrWalletNotFound, err)
+	})
+
+	t.Run("failed to debit balance in repository", func(t *testing.T) {
+		lockWallets(wallet(sender, amount.Amount, domain.WalletActive), wallet(receiver, 0, domain.WalletActive))
+		mockBalanceRepo.EXPECT().Debit(ctx, gomock.Any(), sender, amount).
+			Return(errors.New("error debiting balance")).Times(1)
+
+		err := service.Transfer(ctx, *tx, sender, receiver, transferID, amount)
+		assert.Equal(t, domain.ErrUpdateBalance, err)
+	})
+}
+
+func TestService_Statement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockLedgerRepo := mocks.NewMockLedgerRepository(ctrl)
+	mockDB := mocks.NewMockDatabase(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		db:          mockDB,
+		balanceRepo: mockBalanceRepo,
+		ledgerRepo:  mockLedgerRepo,
+	}
+
+	ctx := context.Background()
+	userID := "valid-user-id"
+	currency := domain.CurrencyUSD
+	entries := []domain.LedgerEntry{
+		{ID: 2, Movement: domain.MovementReserve, Account: domain.AccountReserved, Direction: domain.EntryCredit, Amount: 10},
+		{ID: 1, Movement: domain.MovementReserve, Account: domain.AccountAvailable, Direction: domain.EntryDebit, Amount: 10},
+	}
+
+	withSnapshot := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		return fn(new(pgx.Tx))
+	}
+
+	t.Run("balance matches ledger", func(t *testing.T) {
+		mockDB.EXPECT().WithSnapshot(ctx, gomock.Any()).DoAndReturn(withSnapshot).Times(1)
+		mockBalanceRepo.EXPECT().ReadByUserID(ctx, gomock.Any(), userID, currency).
+			Return(&domain.Balance{UserID: userID, Available: 90, Reserved: 10}, nil).Times(1)
+		mockLedgerRepo.EXPECT().GetBalance(ctx, gomock.Any(), userID, currency).
+			Return(&domain.Balance{UserID: userID, Available: 90, Reserved: 10}, nil).Times(1)
+		mockLedgerRepo.EXPECT().GetEntries(ctx, gomock.Any(), userID, currency, 50).Return(entries, nil).Times(1)
+
+		statement, err := service.Statement(ctx, userID, currency, 50)
+		assert.NoError(t, err)
+		assert.True(t, statement.Reconciled)
+		assert.Equal(t, int64(90), statement.Available)
+		assert.Equal(t, int64(10), statement.Reserved)
+		assert.Equal(t, currency, statement.Currency)
+		assert.Equal(t, entries, statement.Entries)
+	})
+
+	t.Run("balance does not match ledger", func(t *testing.T) {
+		mockDB.EXPECT().WithSnapshot(ctx, gomock.Any()).DoAndReturn(withSnapshot).Times(1)
+		mockBalanceRepo.EXPECT().ReadByUserID(ctx, gomock.Any(), userID, currency).
+			Return(&domain.Balance{UserID: userID, Available: 100, Reserved: 10}, nil).Times(1)
+		mockLedgerRepo.EXPECT().GetBalance(ctx, gomock.Any(), userID, currency).
+			Return(&domain.Balance{UserID: userID, Available: 90, Reserved: 10}, nil).Times(1)
+		mockLedgerRepo.EXPECT().GetEntries(ctx, gomock.Any(), userID, currency, 50).Return(entries, nil).Times(1)
+
+		statement, err := service.Statement(ctx, userID, currency, 50)
+		assert.NoError(t, err)
+		assert.False(t, statement.Reconciled)
+		assert.Equal(t, int64(90), statement.Available)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockDB.EXPECT().WithSnapshot(ctx, gomock.Any()).DoAndReturn(withSnapshot).Times(1)
+		mockBalanceRepo.EXPECT().ReadByUserID(ctx, gomock.Any(), userID, currency).Return(nil, domain.ErrWalletNotFound).Times(1)
+
+		statement, err := service.Statement(ctx, userID, currency, 50)
+		assert.Nil(t, statement)
+		assert.Equal(t, domain.ErrWalletNotFound, err)
+	})
+
+	t.Run("failed to get user balance", func(t *testing.T) {
+		mockDB.EXPECT().WithSnapshot(ctx, gomock.Any()).DoAndReturn(withSnapshot).Times(1)
+		mockBalanceRepo.EXPECT().ReadByUserID(ctx, gomock.Any(), userID, currency).Return(nil, pgx.ErrNoRows).Times(1)
+
+		statement, err := service.Statement(ctx, userID, currency, 50)
+		assert.Nil(t, statement)
+		assert.Equal(t, domain.ErrGetBalance, err)
+	})
+
+	t.Run("failed to get ledger entries", func(t *testing.T) {
+		mockDB.EXPECT().WithSnapshot(ctx, gomock.Any()).DoAndReturn(withSnapshot).Times(1)
+		mockBalanceRepo.EXPECT().ReadByUserID(ctx, gomock.Any(), userID, currency).
+			Return(&domain.Balance{UserID: userID}, nil).Times(1)
+		mockLedgerRepo.EXPECT().GetBalance(ctx, gomock.Any(), userID, currency).
+			Return(&domain.Balance{UserID: userID}, nil).Times(1)
+		mockLedgerRepo.EXPECT().GetEntries(ctx, gomock.Any(), userID, currency, 50).
+			Return(nil, errors.New("database error")).Times(1)
+
+		statement, err := service.Statement(ctx, userID, currency, 50)
+		assert.Nil(t, statement)
+		assert.Equal(t, domain.ErrGetStatement, err)
+	})
+}
@@ -0,0 +1,66 @@
+package billers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+)
+
+type ServiceConfig struct {
+	Logger           *slog.Logger
+	BillerRepository ports.BillerRepository
+}
+
+type Service struct {
+	logger     *slog.Logger
+	billerRepo ports.BillerRepository
+}
+
+func NewBillerService(config ServiceConfig) *Service {
+	return &Service{
+		logger:     config.Logger,
+		billerRepo: config.BillerRepository,
+	}
+}
+
+// Check returns an error when the payment request cannot be made to the
+// biller of its service_id: it is unknown or disabled, or the amount, the
+// currency or the client number are not the ones it accepts.
+func (s *Service) Check(ctx context.Context, request domain.CreatePaymentRequest) error {
+	biller, err := s.billerRepo.Get(ctx, request.ServiceID)
+	if err != nil {
+		if errors.Is(err, domain.ErrBillerNotFound) {
+			return domain.ErrBillerNotFound
+		}
+
+		s.logger.ErrorContext(ctx, "failed to get biller",
+			slog.Any("error", err),
+			slog.String("service_id", request.ServiceID))
+
+		return domain.ErrGetBiller
+	}
+
+	if errAccept := biller.Accept(request); errAccept != nil {
+		s.logger.WarnContext(ctx, "payment not accepted by biller",
+			slog.Any("error", errAccept),
+			slog.String("service_id", request.ServiceID))
+
+		return errAccept
+	}
+
+	return nil
+}
+
+// List returns the billers users can pay to.
+func (s *Service) List(ctx context.Context) ([]domain.Biller, error) {
+	billers, err := s.billerRepo.ListEnabled(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list billers", slog.Any("error", err))
+		return nil, domain.ErrListBillers
+	}
+
+	return billers, nil
+}
@@ -0,0 +1,215 @@
+package billers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewBillerService(t *testing.T) {
+	logger := slog.Default()
+	mockBillerRepo := mocks.NewMockBillerRepository(gomock.NewController(t))
+
+	service := NewBillerService(ServiceConfig{
+		Logger:           logger,
+		BillerRepository: mockBillerRepo,
+	})
+
+	assert.NotNil(t, service)
+	assert.Equal(t, logger, service.logger)
+	assert.Equal(t, mockBillerRepo, service.billerRepo)
+}
+
+func TestService_Check(t *testing.T) {
+	ctx := context.Background()
+	electricity := domain.Biller{
+		ID:                  "biller-1",
+		Name:                "Electricidad del Norte",
+		Currency:            domain.CurrencyARS,
+		MinAmount:           100,
+		MaxAmount:           50000,
+		Enabled:             true,
+		ClientNumberPattern: "[0-9]{11}",
+		CheckDigit:          domain.CheckDigitLuhn,
+	}
+	request := domain.CreatePaymentRequest{
+		UserID:       "user-1",
+		ServiceID:    "biller-1",
+		ClientNumber: "79927398713",
+		Amount:       10000,
+		Currency:     domain.CurrencyARS,
+	}
+
+	tests := []struct {
+		name        string
+		biller      func() domain.Biller
+		request     func() domain.CreatePaymentRequest
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name: "accepted payment",
+		},
+		{
+			name: "amount at the limits of the range",
+			request: func() domain.CreatePaymentRequest {
+				r := request
+				r.Amount = 50000
+				return r
+			},
+		},
+		{
+			name: "biller without maximum",
+			biller: func() domain.Biller {
+				b := electricity
+				b.MaxAmount = 0
+				return b
+			},
+			request: func() domain.CreatePaymentRequest {
+				r := request
+				r.Amount = 10000000
+				return r
+			},
+		},
+		{
+			name:        "unknown biller",
+			repoErr:     domain.ErrBillerNotFound,
+			expectedErr: domain.ErrBillerNotFound,
+		},
+		{
+			name:        "error getting the biller",
+			repoErr:     errors.New("database error"),
+			expectedErr: domain.ErrGetBiller,
+		},
+		{
+			name: "disabled biller",
+			biller: func() domain.Biller {
+				b := electricity
+				b.Enabled = false
+				return b
+			},
+			expectedErr: domain.ErrBillerDisabled,
+		},
+		{
+			name: "payment in another currency",
+			request: func() domain.CreatePaymentRequest {
+				r := request
+				r.Currency = domain.CurrencyUSD
+				return r
+			},
+			expectedErr: domain.ErrBillerCurrencyMismatch,
+		},
+		{
+			name: "amount below the minimum",
+			request: func() domain.CreatePaymentRequest {
+				r := request
+				r.Amount = 99
+				return r
+			},
+			expectedErr: domain.ErrAmountOutOfRange,
+		},
+		{
+			name: "amount above the maximum",
+			request: func() domain.CreatePaymentRequest {
+				r := request
+				r.Amount = 50001
+				return r
+			},
+			expectedErr: domain.ErrAmountOutOfRange,
+		},
+		{
+			name: "client number without the format",
+			request: func() domain.CreatePaymentRequest {
+				r := request
+				r.ClientNumber = "7992739871"
+				return r
+			},
+			expectedErr: domain.ErrInvalidClientNumber,
+		},
+		{
+			name: "pattern matches the whole client number",
+			request: func() domain.CreatePaymentRequest {
+				r := request
+				r.ClientNumber = "79927398713-1"
+				return r
+			},
+			expectedErr: domain.ErrInvalidClientNumber,
+		},
+		{
+			name: "wrong check digit",
+			request: func() domain.CreatePaymentRequest {
+				r := request
+				r.ClientNumber = "79927398710"
+				return r
+			},
+			expectedErr: domain.ErrInvalidClientNumber,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			biller := electricity
+			if tt.biller != nil {
+				biller = tt.biller()
+			}
+			paymentRequest := request
+			if tt.request != nil {
+				paymentRequest = tt.request()
+			}
+
+			ctrl := gomock.NewController(t)
+			mockBillerRepo := mocks.NewMockBillerRepository(ctrl)
+			if tt.repoErr != nil {
+				mockBillerRepo.EXPECT().Get(ctx, "biller-1").Return(nil, tt.repoErr).Times(1)
+			} else {
+				mockBillerRepo.EXPECT().Get(ctx, "biller-1").Return(&biller, nil).Times(1)
+			}
+
+			service := NewBillerService(ServiceConfig{
+				Logger:           slog.Default(),
+				BillerRepository: mockBillerRepo,
+			})
+
+			err := service.Check(ctx, paymentRequest)
+			if tt.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mockBillerRepo := mocks.NewMockBillerRepository(ctrl)
+
+	service := NewBillerService(ServiceConfig{
+		Logger:           slog.Default(),
+		BillerRepository: mockBillerRepo,
+	})
+
+	t.Run("enabled billers", func(t *testing.T) {
+		billers := []domain.Biller{{ID: "biller-1", Name: "Aguas del Sur", Enabled: true}}
+		mockBillerRepo.EXPECT().ListEnabled(ctx).Return(billers, nil).Times(1)
+
+		result, err := service.List(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, billers, result)
+	})
+
+	t.Run("error listing billers", func(t *testing.T) {
+		mockBillerRepo.EXPECT().ListEnabled(ctx).Return(nil, errors.New("database error")).Times(1)
+
+		result, err := service.List(ctx)
+		assert.Nil(t, result)
+		assert.Equal(t, domain.ErrListBillers, err)
+	})
+}
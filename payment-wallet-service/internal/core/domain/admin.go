@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Role is what an operator is allowed to do on the admin API.
+type Role string
+
+const (
+	// RoleSupportReadOnly looks up payments and balances of any user.
+	RoleSupportReadOnly Role = "support-readonly"
+	// RoleOps changes the status of wallets and payments, and tops up wallets.
+	RoleOps Role = "ops"
+	// RoleFinance adjusts balances and tops up wallets.
+	RoleFinance Role = "finance"
+)
+
+// Operator is the authenticated staff member acting on the admin API.
+type Operator struct {
+	ID    string `json:"id"`
+	Roles []Role `json:"roles"`
+}
+
+// HasAnyRole reports whether the operator holds at least one of the roles.
+func (o Operator) HasAnyRole(roles ...Role) bool {
+	for _, role := range roles {
+		if slices.Contains(o.Roles, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	AuditLookupPayments     = "LOOKUP_PAYMENTS"
+	AuditLookupBalance      = "LOOKUP_BALANCE"
+	AuditFreezeWallet       = "FREEZE_WALLET"
+	AuditUnfreezeWallet     = "UNFREEZE_WALLET"
+	AuditCloseWallet        = "CLOSE_WALLET"
+	AuditApprovePayment     = "APPROVE_PAYMENT"
+	AuditRejectPayment      = "REJECT_PAYMENT"
+	AuditAdjustBalance      = "ADJUST_BALANCE"
+	AuditTopUpWallet        = "TOP_UP_WALLET"
+	AuditForcePaymentStatus = "FORCE_PAYMENT_STATUS"
+)
+
+// AuditEntry records an action of an operator on the admin API. Entries are
+// append-only. TargetID is the user or payment acted on and Details holds the
+// parameters of the action as JSON.
+type AuditEntry struct {
+	ID            string    `json:"id"`
+	OperatorID    string    `json:"operator_id"`
+	OperatorRoles []Role    `json:"operator_roles"`
+	Action        string    `json:"action"`
+	TargetID      string    `json:"target_id"`
+	Reason        string    `json:"reason,omitempty"`
+	Details       []byte    `json:"details,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func NewAuditEntry(id string, operator Operator, action, targetID, reason string, details any) (AuditEntry, error) {
+	entry := AuditEntry{
+		ID:            id,
+		OperatorID:    operator.ID,
+		OperatorRoles: operator.Roles,
+		Action:        action,
+		TargetID:      targetID,
+		Reason:        reason,
+		CreatedAt:     time.Now(),
+	}
+
+	if details != nil {
+		payload, err := json.Marshal(details)
+		if err != nil {
+			return AuditEntry{}, err
+		}
+		entry.Details = payload
+	}
+
+	return entry, nil
+}
+
+// BalanceAdjustmentRequest corrects the available balance of a wallet by hand.
+// A positive amount credits the wallet and a negative one debits it.
+type BalanceAdjustmentRequest struct {
+	UserID   string   `json:"user_id"`
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+	Reason   string   `json:"reason"`
+}
+
+type BalanceAdjustment struct {
+	ID       string   `json:"id"`
+	UserID   string   `json:"user_id"`
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+	Reason   string   `json:"reason"`
+	Balance  Balance  `json:"balance"`
+}
+
+func (bar BalanceAdjustmentRequest) Validate() error {
+	err := validation.ValidateStruct(&bar,
+		validation.Field(&bar.UserID,
+			validation.Required),
+		validation.Field(&bar.Amount,
+			validation.Required),
+		validation.Field(&bar.Currency,
+			validation.Required,
+			validation.By(validCurrency)),
+		validation.Field(&bar.Reason,
+			validation.Required))
+	if err != nil {
+		return fmt.Errorf("error validating request %w", err)
+	}
+
+	return nil
+}
+
+// Money returns the size of the adjustment, without its sign, in its currency.
+func (bar BalanceAdjustmentRequest) Money() Money {
+	amount := bar.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+
+	return NewMoney(amount, bar.Currency)
+}
+
+// ForcePaymentStatusRequest settles a payment stuck before reaching a final
+// status, bypassing the processor.
+type ForcePaymentStatusRequest struct {
+	PaymentID string        `json:"payment_id"`
+	Status    PaymentStatus `json:"status"`
+	Reason    string        `json:"reason"`
+}
+
+func (fpr ForcePaymentStatusRequest) Validate() error {
+	err := validation.ValidateStruct(&fpr,
+		validation.Field(&fpr.PaymentID,
+			validation.Required),
+		validation.Field(&fpr.Status,
+			validation.Required,
+			validation.By(validStatus)),
+		validation.Field(&fpr.Reason,
+			validation.Required))
+	if err != nil {
+		return fmt.Errorf("error validating request %w", err)
+	}
+
+	return nil
+}
@@ -2,9 +2,36 @@ package domain
 
 import "time"
 
+type WalletStatus string
+
+const (
+	WalletActive WalletStatus = "ACTIVE"
+	WalletFrozen WalletStatus = "FROZEN"
+	WalletClosed WalletStatus = "CLOSED"
+)
+
+// _walletTransitions holds the statuses each wallet status can move to. Closed
+// wallets cannot be reopened.
+var _walletTransitions = map[WalletStatus][]WalletStatus{
+	WalletActive: {WalletFrozen, WalletClosed},
+	WalletFrozen: {WalletActive, WalletClosed},
+}
+
 type Balance struct {
-	UserID    string
-	Available int64
-	Reserved  int64
-	UpdatedAt time.Time
+	UserID    string       `json:"user_id"`
+	Available int64        `json:"available"`
+	Reserved  int64        `json:"reserved"`
+	Currency  Currency     `json:"currency"`
+	Status    WalletStatus `json:"status"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+func (ws WalletStatus) CanTransitionTo(next WalletStatus) bool {
+	for _, allowed := range _walletTransitions[ws] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// CheckDigit is the algorithm the last digit of a client number is verified
+// with.
+type CheckDigit string
+
+const (
+	CheckDigitNone CheckDigit = ""
+	CheckDigitLuhn CheckDigit = "LUHN"
+)
+
+// Biller is a payment entity users can pay to, identified by the service_id
+// of the payments. Amounts are in the minor unit of the currency it bills in;
+// a zero MaxAmount means there is no maximum.
+type Biller struct {
+	ID                  string     `json:"id"`
+	Name                string     `json:"name"`
+	Currency            Currency   `json:"currency"`
+	MinAmount           int64      `json:"min_amount"`
+	MaxAmount           int64      `json:"max_amount"`
+	Enabled             bool       `json:"enabled"`
+	ClientNumberPattern string     `json:"client_number_pattern,omitempty"`
+	CheckDigit          CheckDigit `json:"check_digit,omitempty"`
+}
+
+// Accept returns an error when the biller cannot be paid with the request:
+// it is disabled, bills in another currency, the amount is out of its range
+// or the client number does not have its format.
+func (b Biller) Accept(request CreatePaymentRequest) error {
+	if !b.Enabled {
+		return ErrBillerDisabled
+	}
+
+	if request.Currency != b.Currency {
+		return fmt.Errorf("%w: %s bills in %s", ErrBillerCurrencyMismatch, b.Name, b.Currency)
+	}
+
+	if request.Amount < b.MinAmount || (b.MaxAmount > 0 && request.Amount > b.MaxAmount) {
+		return fmt.Errorf("%w: %s accepts from %s%s", ErrAmountOutOfRange, b.Name,
+			NewMoney(b.MinAmount, b.Currency), b.maxSuffix())
+	}
+
+	return b.checkClientNumber(request.ClientNumber)
+}
+
+func (b Biller) maxSuffix() string {
+	if b.MaxAmount == 0 {
+		return ""
+	}
+
+	return " to " + NewMoney(b.MaxAmount, b.Currency).String()
+}
+
+func (b Biller) checkClientNumber(clientNumber string) error {
+	if b.ClientNumberPattern != "" {
+		// the pattern must match the whole client number, not a part of it
+		matched, err := regexp.MatchString("^(?:"+b.ClientNumberPattern+")$", clientNumber)
+		if err != nil {
+			return fmt.Errorf("client number pattern of biller %s: %w", b.ID, err)
+		}
+		if !matched {
+			return fmt.Errorf("%w: does not have the format of %s", ErrInvalidClientNumber, b.Name)
+		}
+	}
+
+	if b.CheckDigit == CheckDigitLuhn && !luhn(clientNumber) {
+		return fmt.Errorf("%w: wrong check digit", ErrInvalidClientNumber)
+	}
+
+	return nil
+}
+
+// luhn reports whether the number, digits only, passes the Luhn checksum.
+func luhn(number string) bool {
+	if len(number) < 2 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
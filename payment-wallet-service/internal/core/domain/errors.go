@@ -3,9 +3,60 @@ package domain
 import "errors"
 
 var (
-	ErrGetBalance        = errors.New("failed to get user balance")
-	ErrInsufficientFunds = errors.New("insufficient funds")
-	ErrReserveFunds      = errors.New("failed to reserve funds")
-	ErrCreatePayment     = errors.New("failed to create payment")
-	ErrCheckIdempotency  = errors.New("failed to check idempotency")
+	ErrGetBalance                  = errors.New("failed to get user balance")
+	ErrWalletNotFound              = errors.New("wallet not found")
+	ErrCurrencyNotHeld             = errors.New("wallet does not hold the currency")
+	ErrWalletAlreadyExists         = errors.New("wallet already exists")
+	ErrWalletFrozen                = errors.New("wallet is frozen")
+	ErrWalletClosed                = errors.New("wallet is closed")
+	ErrWalletHasReservedFunds      = errors.New("wallet has reserved funds")
+	ErrInvalidWalletTransition     = errors.New("invalid wallet status transition")
+	ErrCreateWallet                = errors.New("failed to create wallet")
+	ErrUpdateWallet                = errors.New("failed to update wallet")
+	ErrInsufficientFunds           = errors.New("insufficient funds")
+	ErrReserveFunds                = errors.New("failed to reserve funds")
+	ErrUpdateBalance               = errors.New("failed to update user balance")
+	ErrReservationNotFound         = errors.New("reservation not found")
+	ErrPostLedger                  = errors.New("failed to post ledger entries")
+	ErrGetStatement                = errors.New("failed to get statement")
+	ErrUnbalancedLedgerTransaction = errors.New("unbalanced ledger transaction")
+	ErrCreatePayment               = errors.New("failed to create payment")
+	ErrFXRateUnavailable           = errors.New("no exchange rate for the currency pair")
+	ErrGetFXRate                   = errors.New("failed to get exchange rate")
+	ErrLimitExceeded               = errors.New("spending limit exceeded")
+	ErrTopUpLimitExceeded          = errors.New("top-up exceeds the maximum amount")
+	ErrGetLimits                   = errors.New("failed to get spending limits")
+	ErrPaymentDenied               = errors.New("payment denied by risk evaluation")
+	ErrEvaluateRisk                = errors.New("failed to evaluate payment risk")
+	ErrPaymentNotUnderReview       = errors.New("payment is not pending review")
+	ErrPaymentNotForceable         = errors.New("only unsettled payments can be forced to a final status")
+	ErrRecordAudit                 = errors.New("failed to record audit entry")
+	ErrBillerNotFound              = errors.New("unknown biller")
+	ErrBillerDisabled              = errors.New("biller is disabled")
+	ErrBillerCurrencyMismatch      = errors.New("biller does not bill in the currency")
+	ErrAmountOutOfRange            = errors.New("amount out of the range accepted by the biller")
+	ErrInvalidClientNumber         = errors.New("invalid client number")
+	ErrGetBiller                   = errors.New("failed to get biller")
+	ErrListBillers                 = errors.New("failed to list billers")
+	ErrCheckIdempotency            = errors.New("failed to check idempotency")
+	ErrIdempotencyKeyReused        = errors.New("idempotency key already used with a different request")
+	ErrIdempotencyKeyConflict      = errors.New("a request with the same idempotency key is in progress")
+	ErrCreateOutboxMessage         = errors.New("failed to create outbox message")
+	ErrGetPayment                  = errors.New("failed to get payment")
+	ErrUpdatePayment               = errors.New("failed to update payment")
+	ErrPaymentNotFound             = errors.New("payment not found")
+	ErrPaymentNotCancellable       = errors.New("only pending payments can be cancelled")
+	ErrPaymentConflict             = errors.New("payment was modified concurrently")
+	ErrInvalidStatusTransition     = errors.New("invalid payment status transition")
+	ErrGetPaymentHistory           = errors.New("failed to get payment history")
+	ErrPaymentNotRefundable        = errors.New("only approved payments can be refunded")
+	ErrRefundExceedsPayment        = errors.New("refund exceeds the amount left to refund")
+	ErrCreateRefund                = errors.New("failed to create refund")
+	ErrCreditBalance               = errors.New("failed to credit user balance")
+	ErrCreateTopUp                 = errors.New("failed to create top-up")
+	ErrCreateTransfer              = errors.New("failed to create transfer")
+	ErrListPayments                = errors.New("failed to list payments")
+	ErrInvalidPaymentFilter        = errors.New("invalid payment filter")
+	ErrInvalidCursor               = errors.New("invalid cursor")
+	ErrInvalidPaymentResult        = errors.New("invalid payment result")
 )
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"math/big"
+	"time"
+)
+
+// RoundingMode tells how a converted amount is rounded to the minor unit of
+// its currency.
+type RoundingMode string
+
+const (
+	// RoundUp rounds away from zero, so the wallet is never charged less than
+	// the converted invoice. It is the default.
+	RoundUp     RoundingMode = "UP"
+	RoundDown   RoundingMode = "DOWN"
+	RoundHalfUp RoundingMode = "HALF_UP"
+)
+
+// RateDecimals is the precision quoted rates are locked at. Converted amounts
+// are computed from the locked rate, so storing it is enough to reproduce them.
+const RateDecimals = 10
+
+// FXQuote is a rate locked to convert Source into the currency of Target.
+// Rate is the number of major units of the target currency paid per major
+// unit of the source one, spread included.
+type FXQuote struct {
+	ID        string    `json:"id"`
+	Source    Money     `json:"source"`
+	Target    Money     `json:"target"`
+	Rate      string    `json:"rate"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (rm RoundingMode) Valid() bool {
+	switch rm {
+	case RoundUp, RoundDown, RoundHalfUp:
+		return true
+	}
+
+	return false
+}
+
+// OrDefault returns the rounding mode, or RoundUp when it is empty.
+func (rm RoundingMode) OrDefault() RoundingMode {
+	if rm == "" {
+		return RoundUp
+	}
+
+	return rm
+}
+
+// Convert converts amount into the currency to at rate, given in major units
+// of to per major unit of the amount currency, and rounds the result to the
+// minor unit of to.
+func Convert(amount Money, to Currency, rate *big.Rat, mode RoundingMode) Money {
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount.Amount), rate)
+
+	// rescale from the minor unit of the source to the one of the target
+	shift := to.Exponent() - amount.Currency.Exponent()
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		value.Mul(value, scale)
+	} else {
+		value.Quo(value, scale)
+	}
+
+	return NewMoney(round(value, mode).Int64(), to)
+}
+
+// LockRate rounds rate half up to RateDecimals, the precision it is stored with.
+func LockRate(rate *big.Rat) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(RateDecimals), nil)
+	scaled := new(big.Rat).Mul(rate, new(big.Rat).SetInt(scale))
+
+	return new(big.Rat).SetFrac(round(scaled, RoundHalfUp), scale)
+}
+
+// round rounds a non-negative value to an integer.
+func round(value *big.Rat, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	switch mode {
+	case RoundDown:
+		return quo
+	case RoundHalfUp:
+		// the remainder is at least half of the denominator
+		if new(big.Int).Lsh(rem, 1).Cmp(value.Denom()) >= 0 {
+			return quo.Add(quo, big.NewInt(1))
+		}
+		return quo
+	default:
+		return quo.Add(quo, big.NewInt(1))
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,137 @@
+package domain
+
+import "time"
+
+// Ledger accounts. Wallet accounts belong to the user and grow with credits;
+// funding and settlement are the system counterparts where money enters and
+// leaves the wallets. Transfers is the clearing account between two wallets,
+// left at zero once both legs of a transfer are posted. Adjustments is the
+// counterpart of the corrections made by hand by operators.
+const (
+	AccountAvailable   = "available"
+	AccountReserved    = "reserved"
+	AccountFunding     = "funding"
+	AccountSettlement  = "settlement"
+	AccountTransfers   = "transfers"
+	AccountAdjustments = "adjustments"
+)
+
+const (
+	EntryDebit  = "DEBIT"
+	EntryCredit = "CREDIT"
+)
+
+const (
+	MovementOpening = "OPENING"
+	MovementReserve = "RESERVE"
+	MovementConfirm = "CONFIRM"
+	MovementRelease = "RELEASE"
+	MovementTopUp   = "TOP_UP"
+	MovementRefund  = "REFUND"
+
+	MovementTransferOut = "TRANSFER_OUT"
+	MovementTransferIn  = "TRANSFER_IN"
+
+	MovementAdjustmentCredit = "ADJUSTMENT_CREDIT"
+	MovementAdjustmentDebit  = "ADJUSTMENT_DEBIT"
+)
+
+// _movementAccounts holds the account debited and the account credited by
+// each kind of balance movement.
+var _movementAccounts = map[string][2]string{
+	MovementOpening: {AccountFunding, AccountAvailable},
+	MovementReserve: {AccountAvailable, AccountReserved},
+	MovementConfirm: {AccountReserved, AccountSettlement},
+	MovementRelease: {AccountReserved, AccountAvailable},
+	MovementTopUp:   {AccountFunding, AccountAvailable},
+	MovementRefund:  {AccountSettlement, AccountAvailable},
+
+	MovementTransferOut: {AccountAvailable, AccountTransfers},
+	MovementTransferIn:  {AccountTransfers, AccountAvailable},
+
+	MovementAdjustmentCredit: {AccountAdjustments, AccountAvailable},
+	MovementAdjustmentDebit:  {AccountAvailable, AccountAdjustments},
+}
+
+type LedgerEntry struct {
+	ID            int64     `json:"id"`
+	TransactionID string    `json:"transaction_id"`
+	Movement      string    `json:"movement"`
+	UserID        string    `json:"user_id"`
+	Account       string    `json:"account"`
+	Direction     string    `json:"direction"`
+	Amount        int64     `json:"amount"`
+	Currency      Currency  `json:"currency"`
+	ReferenceID   string    `json:"reference_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LedgerTransaction groups the postings of a single balance movement.
+type LedgerTransaction struct {
+	ID      string
+	Entries []LedgerEntry
+}
+
+type Statement struct {
+	UserID     string        `json:"user_id"`
+	Available  int64         `json:"available"`
+	Reserved   int64         `json:"reserved"`
+	Currency   Currency      `json:"currency"`
+	Reconciled bool          `json:"reconciled"`
+	Entries    []LedgerEntry `json:"entries"`
+}
+
+// NewLedgerTransaction builds the balanced debit/credit pair that records a
+// movement of amount on the user's wallet, referencing the operation that
+// caused it (payment, top-up, refund, transfer).
+func NewLedgerTransaction(id, movement, userID, referenceID string, amount Money) LedgerTransaction {
+	accounts := _movementAccounts[movement]
+	now := time.Now()
+
+	entry := func(account, direction string) LedgerEntry {
+		return LedgerEntry{
+			TransactionID: id,
+			Movement:      movement,
+			UserID:        userID,
+			Account:       account,
+			Direction:     direction,
+			Amount:        amount.Amount,
+			Currency:      amount.Currency,
+			ReferenceID:   referenceID,
+			CreatedAt:     now,
+		}
+	}
+
+	return LedgerTransaction{
+		ID: id,
+		Entries: []LedgerEntry{
+			entry(accounts[0], EntryDebit),
+			entry(accounts[1], EntryCredit),
+		},
+	}
+}
+
+// Balanced reports whether debits and credits of the transaction add up.
+func (lt LedgerTransaction) Balanced() bool {
+	if len(lt.Entries) == 0 {
+		return false
+	}
+
+	var total int64
+	for _, entry := range lt.Entries {
+		if entry.Amount <= 0 || entry.Account == "" {
+			return false
+		}
+
+		switch entry.Direction {
+		case EntryDebit:
+			total += entry.Amount
+		case EntryCredit:
+			total -= entry.Amount
+		default:
+			return false
+		}
+	}
+
+	return total == 0
+}
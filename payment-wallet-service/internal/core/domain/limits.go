@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// LimitKind identifies each of the spending limits checked before a payment.
+type LimitKind string
+
+const (
+	LimitPerTransaction LimitKind = "PER_TRANSACTION"
+	LimitDailyAmount    LimitKind = "DAILY_AMOUNT"
+	LimitMonthlyAmount  LimitKind = "MONTHLY_AMOUNT"
+	LimitHourlyCount    LimitKind = "HOURLY_COUNT"
+)
+
+// DefaultLimitTier is the tier of users without one assigned, unless the
+// configuration names another.
+const DefaultLimitTier = "STANDARD"
+
+// Limits caps the payments funded from a wallet. Amounts are in the minor unit
+// of the wallet currency. A zero value means the limit is not enforced.
+type Limits struct {
+	PerTransaction int64
+	DailyAmount    int64
+	MonthlyAmount  int64
+	HourlyCount    int64
+}
+
+// LimitOverride is the limits configuration of a single wallet. Tier replaces
+// the default tier and every non nil limit replaces the one of the tier.
+type LimitOverride struct {
+	Tier           string
+	PerTransaction *int64
+	DailyAmount    *int64
+	MonthlyAmount  *int64
+	HourlyCount    *int64
+}
+
+// LimitWindows are the starts of the periods the usage is accumulated over.
+// Days and months are calendar ones in UTC; the hour is rolling.
+type LimitWindows struct {
+	Day   time.Time
+	Month time.Time
+	Hour  time.Time
+}
+
+// LimitUsage is what the payments of a wallet already consumed in each window.
+type LimitUsage struct {
+	DailyAmount   int64
+	MonthlyAmount int64
+	HourlyCount   int64
+}
+
+// LimitExceededError tells which limit a payment hits. It matches
+// ErrLimitExceeded with errors.Is.
+type LimitExceededError struct {
+	Limit     LimitKind `json:"limit"`
+	Max       int64     `json:"max"`
+	Used      int64     `json:"used"`
+	Requested int64     `json:"requested"`
+	Currency  Currency  `json:"currency"`
+}
+
+func NewLimitWindows(now time.Time) LimitWindows {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	return LimitWindows{
+		Day:   day,
+		Month: day.AddDate(0, 0, 1-day.Day()),
+		Hour:  now.Add(-time.Hour),
+	}
+}
+
+// Apply returns the limits with the overrides that are set.
+func (l Limits) Apply(override LimitOverride) Limits {
+	set := func(limit *int64, value *int64) {
+		if value != nil {
+			*limit = *value
+		}
+	}
+
+	set(&l.PerTransaction, override.PerTransaction)
+	set(&l.DailyAmount, override.DailyAmount)
+	set(&l.MonthlyAmount, override.MonthlyAmount)
+	set(&l.HourlyCount, override.HourlyCount)
+
+	return l
+}
+
+// Check returns a *LimitExceededError for the first limit amount would exceed
+// on top of usage, or nil when it fits all of them.
+func (l Limits) Check(amount Money, usage LimitUsage) error {
+	exceeded := func(kind LimitKind, max, used, requested int64) error {
+		if max == 0 || used+requested <= max {
+			return nil
+		}
+
+		return &LimitExceededError{
+			Limit:     kind,
+			Max:       max,
+			Used:      used,
+			Requested: requested,
+			Currency:  amount.Currency,
+		}
+	}
+
+	if err := exceeded(LimitPerTransaction, l.PerTransaction, 0, amount.Amount); err != nil {
+		return err
+	}
+	if err := exceeded(LimitDailyAmount, l.DailyAmount, usage.DailyAmount, amount.Amount); err != nil {
+		return err
+	}
+	if err := exceeded(LimitMonthlyAmount, l.MonthlyAmount, usage.MonthlyAmount, amount.Amount); err != nil {
+		return err
+	}
+
+	return exceeded(LimitHourlyCount, l.HourlyCount, usage.HourlyCount, 1)
+}
+
+func (e *LimitExceededError) Error() string {
+	if e.Limit == LimitHourlyCount {
+		return fmt.Sprintf("%s: %s of %d payments, %d already made",
+			ErrLimitExceeded, e.Limit, e.Max, e.Used)
+	}
+
+	return fmt.Sprintf("%s: %s of %s, %s already used, %s requested",
+		ErrLimitExceeded, e.Limit,
+		NewMoney(e.Max, e.Currency), NewMoney(e.Used, e.Currency), NewMoney(e.Requested, e.Currency))
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
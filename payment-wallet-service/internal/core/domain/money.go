@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Currency is an ISO-4217 alphabetic currency code.
+type Currency string
+
+const (
+	CurrencyARS Currency = "ARS"
+	CurrencyBRL Currency = "BRL"
+	CurrencyCLP Currency = "CLP"
+	CurrencyMXN Currency = "MXN"
+	CurrencyUYU Currency = "UYU"
+	CurrencyUSD Currency = "USD"
+
+	// DefaultCurrency is assumed by requests that do not state one, matching
+	// the single currency the service handled before wallets became
+	// multi-currency.
+	DefaultCurrency = CurrencyARS
+)
+
+// _currencyExponents holds the number of decimal digits of the minor unit of
+// each supported currency.
+var _currencyExponents = map[Currency]int{
+	CurrencyARS: 2,
+	CurrencyBRL: 2,
+	CurrencyCLP: 0,
+	CurrencyMXN: 2,
+	CurrencyUYU: 2,
+	CurrencyUSD: 2,
+}
+
+// Money is an amount in the minor unit of its currency, e.g. cents.
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (c Currency) Valid() bool {
+	_, ok := _currencyExponents[c]
+	return ok
+}
+
+// Exponent returns the number of decimal digits of the minor unit.
+func (c Currency) Exponent() int {
+	return _currencyExponents[c]
+}
+
+// OrDefault returns the currency, or DefaultCurrency when it is empty.
+func (c Currency) OrDefault() Currency {
+	if c == "" {
+		return DefaultCurrency
+	}
+
+	return c
+}
+
+// String formats the amount in major units, e.g. "1234.50 ARS".
+func (m Money) String() string {
+	exponent := m.Currency.Exponent()
+	if exponent == 0 {
+		return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	scale := int64(1)
+	for i := 0; i < exponent; i++ {
+		scale *= 10
+	}
+
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/scale, exponent, amount%scale, m.Currency)
+}
+
+func validCurrency(value interface{}) error {
+	v, _ := value.(Currency)
+	if !v.Valid() {
+		return fmt.Errorf("must be a supported ISO-4217 currency code")
+	}
+	return nil
+}
+
+// ParseCurrency normalizes a currency code read from a request, rejecting
+// unsupported ones.
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code))).OrDefault()
+	if err := validCurrency(currency); err != nil {
+		return "", err
+	}
+
+	return currency, nil
+}
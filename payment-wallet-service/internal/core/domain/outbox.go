@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	EventTypePaymentInitiated  = "PaymentInitiated"
+	EventTypePaymentCancelled  = "PaymentCancelled"
+	EventTypePaymentExpired    = "PaymentExpired"
+	EventTypeRefundRequested   = "RefundRequested"
+	EventTypeWalletCredited    = "WalletCredited"
+	EventTypeTransferCompleted = "TransferCompleted"
+)
+
+// OutboxMessage is an event persisted in the same transaction as the change
+// that produced it, waiting to be relayed to the broker.
+type OutboxMessage struct {
+	ID          string
+	AggregateID string
+	EventType   string
+	Payload     []byte
+	Attempts    int
+	CreatedAt   time.Time
+	// TraceContext holds the propagation headers of the trace that wrote the
+	// message, so its publication continues that trace.
+	TraceContext map[string]string
+	// RequestID is the ID of the request that wrote the message, if any.
+	RequestID string
+}
+
+func NewOutboxMessage(id, eventType, aggregateID string, event any) (OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+
+	return OutboxMessage{
+		ID:          id,
+		AggregateID: aggregateID,
+		EventType:   eventType,
+		Payload:     payload,
+		CreatedAt:   time.Now(),
+	}, nil
+}
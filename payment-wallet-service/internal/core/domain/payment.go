@@ -3,38 +3,108 @@ package domain
 import (
 	"fmt"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"math/big"
 	"time"
 )
 
 type CreatePaymentRequest struct {
-	UserID         string `json:"user_id"`
-	ClientNumber   string `json:"client_number"`
-	ServiceID      string `json:"service_id"`
-	Amount         int64  `json:"amount"`
-	IdempotencyKey string `json:"idempotency_key"`
+	UserID       string   `json:"user_id"`
+	ClientNumber string   `json:"client_number"`
+	ServiceID    string   `json:"service_id"`
+	Amount       int64    `json:"amount"`
+	Currency     Currency `json:"currency"`
+	// FundingCurrency is the wallet the payment is taken from. When it differs
+	// from Currency the amount is converted at a quoted rate.
+	FundingCurrency Currency        `json:"funding_currency"`
+	IdempotencyKey  string          `json:"idempotency_key"`
+	Metadata        RequestMetadata `json:"-"`
 }
 
 type Payment struct {
-	ID             string    `json:"id"`
-	IdempotencyKey string    `json:"idempotency_key"`
-	UserID         string    `json:"user_id"`
-	Amount         int64     `json:"amount"`
-	Status         string    `json:"status"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
-	ServiceID      string    `json:"service_id"`
-	ClientNumber   string    `json:"client_number"`
+	ID                 string        `json:"id"`
+	IdempotencyKey     string        `json:"idempotency_key"`
+	UserID             string        `json:"user_id"`
+	Amount             int64         `json:"amount"`
+	Currency           Currency      `json:"currency"`
+	Status             PaymentStatus `json:"status"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UpdatedAt          time.Time     `json:"updated_at"`
+	ServiceID          string        `json:"service_id"`
+	ClientNumber       string        `json:"client_number"`
+	ProcessorReference string        `json:"processor_reference,omitempty"`
+	FailureReason      string        `json:"failure_reason,omitempty"`
+	RefundedAmount     int64         `json:"refunded_amount"`
+	FundingAmount      int64         `json:"funding_amount"`
+	FundingCurrency    Currency      `json:"funding_currency"`
+	FXRate             string        `json:"fx_rate,omitempty"`
+	FXQuoteID          string        `json:"fx_quote_id,omitempty"`
+	Version            int64         `json:"-"`
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Reasons a payment request is refused before the payment is created, as
+// counted by the business metrics.
+const (
+	RefusalIdempotencyKeyReused = "idempotency_key_reused"
+	RefusalBiller               = "biller"
+	RefusalRiskDenied           = "risk_denied"
+	RefusalInsufficientFunds    = "insufficient_funds"
+	RefusalLimitExceeded        = "limit_exceeded"
+)
+
+// PaymentFilter narrows the payment history of a user. Zero values mean the
+// filter is not applied; Cursor continues a previous page.
+type PaymentFilter struct {
+	UserID      string
+	Status      PaymentStatus
+	ServiceID   string
+	MinAmount   int64
+	MaxAmount   int64
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Cursor      string
+	Limit       int
+}
+
+type PaymentPage struct {
+	Payments   []Payment `json:"payments"`
+	NextCursor string    `json:"next_cursor,omitempty"`
 }
 
 type PaymentInitiatedEvent struct {
+	UserID          string   `json:"user_id"`
+	ClientNumber    string   `json:"client_number"`
+	ServiceID       string   `json:"service_id"`
+	Amount          int64    `json:"amount"`
+	Currency        Currency `json:"currency"`
+	FundingAmount   int64    `json:"funding_amount"`
+	FundingCurrency Currency `json:"funding_currency"`
+	FXRate          string   `json:"fx_rate,omitempty"`
+	FXQuoteID       string   `json:"fx_quote_id,omitempty"`
+	TransactionID   string   `json:"transaction_id"`
+	RequestID       string   `json:"request_id,omitempty"`
+}
+
+type PaymentCancelledEvent struct {
 	UserID        string `json:"user_id"`
-	ClientNumber  string `json:"client_number"`
-	ServiceID     string `json:"service_id"`
-	Amount        int64  `json:"amount"`
 	TransactionID string `json:"transaction_id"`
 }
 
-type PaymentResultEvent struct{}
+type PaymentExpiredEvent struct {
+	UserID        string `json:"user_id"`
+	TransactionID string `json:"transaction_id"`
+}
+
+type PaymentResultEvent struct {
+	TransactionID      string        `json:"transaction_id"`
+	Status             PaymentStatus `json:"status"`
+	ProcessorReference string        `json:"processor_reference"`
+	FailureReason      string        `json:"failure_reason"`
+}
 
 func (cpr CreatePaymentRequest) Validate() error {
 	err := validation.ValidateStruct(&cpr,
@@ -45,6 +115,12 @@ func (cpr CreatePaymentRequest) Validate() error {
 		validation.Field(&cpr.Amount,
 			validation.Required,
 			validation.By(validAmount)),
+		validation.Field(&cpr.Currency,
+			validation.Required,
+			validation.By(validCurrency)),
+		validation.Field(&cpr.FundingCurrency,
+			validation.Required,
+			validation.By(validCurrency)),
 		validation.Field(&cpr.ServiceID,
 			validation.Required),
 		validation.Field(&cpr.ClientNumber,
@@ -56,6 +132,82 @@ func (cpr CreatePaymentRequest) Validate() error {
 	return nil
 }
 
+// Matches reports whether the payment was created from an equivalent request,
+// which is what makes replaying an idempotency key safe.
+func (p Payment) Matches(request CreatePaymentRequest) bool {
+	return p.UserID == request.UserID &&
+		p.Amount == request.Amount &&
+		p.Currency == request.Currency &&
+		p.FundingCurrency == request.FundingCurrency &&
+		p.ServiceID == request.ServiceID &&
+		p.ClientNumber == request.ClientNumber
+}
+
+// Money returns the amount of the payment in its currency.
+func (p Payment) Money() Money {
+	return NewMoney(p.Amount, p.Currency)
+}
+
+// Funding returns the amount reserved from the wallet of the user, in the
+// wallet currency.
+func (p Payment) Funding() Money {
+	return NewMoney(p.FundingAmount, p.FundingCurrency)
+}
+
+// FundingRefund returns the part of the funding amount given back when amount
+// is refunded on top of RefundedAmount. Each refund takes its share at the
+// rate locked on creation rounded down, computed as the difference of the
+// cumulative shares, so the refunds of the whole payment add up to exactly
+// the funded amount.
+func (p Payment) FundingRefund(amount int64) Money {
+	share := func(refunded int64) int64 {
+		value := new(big.Int).Mul(big.NewInt(p.FundingAmount), big.NewInt(refunded))
+		return value.Quo(value, big.NewInt(p.Amount)).Int64()
+	}
+
+	return NewMoney(share(p.RefundedAmount+amount)-share(p.RefundedAmount), p.FundingCurrency)
+}
+
+func (pre PaymentResultEvent) Validate() error {
+	err := validation.ValidateStruct(&pre,
+		validation.Field(&pre.TransactionID,
+			validation.Required),
+		validation.Field(&pre.Status,
+			validation.Required,
+			validation.In(StatusProcessing, StatusApproved, StatusRejected)))
+	if err != nil {
+		return fmt.Errorf("error validating event %w", err)
+	}
+
+	return nil
+}
+
+func (pf PaymentFilter) Validate() error {
+	err := validation.ValidateStruct(&pf,
+		validation.Field(&pf.UserID,
+			validation.Required),
+		validation.Field(&pf.Status,
+			validation.When(pf.Status != "",
+				validation.By(validStatus))),
+		validation.Field(&pf.Limit,
+			validation.Min(1),
+			validation.Max(MaxPageSize)),
+		validation.Field(&pf.MinAmount,
+			validation.Min(int64(0))),
+		validation.Field(&pf.MaxAmount,
+			validation.Min(int64(0)),
+			validation.When(pf.MaxAmount > 0 && pf.MinAmount > 0,
+				validation.Min(pf.MinAmount).Error("must be greater than or equal to min_amount"))),
+		validation.Field(&pf.CreatedTo,
+			validation.When(!pf.CreatedTo.IsZero() && !pf.CreatedFrom.IsZero(),
+				validation.Min(pf.CreatedFrom).Error("must be after created_from"))))
+	if err != nil {
+		return fmt.Errorf("error validating filter %w", err)
+	}
+
+	return nil
+}
+
 func validAmount(value interface{}) error {
 	v, _ := value.(int64)
 	if v <= 0 {
@@ -63,3 +215,11 @@ func validAmount(value interface{}) error {
 	}
 	return nil
 }
+
+func validStatus(value interface{}) error {
+	v, _ := value.(PaymentStatus)
+	if !v.Valid() {
+		return fmt.Errorf("must be a valid payment status")
+	}
+	return nil
+}
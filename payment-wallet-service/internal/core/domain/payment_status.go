@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type PaymentStatus string
+
+const (
+	StatusPending    PaymentStatus = "PENDING"
+	StatusProcessing PaymentStatus = "PROCESSING"
+	StatusApproved   PaymentStatus = "APPROVED"
+	StatusRejected   PaymentStatus = "REJECTED"
+	StatusCancelled  PaymentStatus = "CANCELLED"
+	StatusExpired    PaymentStatus = "EXPIRED"
+	StatusRefunded   PaymentStatus = "REFUNDED"
+
+	StatusPartiallyRefunded PaymentStatus = "PARTIALLY_REFUNDED"
+
+	// StatusPendingReview holds a payment flagged by the risk evaluation. Its
+	// funds are reserved but it is not sent to the processor until approved.
+	StatusPendingReview PaymentStatus = "PENDING_REVIEW"
+)
+
+// _paymentTransitions holds the statuses each status can move to. The
+// processor may report the result of a payment it never acknowledged as
+// PROCESSING, so pending payments can be settled directly. Once the processor
+// picked a payment up it can no longer be cancelled by the user. Payments
+// under review are released to PENDING or rejected by an admin.
+var _paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	StatusPending:    {StatusProcessing, StatusApproved, StatusRejected, StatusCancelled, StatusExpired},
+	StatusProcessing: {StatusApproved, StatusRejected, StatusExpired},
+	StatusApproved:   {StatusPartiallyRefunded, StatusRefunded},
+
+	StatusPartiallyRefunded: {StatusPartiallyRefunded, StatusRefunded},
+	StatusPendingReview:     {StatusPending, StatusRejected, StatusCancelled},
+}
+
+// PaymentStatusChange is an entry of the timeline of a payment. From is empty
+// for the change that created the payment.
+type PaymentStatusChange struct {
+	PaymentID string        `json:"payment_id"`
+	From      PaymentStatus `json:"from,omitempty"`
+	To        PaymentStatus `json:"to"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+func (ps PaymentStatus) Valid() bool {
+	switch ps {
+	case StatusPendingReview, StatusPending, StatusProcessing, StatusApproved, StatusRejected,
+		StatusCancelled, StatusExpired, StatusRefunded, StatusPartiallyRefunded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ps PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range _paymentTransitions[ps] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Final reports whether the payment settled its reservation, either charging
+// or releasing it.
+func (ps PaymentStatus) Final() bool {
+	return ps != StatusPendingReview && ps != StatusPending && ps != StatusProcessing
+}
+
+// Refundable returns the part of the payment amount not refunded yet, zero
+// when the payment was never charged.
+func (p Payment) Refundable() int64 {
+	if p.Status != StatusApproved && p.Status != StatusPartiallyRefunded {
+		return 0
+	}
+
+	return p.Amount - p.RefundedAmount
+}
+
+// TransitionTo moves the payment to next, rejecting transitions the state
+// machine does not allow.
+func (p *Payment) TransitionTo(next PaymentStatus) error {
+	if !p.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, p.Status, next)
+	}
+
+	p.Status = next
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ForceTo settles a payment that has not reached a final status yet, moving it
+// to any final status other than the refunded ones, whatever the state machine
+// allows. It is the way out for payments the processor never settles, so only
+// a payment sent to the processor can be forced to approved; a held one was
+// never charged.
+func (p *Payment) ForceTo(next PaymentStatus) error {
+	if p.Status.Final() || !next.Final() || next == StatusRefunded || next == StatusPartiallyRefunded ||
+		(next == StatusApproved && p.Status == StatusPendingReview) {
+		return fmt.Errorf("%w: %s to %s", ErrPaymentNotForceable, p.Status, next)
+	}
+
+	p.Status = next
+	p.UpdatedAt = time.Now()
+
+	return nil
+}
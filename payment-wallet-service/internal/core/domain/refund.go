@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type CreateRefundRequest struct {
+	UserID         string `json:"user_id"`
+	PaymentID      string `json:"payment_id"`
+	Amount         int64  `json:"amount"`
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+type Refund struct {
+	ID             string    `json:"id"`
+	PaymentID      string    `json:"payment_id"`
+	UserID         string    `json:"user_id"`
+	Amount         int64     `json:"amount"`
+	Currency       Currency  `json:"currency"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type RefundRequestedEvent struct {
+	RefundID      string   `json:"refund_id"`
+	TransactionID string   `json:"transaction_id"`
+	UserID        string   `json:"user_id"`
+	Amount        int64    `json:"amount"`
+	Currency      Currency `json:"currency"`
+}
+
+func (crr CreateRefundRequest) Validate() error {
+	err := validation.ValidateStruct(&crr,
+		validation.Field(&crr.IdempotencyKey,
+			validation.Required),
+		validation.Field(&crr.UserID,
+			validation.Required),
+		validation.Field(&crr.PaymentID,
+			validation.Required),
+		validation.Field(&crr.Amount,
+			validation.Required,
+			validation.By(validAmount)))
+	if err != nil {
+		return fmt.Errorf("error validating request %w", err)
+	}
+
+	return nil
+}
+
+// Money returns the amount of the refund in the currency of the payment.
+func (r Refund) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+// Matches reports whether the refund was created from an equivalent request.
+func (r Refund) Matches(request CreateRefundRequest) bool {
+	return r.UserID == request.UserID &&
+		r.PaymentID == request.PaymentID &&
+		r.Amount == request.Amount
+}
@@ -0,0 +1,34 @@
+package domain
+
+// RiskDecision is the verdict of the risk evaluation of a new payment.
+type RiskDecision string
+
+const (
+	RiskAllow RiskDecision = "ALLOW"
+	// RiskReview holds the payment in PENDING_REVIEW, with its funds reserved,
+	// until an admin approves or rejects it.
+	RiskReview RiskDecision = "REVIEW"
+	RiskDeny   RiskDecision = "DENY"
+)
+
+// RequestMetadata describes where a request came from. It is taken from the
+// HTTP request rather than from the body, so clients cannot set it.
+type RequestMetadata struct {
+	IP       string `json:"-"`
+	DeviceID string `json:"-"`
+}
+
+// RiskInput is what a payment is evaluated on. Funding is the amount reserved
+// from the wallet and History the recent payments of the user, newest first.
+type RiskInput struct {
+	Request  CreatePaymentRequest
+	Funding  Money
+	History  []Payment
+	Metadata RequestMetadata
+}
+
+// RiskAssessment is the decision on a payment and the rules that led to it.
+type RiskAssessment struct {
+	Decision RiskDecision
+	Reasons  []string
+}
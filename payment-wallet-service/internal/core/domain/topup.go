@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type CreateTopUpRequest struct {
+	UserID         string   `json:"user_id"`
+	Amount         int64    `json:"amount"`
+	Currency       Currency `json:"currency"`
+	IdempotencyKey string   `json:"idempotency_key"`
+}
+
+type TopUp struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id"`
+	Amount         int64     `json:"amount"`
+	Currency       Currency  `json:"currency"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type WalletCreditedEvent struct {
+	TopUpID  string   `json:"topup_id"`
+	UserID   string   `json:"user_id"`
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func (ctr CreateTopUpRequest) Validate() error {
+	err := validation.ValidateStruct(&ctr,
+		validation.Field(&ctr.IdempotencyKey,
+			validation.Required),
+		validation.Field(&ctr.UserID,
+			validation.Required),
+		validation.Field(&ctr.Amount,
+			validation.Required,
+			validation.By(validAmount)),
+		validation.Field(&ctr.Currency,
+			validation.Required,
+			validation.By(validCurrency)))
+	if err != nil {
+		return fmt.Errorf("error validating request %w", err)
+	}
+
+	return nil
+}
+
+// Money returns the amount of the top-up in its currency.
+func (t TopUp) Money() Money {
+	return NewMoney(t.Amount, t.Currency)
+}
+
+// Matches reports whether the top-up was created from an equivalent request.
+func (t TopUp) Matches(request CreateTopUpRequest) bool {
+	return t.UserID == request.UserID &&
+		t.Amount == request.Amount &&
+		t.Currency == request.Currency
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type CreateTransferRequest struct {
+	FromUserID     string   `json:"from_user_id"`
+	ToUserID       string   `json:"to_user_id"`
+	Amount         int64    `json:"amount"`
+	Currency       Currency `json:"currency"`
+	IdempotencyKey string   `json:"idempotency_key"`
+}
+
+type Transfer struct {
+	ID             string    `json:"id"`
+	FromUserID     string    `json:"from_user_id"`
+	ToUserID       string    `json:"to_user_id"`
+	Amount         int64     `json:"amount"`
+	Currency       Currency  `json:"currency"`
+	IdempotencyKey string    `json:"idempotency_key"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type TransferCompletedEvent struct {
+	TransferID string   `json:"transfer_id"`
+	FromUserID string   `json:"from_user_id"`
+	ToUserID   string   `json:"to_user_id"`
+	Amount     int64    `json:"amount"`
+	Currency   Currency `json:"currency"`
+}
+
+func (ctr CreateTransferRequest) Validate() error {
+	err := validation.ValidateStruct(&ctr,
+		validation.Field(&ctr.IdempotencyKey,
+			validation.Required),
+		validation.Field(&ctr.FromUserID,
+			validation.Required),
+		validation.Field(&ctr.ToUserID,
+			validation.Required,
+			validation.By(func(value interface{}) error {
+				if value == ctr.FromUserID {
+					return errors.New("cannot transfer to the same wallet")
+				}
+				return nil
+			})),
+		validation.Field(&ctr.Amount,
+			validation.Required,
+			validation.By(validAmount)),
+		validation.Field(&ctr.Currency,
+			validation.Required,
+			validation.By(validCurrency)))
+	if err != nil {
+		return fmt.Errorf("error validating request %w", err)
+	}
+
+	return nil
+}
+
+// Money returns the amount of the transfer in its currency.
+func (t Transfer) Money() Money {
+	return NewMoney(t.Amount, t.Currency)
+}
+
+// Matches reports whether the transfer was created from an equivalent request.
+func (t Transfer) Matches(request CreateTransferRequest) bool {
+	return t.FromUserID == request.FromUserID &&
+		t.ToUserID == request.ToUserID &&
+		t.Amount == request.Amount &&
+		t.Currency == request.Currency
+}
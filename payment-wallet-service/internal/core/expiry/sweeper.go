@@ -0,0 +1,215 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	_defaultTTL          = 15 * time.Minute
+	_defaultPollInterval = 30 * time.Second
+	_defaultBatchSize    = 100
+)
+
+type SweeperConfig struct {
+	Logger            *slog.Logger
+	DB                ports.Database
+	PaymentRepository ports.PaymentRepository
+	BalanceService    ports.BalanceService
+	OutboxRepository  ports.OutboxRepository
+	Metrics           ports.PaymentMetrics
+	TTL               time.Duration
+	PollInterval      time.Duration
+	BatchSize         int
+}
+
+// Sweeper expires payments the processor never answered and releases their
+// reserved funds. A payment is locked while it is expired, so several replicas
+// can sweep at the same time without expiring a payment twice.
+type Sweeper struct {
+	logger         *slog.Logger
+	db             ports.Database
+	paymentRepo    ports.PaymentRepository
+	balanceService ports.BalanceService
+	outboxRepo     ports.OutboxRepository
+	metrics        ports.PaymentMetrics
+	ttl            time.Duration
+	pollInterval   time.Duration
+	batchSize      int
+	done           chan struct{}
+	closeOnce      sync.Once
+}
+
+func NewSweeper(config SweeperConfig) *Sweeper {
+	sweeper := &Sweeper{
+		logger:         config.Logger,
+		db:             config.DB,
+		paymentRepo:    config.PaymentRepository,
+		balanceService: config.BalanceService,
+		outboxRepo:     config.OutboxRepository,
+		metrics:        config.Metrics,
+		ttl:            config.TTL,
+		pollInterval:   config.PollInterval,
+		batchSize:      config.BatchSize,
+		done:           make(chan struct{}),
+	}
+
+	if sweeper.ttl <= 0 {
+		sweeper.ttl = _defaultTTL
+	}
+	if sweeper.pollInterval <= 0 {
+		sweeper.pollInterval = _defaultPollInterval
+	}
+	if sweeper.batchSize <= 0 {
+		sweeper.batchSize = _defaultBatchSize
+	}
+
+	return sweeper
+}
+
+// Start sweeps periodically until the context is cancelled or the sweeper is
+// closed.
+func (s *Sweeper) Start(ctx context.Context) {
+	s.logger.InfoContext(ctx, "starting payment expiry sweeper",
+		slog.Duration("ttl", s.ttl),
+		slog.Duration("poll_interval", s.pollInterval))
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			s.logger.InfoContext(ctx, "payment expiry sweeper stopped")
+			return
+		case <-ticker.C:
+			if err := s.Sweep(ctx); err != nil {
+				s.logger.ErrorContext(ctx, "failed to expire payments", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Sweep expires up to a batch of payments pending for longer than the TTL.
+// Each payment is locked and expired in its own transaction, so replicas
+// sweeping at the same time never claim the same payment. One that fails is
+// logged and postponed by a TTL, so it cannot hold back the rest of the
+// pending payments.
+func (s *Sweeper) Sweep(ctx context.Context) error {
+	pendingBefore := time.Now().Add(-s.ttl)
+
+	expired := 0
+	for range s.batchSize {
+		var payment *domain.Payment
+
+		err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+			var err error
+			payment, err = s.paymentRepo.FindExpired(ctx, *tx, pendingBefore)
+			if err != nil {
+				return err
+			}
+
+			return s.expire(ctx, *tx, payment)
+		})
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			break
+		}
+		if err != nil && payment == nil {
+			return err
+		}
+		if err != nil {
+			s.logger.WarnContext(correlation.WithPaymentID(ctx, payment.ID), "failed to expire payment, postponing",
+				slog.Any("error", err))
+			s.postpone(ctx, payment.ID)
+			continue
+		}
+
+		expired++
+		s.metrics.PaymentStatusChanged(*payment)
+	}
+
+	if expired > 0 {
+		s.logger.InfoContext(ctx, "Payments expired", slog.Int("count", expired))
+	}
+
+	return nil
+}
+
+// postpone restarts the TTL of a payment that failed to expire, which also
+// keeps it out of the rest of this sweep.
+func (s *Sweeper) postpone(ctx context.Context, paymentID string) {
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		return s.paymentRepo.PostponeExpiry(ctx, *tx, paymentID, time.Now())
+	})
+	if err != nil {
+		s.logger.ErrorContext(correlation.WithPaymentID(ctx, paymentID), "failed to postpone payment expiry",
+			slog.Any("error", err))
+	}
+}
+
+func (s *Sweeper) expire(ctx context.Context, tx pgx.Tx, payment *domain.Payment) error {
+	ctx = correlation.WithPaymentID(ctx, payment.ID)
+
+	from := payment.Status
+	if err := payment.TransitionTo(domain.StatusExpired); err != nil {
+		return err
+	}
+
+	if err := s.paymentRepo.Update(ctx, tx, *payment, from); err != nil {
+		if errors.Is(err, domain.ErrPaymentConflict) {
+			return domain.ErrPaymentConflict
+		}
+
+		s.logger.ErrorContext(ctx, "failed to expire payment",
+			slog.Any("error", err))
+
+		return domain.ErrUpdatePayment
+	}
+
+	err := s.balanceService.Update(ctx, tx, payment.UserID, payment.ID, payment.Funding(), false)
+	if err != nil {
+		return err
+	}
+
+	paymentExpiredEvent := &domain.PaymentExpiredEvent{
+		UserID:        payment.UserID,
+		TransactionID: payment.ID,
+	}
+
+	message, err := domain.NewOutboxMessage(uidgen.NewUUID(),
+		domain.EventTypePaymentExpired, payment.ID, paymentExpiredEvent)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to build outbox message",
+			slog.Any("error", err))
+
+		return domain.ErrCreateOutboxMessage
+	}
+
+	if err = s.outboxRepo.Create(ctx, tx, message); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create outbox message",
+			slog.Any("error", err))
+
+		return domain.ErrCreateOutboxMessage
+	}
+
+	return nil
+}
+
+func (s *Sweeper) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+
+	return nil
+}
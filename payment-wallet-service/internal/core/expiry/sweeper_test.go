@@ -0,0 +1,285 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewSweeper(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+	logger := slog.Default()
+
+	sweeper := NewSweeper(SweeperConfig{
+		Logger:            logger,
+		DB:                mockDB,
+		PaymentRepository: mockPaymentRepo,
+		BalanceService:    mockBalanceService,
+		OutboxRepository:  mockOutboxRepo,
+		Metrics:           mockMetrics,
+	})
+
+	assert.NotNil(t, sweeper)
+	assert.Equal(t, logger, sweeper.logger)
+	assert.Equal(t, mockDB, sweeper.db)
+	assert.Equal(t, mockPaymentRepo, sweeper.paymentRepo)
+	assert.Equal(t, mockBalanceService, sweeper.balanceService)
+	assert.Equal(t, mockOutboxRepo, sweeper.outboxRepo)
+	assert.Equal(t, mockMetrics, sweeper.metrics)
+	assert.Equal(t, _defaultTTL, sweeper.ttl)
+	assert.Equal(t, _defaultPollInterval, sweeper.pollInterval)
+	assert.Equal(t, _defaultBatchSize, sweeper.batchSize)
+}
+
+func TestSweeper_Sweep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+
+	sweeper := NewSweeper(SweeperConfig{
+		Logger:            slog.Default(),
+		DB:                mockDB,
+		PaymentRepository: mockPaymentRepo,
+		BalanceService:    mockBalanceService,
+		OutboxRepository:  mockOutboxRepo,
+		Metrics:           mockMetrics,
+		TTL:               10 * time.Minute,
+		BatchSize:         10,
+	})
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-1")
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	stalePayment := func() *domain.Payment {
+		return &domain.Payment{
+			ID:              "payment-1",
+			UserID:          "user-1",
+			Amount:          5000,
+			Currency:        domain.CurrencyARS,
+			FundingAmount:   5000,
+			FundingCurrency: domain.CurrencyARS,
+			Status:          domain.StatusPending,
+		}
+	}
+
+	t.Run("stale payments are expired and funds released", func(t *testing.T) {
+		before := time.Now()
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(2)
+		gomock.InOrder(
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, tx pgx.Tx, pendingBefore time.Time) (*domain.Payment, error) {
+					assert.True(t, !pendingBefore.Before(before.Add(-10*time.Minute)))
+					assert.True(t, pendingBefore.Before(time.Now().Add(-9*time.Minute)))
+					return stalePayment(), nil
+				}),
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).
+				Return(nil, domain.ErrPaymentNotFound),
+		)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusExpired, payment.Status)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-1", "payment-1", domain.NewMoney(5000, domain.CurrencyARS), false).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Equal(t, domain.EventTypePaymentExpired, message.EventType)
+				assert.Equal(t, "payment-1", message.AggregateID)
+				return nil
+			}).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusExpired, payment.Status)
+			}).Times(1)
+
+		err := sweeper.Sweep(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("nothing to expire", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).
+			Return(nil, domain.ErrPaymentNotFound).Times(1)
+		mockPaymentRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := sweeper.Sweep(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error finding expired payments", func(t *testing.T) {
+		expectedError := errors.New("database error")
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).
+			Return(nil, expectedError).Times(1)
+
+		err := sweeper.Sweep(ctx)
+		assert.Equal(t, expectedError, err)
+	})
+
+	t.Run("stops after a batch", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(10)
+		mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, pendingBefore time.Time) (*domain.Payment, error) {
+				return stalePayment(), nil
+			}).Times(10)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(10)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-1", "payment-1", domain.NewMoney(5000, domain.CurrencyARS), false).
+			Return(nil).Times(10)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).Return(nil).Times(10)
+		mockMetrics.EXPECT().PaymentStatusChanged(gomock.Any()).Times(10)
+
+		err := sweeper.Sweep(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error updating payment postpones it", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(3)
+		gomock.InOrder(
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(stalePayment(), nil),
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(nil, domain.ErrPaymentNotFound),
+		)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			Return(errors.New("database error")).Times(1)
+		mockPaymentRepo.EXPECT().PostponeExpiry(ctx, gomock.Any(), "payment-1", gomock.Any()).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMetrics.EXPECT().PaymentStatusChanged(gomock.Any()).Times(0)
+
+		err := sweeper.Sweep(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error releasing funds postpones the payment", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(3)
+		gomock.InOrder(
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(stalePayment(), nil),
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(nil, domain.ErrPaymentNotFound),
+		)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-1", "payment-1", domain.NewMoney(5000, domain.CurrencyARS), false).
+			Return(domain.ErrUpdateBalance).Times(1)
+		mockPaymentRepo.EXPECT().PostponeExpiry(ctx, gomock.Any(), "payment-1", gomock.Any()).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMetrics.EXPECT().PaymentStatusChanged(gomock.Any()).Times(0)
+
+		err := sweeper.Sweep(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error creating outbox message postpones the payment", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(3)
+		gomock.InOrder(
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(stalePayment(), nil),
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(nil, domain.ErrPaymentNotFound),
+		)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-1", "payment-1", domain.NewMoney(5000, domain.CurrencyARS), false).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			Return(errors.New("outbox error")).Times(1)
+		mockPaymentRepo.EXPECT().PostponeExpiry(ctx, gomock.Any(), "payment-1", gomock.Any()).Return(nil).Times(1)
+		mockMetrics.EXPECT().PaymentStatusChanged(gomock.Any()).Times(0)
+
+		err := sweeper.Sweep(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failing payment does not block the rest of the batch", func(t *testing.T) {
+		secondCtx := correlation.WithPaymentID(ctx, "payment-2")
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(4)
+		gomock.InOrder(
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(stalePayment(), nil),
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(&domain.Payment{
+				ID:              "payment-2",
+				UserID:          "user-2",
+				Amount:          3000,
+				Currency:        domain.CurrencyARS,
+				FundingAmount:   3000,
+				FundingCurrency: domain.CurrencyARS,
+				Status:          domain.StatusPending,
+			}, nil),
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(nil, domain.ErrPaymentNotFound),
+		)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-1", "payment-1", domain.NewMoney(5000, domain.CurrencyARS), false).
+			Return(domain.ErrReservationNotFound).Times(1)
+		mockPaymentRepo.EXPECT().PostponeExpiry(ctx, gomock.Any(), "payment-1", gomock.Any()).Return(nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(secondCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(secondCtx, gomock.Any(), "user-2", "payment-2", domain.NewMoney(3000, domain.CurrencyARS), false).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(secondCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Equal(t, "payment-2", message.AggregateID)
+				return nil
+			}).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, "payment-2", payment.ID)
+			}).Times(1)
+
+		err := sweeper.Sweep(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error postponing a payment is logged", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(3)
+		gomock.InOrder(
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(stalePayment(), nil),
+			mockPaymentRepo.EXPECT().FindExpired(ctx, gomock.Any(), gomock.Any()).Return(nil, domain.ErrPaymentNotFound),
+		)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			Return(errors.New("database error")).Times(1)
+		mockPaymentRepo.EXPECT().PostponeExpiry(ctx, gomock.Any(), "payment-1", gomock.Any()).
+			Return(errors.New("database error")).Times(1)
+		mockMetrics.EXPECT().PaymentStatusChanged(gomock.Any()).Times(0)
+
+		err := sweeper.Sweep(ctx)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSweeper_Close(t *testing.T) {
+	sweeper := NewSweeper(SweeperConfig{Logger: slog.Default(), PollInterval: time.Hour})
+
+	stopped := make(chan struct{})
+	go func() {
+		sweeper.Start(context.Background())
+		close(stopped)
+	}()
+
+	assert.NoError(t, sweeper.Close())
+	assert.NoError(t, sweeper.Close())
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("sweeper did not stop after Close")
+	}
+}
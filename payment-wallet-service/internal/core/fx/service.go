@@ -0,0 +1,68 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
+)
+
+const _basisPoints = 10000
+
+type ServiceConfig struct {
+	Logger       *slog.Logger
+	RateProvider ports.FXRateProvider
+	// SpreadBps is added on top of the provider rate, in basis points.
+	SpreadBps int64
+	Rounding  domain.RoundingMode
+}
+
+type Service struct {
+	logger       *slog.Logger
+	rateProvider ports.FXRateProvider
+	spread       *big.Rat
+	rounding     domain.RoundingMode
+}
+
+func NewFXService(config ServiceConfig) *Service {
+	return &Service{
+		logger:       config.Logger,
+		rateProvider: config.RateProvider,
+		spread:       big.NewRat(_basisPoints+config.SpreadBps, _basisPoints),
+		rounding:     config.Rounding.OrDefault(),
+	}
+}
+
+// Quote locks the rate to convert amount into the currency to. The provider
+// rate is marked up by the spread and rounded to domain.RateDecimals before
+// converting, so the quoted rate alone reproduces the converted amount.
+func (s *Service) Quote(ctx context.Context, amount domain.Money, to domain.Currency) (*domain.FXQuote, error) {
+	rate, err := s.rateProvider.Rate(ctx, amount.Currency, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrFXRateUnavailable) {
+			return nil, domain.ErrFXRateUnavailable
+		}
+
+		s.logger.ErrorContext(ctx, "failed to get exchange rate",
+			slog.Any("error", err),
+			slog.String("from", string(amount.Currency)),
+			slog.String("to", string(to)))
+
+		return nil, domain.ErrGetFXRate
+	}
+
+	locked := domain.LockRate(new(big.Rat).Mul(rate, s.spread))
+
+	return &domain.FXQuote{
+		ID:        uidgen.NewUUID(),
+		Source:    amount,
+		Target:    domain.Convert(amount, to, locked, s.rounding),
+		Rate:      locked.FloatString(domain.RateDecimals),
+		CreatedAt: time.Now(),
+	}, nil
+}
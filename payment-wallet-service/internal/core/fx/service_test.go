@@ -0,0 +1,156 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewFXService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRateProvider := mocks.NewMockFXRateProvider(ctrl)
+	logger := slog.Default()
+
+	service := NewFXService(ServiceConfig{
+		Logger:       logger,
+		RateProvider: mockRateProvider,
+		SpreadBps:    150,
+	})
+
+	assert.NotNil(t, service)
+	assert.Equal(t, logger, service.logger)
+	assert.Equal(t, mockRateProvider, service.rateProvider)
+	assert.Equal(t, big.NewRat(10150, 10000), service.spread)
+	assert.Equal(t, domain.RoundUp, service.rounding)
+}
+
+func TestService_Quote(t *testing.T) {
+	ctx := context.Background()
+	rate := func(value string) *big.Rat {
+		r, _ := new(big.Rat).SetString(value)
+		return r
+	}
+
+	tests := []struct {
+		name         string
+		amount       domain.Money
+		to           domain.Currency
+		rate         *big.Rat
+		providerErr  error
+		spreadBps    int64
+		rounding     domain.RoundingMode
+		expected     domain.Money
+		expectedRate string
+		expectedErr  error
+	}{
+		{
+			name:         "spread is added to the provider rate",
+			amount:       domain.NewMoney(10000, domain.CurrencyUSD),
+			to:           domain.CurrencyARS,
+			rate:         rate("1250"),
+			spreadBps:    150,
+			expected:     domain.NewMoney(12687500, domain.CurrencyARS),
+			expectedRate: "1268.7500000000",
+		},
+		{
+			name:         "rounds up by default",
+			amount:       domain.NewMoney(1, domain.CurrencyUSD),
+			to:           domain.CurrencyCLP,
+			rate:         rate("940"),
+			expected:     domain.NewMoney(10, domain.CurrencyCLP),
+			expectedRate: "940.0000000000",
+		},
+		{
+			name:         "rounds down",
+			amount:       domain.NewMoney(1, domain.CurrencyUSD),
+			to:           domain.CurrencyCLP,
+			rate:         rate("950.5"),
+			rounding:     domain.RoundDown,
+			expected:     domain.NewMoney(9, domain.CurrencyCLP),
+			expectedRate: "950.5000000000",
+		},
+		{
+			name:         "rounds half up below the half",
+			amount:       domain.NewMoney(1, domain.CurrencyUSD),
+			to:           domain.CurrencyCLP,
+			rate:         rate("940"),
+			rounding:     domain.RoundHalfUp,
+			expected:     domain.NewMoney(9, domain.CurrencyCLP),
+			expectedRate: "940.0000000000",
+		},
+		{
+			name:         "rounds half up on the half",
+			amount:       domain.NewMoney(1, domain.CurrencyUSD),
+			to:           domain.CurrencyCLP,
+			rate:         rate("950"),
+			rounding:     domain.RoundHalfUp,
+			expected:     domain.NewMoney(10, domain.CurrencyCLP),
+			expectedRate: "950.0000000000",
+		},
+		{
+			name:         "currency without decimals into one with cents",
+			amount:       domain.NewMoney(1000, domain.CurrencyCLP),
+			to:           domain.CurrencyUSD,
+			rate:         rate("0.00105"),
+			expected:     domain.NewMoney(105, domain.CurrencyUSD),
+			expectedRate: "0.0010500000",
+		},
+		{
+			name:         "rate is locked at its stored precision",
+			amount:       domain.NewMoney(300, domain.CurrencyARS),
+			to:           domain.CurrencyUSD,
+			rate:         big.NewRat(1, 1200),
+			expected:     domain.NewMoney(1, domain.CurrencyUSD),
+			expectedRate: "0.0008333333",
+		},
+		{
+			name:        "unknown currency pair",
+			amount:      domain.NewMoney(10000, domain.CurrencyUSD),
+			to:          domain.CurrencyUYU,
+			providerErr: domain.ErrFXRateUnavailable,
+			expectedErr: domain.ErrFXRateUnavailable,
+		},
+		{
+			name:        "provider failure",
+			amount:      domain.NewMoney(10000, domain.CurrencyUSD),
+			to:          domain.CurrencyARS,
+			providerErr: errors.New("connection refused"),
+			expectedErr: domain.ErrGetFXRate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockRateProvider := mocks.NewMockFXRateProvider(ctrl)
+			mockRateProvider.EXPECT().Rate(ctx, tt.amount.Currency, tt.to).Return(tt.rate, tt.providerErr).Times(1)
+
+			service := NewFXService(ServiceConfig{
+				Logger:       slog.Default(),
+				RateProvider: mockRateProvider,
+				SpreadBps:    tt.spreadBps,
+				Rounding:     tt.rounding,
+			})
+
+			quote, err := service.Quote(ctx, tt.amount, tt.to)
+			if tt.expectedErr != nil {
+				assert.Nil(t, quote)
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, quote.ID)
+			assert.Equal(t, tt.amount, quote.Source)
+			assert.Equal(t, tt.expected, quote.Target)
+			assert.Equal(t, tt.expectedRate, quote.Rate)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package limits
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/jackc/pgx/v5"
+)
+
+type ServiceConfig struct {
+	Logger          *slog.Logger
+	LimitRepository ports.LimitRepository
+	// Tiers holds the limits of each tier by wallet currency. Currencies a
+	// tier does not list are not limited.
+	Tiers       map[string]map[domain.Currency]domain.Limits
+	DefaultTier string
+}
+
+type Service struct {
+	logger      *slog.Logger
+	limitRepo   ports.LimitRepository
+	tiers       map[string]map[domain.Currency]domain.Limits
+	defaultTier string
+	now         func() time.Time
+}
+
+func NewLimitService(config ServiceConfig) *Service {
+	defaultTier := config.DefaultTier
+	if defaultTier == "" {
+		defaultTier = domain.DefaultLimitTier
+	}
+
+	return &Service{
+		logger:      config.Logger,
+		limitRepo:   config.LimitRepository,
+		tiers:       config.Tiers,
+		defaultTier: defaultTier,
+		now:         time.Now,
+	}
+}
+
+// Check returns a *domain.LimitExceededError when a payment of amount, funded
+// from the wallet of the user in that currency, exceeds any of its limits.
+// It must run in the transaction that reserves the funds and after the
+// reservation, which locks the wallet, so concurrent payments of the same
+// wallet see each other in the usage.
+func (s *Service) Check(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error {
+	override, err := s.limitRepo.FindOverride(ctx, tx, userID, amount.Currency)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get limit override",
+			slog.Any("error", err),
+			slog.String("currency", string(amount.Currency)))
+
+		return domain.ErrGetLimits
+	}
+
+	limits := s.limits(userID, amount.Currency, override)
+	if limits == (domain.Limits{}) {
+		return nil
+	}
+
+	usage, err := s.limitRepo.Usage(ctx, tx, userID, amount.Currency, domain.NewLimitWindows(s.now()))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get limit usage",
+			slog.Any("error", err),
+			slog.String("currency", string(amount.Currency)))
+
+		return domain.ErrGetLimits
+	}
+
+	if errCheck := limits.Check(amount, *usage); errCheck != nil {
+		s.logger.WarnContext(ctx, "payment exceeds spending limit",
+			slog.Any("error", errCheck))
+
+		return errCheck
+	}
+
+	return nil
+}
+
+// limits resolves the limits of the wallet: the ones of its tier, or of the
+// default tier, with the wallet overrides applied.
+func (s *Service) limits(userID string, currency domain.Currency, override *domain.LimitOverride) domain.Limits {
+	if override == nil {
+		return s.tiers[s.defaultTier][currency]
+	}
+
+	tier := s.defaultTier
+	if override.Tier != "" {
+		if _, ok := s.tiers[override.Tier]; ok {
+			tier = override.Tier
+		} else {
+			s.logger.Warn("unknown limit tier, using the default one",
+				slog.String("user_id", userID),
+				slog.String("tier", override.Tier))
+		}
+	}
+
+	return s.tiers[tier][currency].Apply(*override)
+}
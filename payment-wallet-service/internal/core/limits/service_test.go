@@ -0,0 +1,187 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewLimitService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockLimitRepo := mocks.NewMockLimitRepository(ctrl)
+	logger := slog.Default()
+	tiers := map[string]map[domain.Currency]domain.Limits{
+		domain.DefaultLimitTier: {domain.CurrencyARS: {DailyAmount: 100000}},
+	}
+
+	service := NewLimitService(ServiceConfig{
+		Logger:          logger,
+		LimitRepository: mockLimitRepo,
+		Tiers:           tiers,
+	})
+
+	assert.NotNil(t, service)
+	assert.Equal(t, logger, service.logger)
+	assert.Equal(t, mockLimitRepo, service.limitRepo)
+	assert.Equal(t, tiers, service.tiers)
+	assert.Equal(t, domain.DefaultLimitTier, service.defaultTier)
+	assert.NotNil(t, service.now)
+}
+
+func TestService_Check(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	windows := domain.LimitWindows{
+		Day:   time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+		Month: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Hour:  time.Date(2026, 3, 15, 9, 30, 0, 0, time.UTC),
+	}
+	tiers := map[string]map[domain.Currency]domain.Limits{
+		"STANDARD": {
+			domain.CurrencyARS: {PerTransaction: 50000, DailyAmount: 100000, MonthlyAmount: 500000, HourlyCount: 5},
+		},
+		"PREMIUM": {
+			domain.CurrencyARS: {PerTransaction: 200000, DailyAmount: 400000, MonthlyAmount: 2000000, HourlyCount: 20},
+		},
+	}
+	limit := func(value int64) *int64 {
+		return &value
+	}
+
+	tests := []struct {
+		name        string
+		amount      domain.Money
+		override    *domain.LimitOverride
+		overrideErr error
+		usage       *domain.LimitUsage
+		usageErr    error
+		usageTimes  int
+		expectedErr error
+	}{
+		{
+			name:       "within every limit",
+			amount:     domain.NewMoney(30000, domain.CurrencyARS),
+			usage:      &domain.LimitUsage{DailyAmount: 70000, MonthlyAmount: 470000, HourlyCount: 4},
+			usageTimes: 1,
+		},
+		{
+			name:       "per transaction maximum",
+			amount:     domain.NewMoney(50001, domain.CurrencyARS),
+			usage:      &domain.LimitUsage{},
+			usageTimes: 1,
+			expectedErr: &domain.LimitExceededError{
+				Limit: domain.LimitPerTransaction, Max: 50000, Requested: 50001, Currency: domain.CurrencyARS,
+			},
+		},
+		{
+			name:       "daily amount",
+			amount:     domain.NewMoney(30000, domain.CurrencyARS),
+			usage:      &domain.LimitUsage{DailyAmount: 70001, MonthlyAmount: 70001},
+			usageTimes: 1,
+			expectedErr: &domain.LimitExceededError{
+				Limit: domain.LimitDailyAmount, Max: 100000, Used: 70001, Requested: 30000, Currency: domain.CurrencyARS,
+			},
+		},
+		{
+			name:       "monthly amount",
+			amount:     domain.NewMoney(30000, domain.CurrencyARS),
+			usage:      &domain.LimitUsage{MonthlyAmount: 480000},
+			usageTimes: 1,
+			expectedErr: &domain.LimitExceededError{
+				Limit: domain.LimitMonthlyAmount, Max: 500000, Used: 480000, Requested: 30000, Currency: domain.CurrencyARS,
+			},
+		},
+		{
+			name:       "payments per hour",
+			amount:     domain.NewMoney(100, domain.CurrencyARS),
+			usage:      &domain.LimitUsage{DailyAmount: 500, MonthlyAmount: 500, HourlyCount: 5},
+			usageTimes: 1,
+			expectedErr: &domain.LimitExceededError{
+				Limit: domain.LimitHourlyCount, Max: 5, Used: 5, Requested: 1, Currency: domain.CurrencyARS,
+			},
+		},
+		{
+			name:       "user override replaces the tier limit",
+			amount:     domain.NewMoney(30000, domain.CurrencyARS),
+			override:   &domain.LimitOverride{DailyAmount: limit(150000)},
+			usage:      &domain.LimitUsage{DailyAmount: 110000, MonthlyAmount: 110000},
+			usageTimes: 1,
+		},
+		{
+			name:       "user override of zero removes the limit",
+			amount:     domain.NewMoney(30000, domain.CurrencyARS),
+			override:   &domain.LimitOverride{HourlyCount: limit(0)},
+			usage:      &domain.LimitUsage{HourlyCount: 50},
+			usageTimes: 1,
+		},
+		{
+			name:       "user assigned to another tier",
+			amount:     domain.NewMoney(150000, domain.CurrencyARS),
+			override:   &domain.LimitOverride{Tier: "PREMIUM"},
+			usage:      &domain.LimitUsage{DailyAmount: 200000, MonthlyAmount: 200000},
+			usageTimes: 1,
+		},
+		{
+			name:       "unknown tier falls back to the default one",
+			amount:     domain.NewMoney(150000, domain.CurrencyARS),
+			override:   &domain.LimitOverride{Tier: "GOLD"},
+			usage:      &domain.LimitUsage{},
+			usageTimes: 1,
+			expectedErr: &domain.LimitExceededError{
+				Limit: domain.LimitPerTransaction, Max: 50000, Requested: 150000, Currency: domain.CurrencyARS,
+			},
+		},
+		{
+			name:       "currency without limits",
+			amount:     domain.NewMoney(1000000, domain.CurrencyUSD),
+			usageTimes: 0,
+		},
+		{
+			name:        "error getting the override",
+			amount:      domain.NewMoney(30000, domain.CurrencyARS),
+			overrideErr: errors.New("database error"),
+			usageTimes:  0,
+			expectedErr: domain.ErrGetLimits,
+		},
+		{
+			name:        "error getting the usage",
+			amount:      domain.NewMoney(30000, domain.CurrencyARS),
+			usageErr:    errors.New("database error"),
+			usageTimes:  1,
+			expectedErr: domain.ErrGetLimits,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockLimitRepo := mocks.NewMockLimitRepository(ctrl)
+			mockLimitRepo.EXPECT().FindOverride(ctx, gomock.Any(), "user-1", tt.amount.Currency).
+				Return(tt.override, tt.overrideErr).Times(1)
+			mockLimitRepo.EXPECT().Usage(ctx, gomock.Any(), "user-1", tt.amount.Currency, windows).
+				Return(tt.usage, tt.usageErr).Times(tt.usageTimes)
+
+			service := NewLimitService(ServiceConfig{
+				Logger:          slog.Default(),
+				LimitRepository: mockLimitRepo,
+				Tiers:           tiers,
+			})
+			service.now = func() time.Time { return now }
+
+			err := service.Check(ctx, nil, "user-1", tt.amount)
+			assert.Equal(t, tt.expectedErr, err)
+
+			var limitErr *domain.LimitExceededError
+			if errors.As(tt.expectedErr, &limitErr) {
+				assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	_defaultPollInterval = time.Second
+	_defaultBatchSize    = 100
+	_defaultBaseBackoff  = time.Second
+	_defaultMaxBackoff   = 5 * time.Minute
+	_defaultClaimLease   = 10 * time.Minute
+)
+
+type RelayConfig struct {
+	Logger           *slog.Logger
+	DB               ports.Database
+	OutboxRepository ports.OutboxRepository
+	Publisher        ports.Publisher
+	PollInterval     time.Duration
+	BatchSize        int
+	BaseBackoff      time.Duration
+	MaxBackoff       time.Duration
+	// ClaimLease is how long a claimed message is kept from other relays. It
+	// must outlast publishing a whole batch, or a slow batch is published
+	// twice.
+	ClaimLease time.Duration
+}
+
+// Relay publishes the messages written to the outbox. A message is marked as
+// sent only after the broker accepted it, so delivery is at-least-once and
+// consumers must deduplicate by message ID. No transaction is held while
+// publishing, so a slow broker does not keep rows locked or a connection busy.
+type Relay struct {
+	logger       *slog.Logger
+	db           ports.Database
+	outboxRepo   ports.OutboxRepository
+	publisher    ports.Publisher
+	pollInterval time.Duration
+	batchSize    int
+	baseBackoff  time.Duration
+	maxBackoff   time.Duration
+	claimLease   time.Duration
+	done         chan struct{}
+	closeOnce    sync.Once
+	started      atomic.Bool
+	stopped      chan struct{}
+}
+
+func NewRelay(config RelayConfig) *Relay {
+	relay := &Relay{
+		logger:       config.Logger,
+		db:           config.DB,
+		outboxRepo:   config.OutboxRepository,
+		publisher:    config.Publisher,
+		pollInterval: config.PollInterval,
+		batchSize:    config.BatchSize,
+		baseBackoff:  config.BaseBackoff,
+		maxBackoff:   config.MaxBackoff,
+		claimLease:   config.ClaimLease,
+		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+
+	if relay.pollInterval <= 0 {
+		relay.pollInterval = _defaultPollInterval
+	}
+	if relay.batchSize <= 0 {
+		relay.batchSize = _defaultBatchSize
+	}
+	if relay.baseBackoff <= 0 {
+		relay.baseBackoff = _defaultBaseBackoff
+	}
+	if relay.maxBackoff <= 0 {
+		relay.maxBackoff = _defaultMaxBackoff
+	}
+	if relay.claimLease <= 0 {
+		relay.claimLease = _defaultClaimLease
+	}
+
+	return relay
+}
+
+// Start polls the outbox until the context is cancelled or the relay is closed.
+func (r *Relay) Start(ctx context.Context) {
+	r.started.Store(true)
+	defer close(r.stopped)
+
+	r.logger.InfoContext(ctx, "starting outbox relay", slog.Duration("poll_interval", r.pollInterval))
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.done:
+			r.logger.InfoContext(ctx, "outbox relay stopped")
+			return
+		case <-ticker.C:
+			if err := r.Relay(ctx); err != nil {
+				r.logger.ErrorContext(ctx, "failed to relay outbox messages", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// Relay publishes one batch of pending messages. The batch is claimed in a
+// transaction of its own, and each message is marked in another one after
+// publishing it. Messages the broker rejects are rescheduled with an
+// exponential backoff instead of failing the batch. When the relay is closed
+// the messages not published yet are released for the next relay.
+func (r *Relay) Relay(ctx context.Context) error {
+	if r.closing() {
+		return nil
+	}
+
+	var messages []domain.OutboxMessage
+
+	err := r.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		var err error
+		messages, err = r.outboxRepo.ClaimPending(ctx, *tx, r.batchSize, r.claimLease)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, message := range messages {
+		if r.closing() {
+			return r.release(ctx, messages[i:])
+		}
+
+		// publishing is logged under the request that wrote the message
+		messageCtx := ctx
+		if message.RequestID != "" {
+			messageCtx = correlation.WithRequestID(ctx, message.RequestID)
+		}
+
+		errPublish := r.publisher.Publish(messageCtx, message)
+
+		err = r.db.WithTx(ctx, func(tx *pgx.Tx) error {
+			if errPublish == nil {
+				return r.outboxRepo.MarkSent(ctx, *tx, message.ID)
+			}
+
+			nextAttemptAt := time.Now().Add(r.backoff(message.Attempts))
+
+			r.logger.WarnContext(messageCtx, "failed to publish outbox message, rescheduling",
+				slog.Any("error", errPublish),
+				slog.String("message_id", message.ID),
+				slog.Int("attempts", message.Attempts+1),
+				slog.Time("next_attempt_at", nextAttemptAt))
+
+			return r.outboxRepo.MarkFailed(ctx, *tx, message.ID, nextAttemptAt, errPublish.Error())
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Relay) closing() bool {
+	select {
+	case <-r.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (r *Relay) release(ctx context.Context, messages []domain.OutboxMessage) error {
+	messageIDs := make([]string, 0, len(messages))
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.ID)
+	}
+
+	return r.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		return r.outboxRepo.Release(ctx, *tx, messageIDs)
+	})
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.baseBackoff
+	for i := 0; i < attempts && backoff < r.maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, r.maxBackoff)
+}
+
+// Close stops the relay and, once started, waits for the batch in flight, so
+// the publisher is not closed under it.
+func (r *Relay) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+
+	if r.started.Load() {
+		<-r.stopped
+	}
+
+	return nil
+}
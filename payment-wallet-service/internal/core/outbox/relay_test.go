@@ -0,0 +1,273 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewRelay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockPublisher := mocks.NewMockPublisher(ctrl)
+	logger := slog.Default()
+
+	relay := NewRelay(RelayConfig{
+		Logger:           logger,
+		DB:               mockDB,
+		OutboxRepository: mockOutboxRepo,
+		Publisher:        mockPublisher,
+	})
+
+	assert.NotNil(t, relay)
+	assert.Equal(t, logger, relay.logger)
+	assert.Equal(t, mockDB, relay.db)
+	assert.Equal(t, mockOutboxRepo, relay.outboxRepo)
+	assert.Equal(t, mockPublisher, relay.publisher)
+	assert.Equal(t, _defaultPollInterval, relay.pollInterval)
+	assert.Equal(t, _defaultBatchSize, relay.batchSize)
+	assert.Equal(t, _defaultBaseBackoff, relay.baseBackoff)
+	assert.Equal(t, _defaultMaxBackoff, relay.maxBackoff)
+	assert.Equal(t, _defaultClaimLease, relay.claimLease)
+}
+
+func TestRelay_Relay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockPublisher := mocks.NewMockPublisher(ctrl)
+
+	relay := NewRelay(RelayConfig{
+		Logger:           slog.Default(),
+		DB:               mockDB,
+		OutboxRepository: mockOutboxRepo,
+		Publisher:        mockPublisher,
+		BatchSize:        10,
+		BaseBackoff:      time.Second,
+		MaxBackoff:       time.Minute,
+		ClaimLease:       time.Minute,
+	})
+
+	ctx := context.Background()
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	message := domain.OutboxMessage{
+		ID:          "message-1",
+		AggregateID: "payment-1",
+		EventType:   domain.EventTypePaymentInitiated,
+		Payload:     []byte(`{"transaction_id":"payment-1"}`),
+		Attempts:    2,
+	}
+
+	t.Run("published messages are marked as sent", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(2)
+		mockOutboxRepo.EXPECT().ClaimPending(ctx, gomock.Any(), 10, time.Minute).
+			Return([]domain.OutboxMessage{message}, nil).Times(1)
+		mockPublisher.EXPECT().Publish(ctx, message).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().MarkSent(ctx, gomock.Any(), message.ID).Return(nil).Times(1)
+
+		err := relay.Relay(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed messages are rescheduled with backoff", func(t *testing.T) {
+		before := time.Now()
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(2)
+		mockOutboxRepo.EXPECT().ClaimPending(ctx, gomock.Any(), 10, time.Minute).
+			Return([]domain.OutboxMessage{message}, nil).Times(1)
+		mockPublisher.EXPECT().Publish(ctx, message).Return(errors.New("broker down")).Times(1)
+		mockOutboxRepo.EXPECT().MarkSent(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockOutboxRepo.EXPECT().MarkFailed(ctx, gomock.Any(), message.ID, gomock.Any(), "broker down").
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, messageID string, nextAttemptAt time.Time, reason string) error {
+				assert.True(t, !nextAttemptAt.Before(before.Add(4*time.Second)))
+				return nil
+			}).Times(1)
+
+		err := relay.Relay(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("messages are claimed before publishing", func(t *testing.T) {
+		claimed := false
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(2)
+		mockOutboxRepo.EXPECT().ClaimPending(ctx, gomock.Any(), 10, time.Minute).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+				claimed = true
+				return []domain.OutboxMessage{message}, nil
+			}).Times(1)
+		mockPublisher.EXPECT().Publish(ctx, message).
+			DoAndReturn(func(ctx context.Context, message domain.OutboxMessage) error {
+				assert.True(t, claimed)
+				return nil
+			}).Times(1)
+		mockOutboxRepo.EXPECT().MarkSent(ctx, gomock.Any(), message.ID).Return(nil).Times(1)
+
+		err := relay.Relay(ctx)
+		assert.NoError(t, err)
+	})
+
+	t.Run("error fetching pending messages", func(t *testing.T) {
+		expectedError := errors.New("database error")
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockOutboxRepo.EXPECT().ClaimPending(ctx, gomock.Any(), 10, time.Minute).
+			Return(nil, expectedError).Times(1)
+		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+		err := relay.Relay(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, expectedError, err)
+	})
+
+	t.Run("error marking message as sent", func(t *testing.T) {
+		expectedError := errors.New("database error")
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(2)
+		mockOutboxRepo.EXPECT().ClaimPending(ctx, gomock.Any(), 10, time.Minute).
+			Return([]domain.OutboxMessage{message}, nil).Times(1)
+		mockPublisher.EXPECT().Publish(ctx, message).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().MarkSent(ctx, gomock.Any(), message.ID).Return(expectedError).Times(1)
+
+		err := relay.Relay(ctx)
+		assert.Error(t, err)
+		assert.Equal(t, expectedError, err)
+	})
+}
+
+func TestRelay_Relay_closing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockPublisher := mocks.NewMockPublisher(ctrl)
+
+	relay := NewRelay(RelayConfig{
+		Logger:           slog.Default(),
+		DB:               mockDB,
+		OutboxRepository: mockOutboxRepo,
+		Publisher:        mockPublisher,
+		BatchSize:        10,
+		ClaimLease:       time.Minute,
+	})
+
+	ctx := context.Background()
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	messages := []domain.OutboxMessage{
+		{ID: "message-1", EventType: domain.EventTypePaymentInitiated},
+		{ID: "message-2", EventType: domain.EventTypePaymentInitiated},
+		{ID: "message-3", EventType: domain.EventTypePaymentInitiated},
+	}
+
+	mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(3)
+	mockOutboxRepo.EXPECT().ClaimPending(ctx, gomock.Any(), 10, time.Minute).Return(messages, nil).Times(1)
+	mockPublisher.EXPECT().Publish(ctx, messages[0]).
+		DoAndReturn(func(ctx context.Context, message domain.OutboxMessage) error {
+			// the relay is closed while the first message is published
+			close(relay.done)
+			return nil
+		}).Times(1)
+	mockOutboxRepo.EXPECT().MarkSent(ctx, gomock.Any(), "message-1").Return(nil).Times(1)
+	mockOutboxRepo.EXPECT().Release(ctx, gomock.Any(), []string{"message-2", "message-3"}).Return(nil).Times(1)
+
+	err := relay.Relay(ctx)
+	assert.NoError(t, err)
+}
+
+func TestRelay_Close(t *testing.T) {
+	relay := NewRelay(RelayConfig{Logger: slog.Default(), PollInterval: time.Hour})
+
+	stopped := make(chan struct{})
+	go func() {
+		relay.Start(context.Background())
+		close(stopped)
+	}()
+
+	assert.Eventually(t, relay.started.Load, time.Second, time.Millisecond)
+	assert.NoError(t, relay.Close())
+	assert.NoError(t, relay.Close())
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop after Close")
+	}
+}
+
+func TestRelay_Close_waitsForBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockPublisher := mocks.NewMockPublisher(ctrl)
+
+	relay := NewRelay(RelayConfig{
+		Logger:           slog.Default(),
+		DB:               mockDB,
+		OutboxRepository: mockOutboxRepo,
+		Publisher:        mockPublisher,
+		PollInterval:     time.Millisecond,
+	})
+
+	message := domain.OutboxMessage{ID: "message-1", EventType: domain.EventTypePaymentInitiated}
+	publishing := make(chan struct{})
+	published := make(chan struct{})
+
+	mockDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(*pgx.Tx) error) error {
+			return fn(new(pgx.Tx))
+		}).Times(2)
+	mockOutboxRepo.EXPECT().ClaimPending(gomock.Any(), gomock.Any(), _defaultBatchSize, _defaultClaimLease).
+		Return([]domain.OutboxMessage{message}, nil).Times(1)
+	mockPublisher.EXPECT().Publish(gomock.Any(), message).
+		DoAndReturn(func(ctx context.Context, message domain.OutboxMessage) error {
+			close(publishing)
+			time.Sleep(50 * time.Millisecond)
+			close(published)
+			return nil
+		}).Times(1)
+	mockOutboxRepo.EXPECT().MarkSent(gomock.Any(), gomock.Any(), "message-1").Return(nil).Times(1)
+
+	go relay.Start(context.Background())
+
+	<-publishing
+	assert.NoError(t, relay.Close())
+
+	select {
+	case <-published:
+	default:
+		t.Fatal("Close returned before the batch in flight was published")
+	}
+}
+
+func TestRelay_backoff(t *testing.T) {
+	relay := &Relay{
+		baseBackoff: time.Second,
+		maxBackoff:  10 * time.Second,
+	}
+
+	assert.Equal(t, time.Second, relay.backoff(0))
+	assert.Equal(t, 2*time.Second, relay.backoff(1))
+	assert.Equal(t, 8*time.Second, relay.backoff(3))
+	assert.Equal(t, 10*time.Second, relay.backoff(4))
+	assert.Equal(t, 10*time.Second, relay.backoff(50))
+}
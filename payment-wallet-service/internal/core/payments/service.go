@@ -2,107 +2,667 @@ package payments
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
 	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
 	"github.com/jackc/pgx/v5"
 )
 
-const (
-	Pending = "PENDING"
-)
+// _riskHistoryWindow is how far back the payments of the user given to the
+// risk evaluator go.
+const _riskHistoryWindow = 30 * 24 * time.Hour
 
 type ServiceConfig struct {
 	Logger            *slog.Logger
 	DB                ports.Database
 	PaymentRepository ports.PaymentRepository
 	BalanceService    ports.BalanceService
-	PublisherService  ports.Publisher
+	OutboxRepository  ports.OutboxRepository
+	FXService         ports.FXService
+	LimitService      ports.LimitService
+	RiskEvaluator     ports.RiskEvaluator
+	BillerService     ports.BillerService
+	AuditService      ports.AuditService
+	Metrics           ports.PaymentMetrics
 }
 
 type Service struct {
-	logger           *slog.Logger
-	db               ports.Database
-	paymentRepo      ports.PaymentRepository
-	balanceService   ports.BalanceService
-	publisherService ports.Publisher
+	logger         *slog.Logger
+	db             ports.Database
+	paymentRepo    ports.PaymentRepository
+	balanceService ports.BalanceService
+	outboxRepo     ports.OutboxRepository
+	fxService      ports.FXService
+	limitService   ports.LimitService
+	riskEvaluator  ports.RiskEvaluator
+	billerService  ports.BillerService
+	auditService   ports.AuditService
+	metrics        ports.PaymentMetrics
 }
 
 func NewPaymentService(config ServiceConfig) *Service {
 	return &Service{
-		logger:           config.Logger,
-		paymentRepo:      config.PaymentRepository,
-		balanceService:   config.BalanceService,
-		db:               config.DB,
-		publisherService: config.PublisherService,
+		logger:         config.Logger,
+		paymentRepo:    config.PaymentRepository,
+		balanceService: config.BalanceService,
+		db:             config.DB,
+		outboxRepo:     config.OutboxRepository,
+		fxService:      config.FXService,
+		limitService:   config.LimitService,
+		riskEvaluator:  config.RiskEvaluator,
+		billerService:  config.BillerService,
+		auditService:   config.AuditService,
+		metrics:        config.Metrics,
 	}
 }
 
-func (s *Service) Create(ctx context.Context, request domain.CreatePaymentRequest) error {
-	return s.db.WithTx(ctx, func(tx *pgx.Tx) error {
-		exists, err := s.paymentRepo.CheckIdempotency(ctx, *tx, request.IdempotencyKey)
+// Create reserves the funds and registers a new pending payment. When the
+// idempotency key was already used for the same request, the stored payment
+// is returned and replayed is true; a key reused for a different request is
+// rejected. Payments the biller of the service_id does not accept are refused
+// before anything else is done. A payment funded from a wallet in another
+// currency reserves the amount converted at a rate quoted and locked on
+// creation. Payments over any spending limit of the wallet fail with a
+// *domain.LimitExceededError.
+// Payments the risk evaluation denies are refused, and the ones it flags for
+// review are held in PENDING_REVIEW, with their funds reserved, and are not
+// sent to the processor until an admin approves them.
+func (s *Service) Create(ctx context.Context, request domain.CreatePaymentRequest) (*domain.Payment, bool, error) {
+	var (
+		payment  *domain.Payment
+		replayed bool
+	)
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		existing, err := s.paymentRepo.CheckIdempotency(ctx, *tx, request.IdempotencyKey)
 		if err != nil {
-			s.logger.Error("failed to check idempotency",
+			s.logger.ErrorContext(ctx, "failed to check idempotency",
 				slog.Any("error", err),
 				slog.String("idempotency_key", request.IdempotencyKey))
 
 			return domain.ErrCheckIdempotency
 		}
 
-		if exists {
-			//Publish error business metric here
+		if existing != nil {
+			if !existing.Matches(request) {
+				s.metrics.PaymentRefused(domain.RefusalIdempotencyKeyReused)
+				s.logger.WarnContext(ctx, "idempotency key reused with a different request",
+					slog.String("idempotency_key", request.IdempotencyKey),
+					slog.String("transaction_id", existing.ID))
 
+				return domain.ErrIdempotencyKeyReused
+			}
+
+			payment = existing
+			replayed = true
 			return nil
 		}
 
-		err = s.balanceService.ReserveFunds(ctx, *tx, request.UserID, request.Amount)
+		err = s.billerService.Check(ctx, request)
+		if err != nil {
+			if !errors.Is(err, domain.ErrGetBiller) {
+				s.metrics.PaymentRefused(domain.RefusalBiller)
+			}
+
+			return err
+		}
+
+		funding := domain.NewMoney(request.Amount, request.Currency)
+
+		var quote *domain.FXQuote
+		if request.FundingCurrency != request.Currency {
+			quote, err = s.fxService.Quote(ctx, funding, request.FundingCurrency)
+			if err != nil {
+				return err
+			}
+
+			funding = quote.Target
+		}
+
+		assessment, err := s.evaluateRisk(ctx, request, funding)
+		if err != nil {
+			return err
+		}
+
+		status := domain.StatusPending
+		switch assessment.Decision {
+		case domain.RiskDeny:
+			s.metrics.PaymentRefused(domain.RefusalRiskDenied)
+
+			return domain.ErrPaymentDenied
+		case domain.RiskReview:
+			status = domain.StatusPendingReview
+		}
+
+		paymentID := uidgen.NewUUID()
+		txCtx := correlation.WithPaymentID(ctx, paymentID)
+
+		err = s.balanceService.ReserveFunds(txCtx, *tx, request.UserID, paymentID, funding)
+		if err != nil {
+			if errors.Is(err, domain.ErrInsufficientFunds) {
+				s.metrics.PaymentRefused(domain.RefusalInsufficientFunds)
+			}
+
+			return err
+		}
+
+		// the reservation locks the wallet until commit, so checking the limits
+		// after it keeps concurrent payments from both fitting in the same room
+		err = s.limitService.Check(txCtx, *tx, request.UserID, funding)
 		if err != nil {
-			//Publish error business metric here
+			if errors.Is(err, domain.ErrLimitExceeded) {
+				s.metrics.PaymentRefused(domain.RefusalLimitExceeded)
+			}
 
 			return err
 		}
 
-		payment := &domain.Payment{
-			ID:             uidgen.NewUUID(),
-			IdempotencyKey: request.IdempotencyKey,
-			UserID:         request.UserID,
-			Amount:         request.Amount,
-			Status:         Pending,
-			ServiceID:      request.ServiceID,
-			ClientNumber:   request.ClientNumber,
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
+		payment = &domain.Payment{
+			ID:              paymentID,
+			IdempotencyKey:  request.IdempotencyKey,
+			UserID:          request.UserID,
+			Amount:          request.Amount,
+			Currency:        request.Currency,
+			FundingAmount:   funding.Amount,
+			FundingCurrency: funding.Currency,
+			Status:          status,
+			ServiceID:       request.ServiceID,
+			ClientNumber:    request.ClientNumber,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+		}
+		if quote != nil {
+			payment.FXRate = quote.Rate
+			payment.FXQuoteID = quote.ID
 		}
 
-		errCreate := s.paymentRepo.Create(ctx, *tx, *payment)
+		errCreate := s.paymentRepo.Create(txCtx, *tx, *payment)
 		if errCreate != nil {
-			slog.Error("failed to create payment",
-				slog.Any("error", errCreate),
-				slog.String("user_id", request.UserID))
+			if errors.Is(errCreate, domain.ErrIdempotencyKeyConflict) {
+				return domain.ErrIdempotencyKeyConflict
+			}
+
+			s.logger.ErrorContext(txCtx, "failed to create payment",
+				slog.Any("error", errCreate))
 
 			return domain.ErrCreatePayment
 		}
 
-		//Publish success business metric here
+		if payment.Status == domain.StatusPendingReview {
+			s.logger.InfoContext(txCtx, "Payment held for review",
+				slog.Any("reasons", assessment.Reasons))
+			return nil
+		}
+
+		if errPublish := s.publishInitiated(txCtx, *tx, payment); errPublish != nil {
+			return errPublish
+		}
+
+		s.logger.InfoContext(txCtx, "Payment created")
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// counted once committed, a later failure rolls the payment back
+	if !replayed {
+		s.metrics.PaymentCreated(*payment)
+	}
+
+	return payment, replayed, nil
+}
+
+// Update applies the status informed by the processor to a payment. Results
+// the state machine does not allow from the current status, such as one for a
+// payment that already reached a final state, are ignored, so redelivered
+// events are harmless.
+func (s *Service) Update(ctx context.Context, event domain.PaymentResultEvent) error {
+	ctx = correlation.WithPaymentID(ctx, event.TransactionID)
+
+	if err := event.Validate(); err != nil {
+		s.logger.ErrorContext(ctx, "invalid payment result",
+			slog.Any("error", err))
+
+		return domain.ErrInvalidPaymentResult
+	}
+
+	var updated *domain.Payment
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		payment, err := s.paymentRepo.FindByID(ctx, *tx, event.TransactionID)
+		if err != nil {
+			if errors.Is(err, domain.ErrPaymentNotFound) {
+				return domain.ErrPaymentNotFound
+			}
+
+			s.logger.ErrorContext(ctx, "failed to get payment",
+				slog.Any("error", err))
+
+			return domain.ErrGetPayment
+		}
+
+		from := payment.Status
+		if errTransition := payment.TransitionTo(event.Status); errTransition != nil {
+			s.logger.InfoContext(ctx, "payment result not applicable to current status, skipping",
+				slog.String("status", string(from)),
+				slog.String("result", string(event.Status)))
 
-		paymentInitiatedEvent := &domain.PaymentInitiatedEvent{
-			UserID:        payment.UserID,
-			ClientNumber:  payment.ClientNumber,
-			ServiceID:     payment.ServiceID,
-			Amount:        payment.Amount,
-			TransactionID: payment.ID,
+			return nil
+		}
+
+		payment.ProcessorReference = event.ProcessorReference
+		payment.FailureReason = event.FailureReason
+
+		errUpdate := s.paymentRepo.Update(ctx, *tx, *payment, from)
+		if errUpdate != nil {
+			s.logger.ErrorContext(ctx, "failed to update payment",
+				slog.Any("error", errUpdate))
+
+			return domain.ErrUpdatePayment
+		}
+
+		if payment.Status.Final() {
+			err = s.balanceService.Update(ctx, *tx, payment.UserID, payment.ID, payment.Funding(),
+				payment.Status == domain.StatusApproved)
+			if err != nil {
+				return err
+			}
+		}
+
+		s.logger.InfoContext(ctx, "Payment updated",
+			slog.String("status", string(payment.Status)))
+
+		updated = payment
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if updated != nil {
+		s.metrics.PaymentStatusChanged(*updated)
+	}
+
+	return nil
+}
+
+// Cancel moves a pending payment of the user, or one held for review, to
+// CANCELLED and releases its reserved funds. The payment is read without
+// locking and written back only if nobody changed it meanwhile, so a
+// cancellation racing a processor result fails with ErrPaymentConflict instead
+// of overwriting it.
+func (s *Service) Cancel(ctx context.Context, userID, paymentID string) (*domain.Payment, error) {
+	ctx = correlation.WithPaymentID(ctx, paymentID)
+
+	payment, err := s.Get(ctx, userID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := payment.Status
+	if errTransition := payment.TransitionTo(domain.StatusCancelled); errTransition != nil {
+		return nil, domain.ErrPaymentNotCancellable
+	}
+
+	err = s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		errUpdate := s.paymentRepo.Update(ctx, *tx, *payment, from)
+		if errUpdate != nil {
+			if errors.Is(errUpdate, domain.ErrPaymentConflict) {
+				return domain.ErrPaymentConflict
+			}
+
+			s.logger.ErrorContext(ctx, "failed to cancel payment",
+				slog.Any("error", errUpdate))
+
+			return domain.ErrUpdatePayment
+		}
+
+		errBalance := s.balanceService.Update(ctx, *tx, payment.UserID, payment.ID, payment.Funding(), false)
+		if errBalance != nil {
+			return errBalance
 		}
 
-		s.publisherService.Publish(ctx, paymentInitiatedEvent)
+		if from == domain.StatusPendingReview {
+			// the processor never heard of it
+			s.logger.InfoContext(ctx, "Payment cancelled")
+			return nil
+		}
+
+		if errPublish := s.publishCancelled(ctx, *tx, payment); errPublish != nil {
+			return errPublish
+		}
 
-		s.logger.Info("Payment created")
+		s.logger.InfoContext(ctx, "Payment cancelled")
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.metrics.PaymentStatusChanged(*payment)
+
+	payment.Version++
+	return payment, nil
 }
 
-func (s *Service) Update(ctx context.Context, paymentID, status string) error {
+// Approve releases a payment held for review to PENDING and sends it to the
+// processor.
+func (s *Service) Approve(ctx context.Context, operator domain.Operator, paymentID string) (*domain.Payment, error) {
+	return s.review(ctx, operator, paymentID, domain.StatusPending)
+}
+
+// Reject moves a payment held for review to REJECTED and releases its reserved
+// funds. The processor never heard of it, so nothing is published.
+func (s *Service) Reject(ctx context.Context, operator domain.Operator, paymentID string) (*domain.Payment, error) {
+	return s.review(ctx, operator, paymentID, domain.StatusRejected)
+}
+
+// review settles the review of a payment, locking it so the decision cannot
+// race a cancellation by the user, and audits the decision of the operator.
+func (s *Service) review(ctx context.Context, operator domain.Operator, paymentID string, next domain.PaymentStatus) (*domain.Payment, error) {
+	ctx = correlation.WithPaymentID(ctx, paymentID)
+
+	var payment *domain.Payment
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		var err error
+		payment, err = s.paymentRepo.FindByID(ctx, *tx, paymentID)
+		if err != nil {
+			if errors.Is(err, domain.ErrPaymentNotFound) {
+				return domain.ErrPaymentNotFound
+			}
+
+			s.logger.ErrorContext(ctx, "failed to get payment",
+				slog.Any("error", err))
+
+			return domain.ErrGetPayment
+		}
+
+		from := payment.Status
+		if from != domain.StatusPendingReview {
+			return domain.ErrPaymentNotUnderReview
+		}
+
+		if errTransition := payment.TransitionTo(next); errTransition != nil {
+			return errTransition
+		}
+		if next == domain.StatusRejected {
+			payment.FailureReason = "rejected on risk review"
+		}
+
+		errUpdate := s.paymentRepo.Update(ctx, *tx, *payment, from)
+		if errUpdate != nil {
+			if errors.Is(errUpdate, domain.ErrPaymentConflict) {
+				return domain.ErrPaymentConflict
+			}
+
+			s.logger.ErrorContext(ctx, "failed to update reviewed payment",
+				slog.Any("error", errUpdate))
+
+			return domain.ErrUpdatePayment
+		}
+
+		action := domain.AuditApprovePayment
+		if next == domain.StatusRejected {
+			action = domain.AuditRejectPayment
+			err = s.balanceService.Update(ctx, *tx, payment.UserID, payment.ID, payment.Funding(), false)
+		} else {
+			err = s.publishInitiated(ctx, *tx, payment)
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.auditService.Append(ctx, *tx, operator, action, payment.ID, "",
+			map[string]domain.PaymentStatus{"from": from, "to": next})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "Payment reviewed",
+		slog.String("status", string(payment.Status)))
+	s.metrics.PaymentStatusChanged(*payment)
+
+	payment.Version++
+	return payment, nil
+}
+
+// ForceStatus settles by hand a payment the processor never settled, moving it
+// to the requested final status whatever the state machine allows. The
+// reservation is charged for APPROVED and released otherwise. The processor is
+// told to drop a payment it already received and that was not approved. The
+// payment is locked while it changes and the change is audited with its reason.
+func (s *Service) ForceStatus(ctx context.Context, operator domain.Operator, request domain.ForcePaymentStatusRequest) (*domain.Payment, error) {
+	ctx = correlation.WithPaymentID(ctx, request.PaymentID)
+
+	var payment *domain.Payment
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		var err error
+		payment, err = s.paymentRepo.FindByID(ctx, *tx, request.PaymentID)
+		if err != nil {
+			if errors.Is(err, domain.ErrPaymentNotFound) {
+				return domain.ErrPaymentNotFound
+			}
+
+			s.logger.ErrorContext(ctx, "failed to get payment",
+				slog.Any("error", err))
+
+			return domain.ErrGetPayment
+		}
+
+		from := payment.Status
+		if errForce := payment.ForceTo(request.Status); errForce != nil {
+			return errForce
+		}
+
+		approved := payment.Status == domain.StatusApproved
+		if !approved {
+			payment.FailureReason = request.Reason
+		}
+
+		errUpdate := s.paymentRepo.Update(ctx, *tx, *payment, from)
+		if errUpdate != nil {
+			if errors.Is(errUpdate, domain.ErrPaymentConflict) {
+				return domain.ErrPaymentConflict
+			}
+
+			s.logger.ErrorContext(ctx, "failed to force payment status",
+				slog.Any("error", errUpdate))
+
+			return domain.ErrUpdatePayment
+		}
+
+		err = s.balanceService.Update(ctx, *tx, payment.UserID, payment.ID, payment.Funding(), approved)
+		if err != nil {
+			return err
+		}
+
+		if !approved && from != domain.StatusPendingReview {
+			if errPublish := s.publishCancelled(ctx, *tx, payment); errPublish != nil {
+				return errPublish
+			}
+		}
+
+		return s.auditService.Append(ctx, *tx, operator, domain.AuditForcePaymentStatus, payment.ID, request.Reason,
+			map[string]domain.PaymentStatus{"from": from, "to": payment.Status})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WarnContext(ctx, "Payment status forced",
+		slog.String("operator_id", operator.ID),
+		slog.String("status", string(payment.Status)))
+	s.metrics.PaymentStatusChanged(*payment)
+
+	payment.Version++
+	return payment, nil
+}
+
+// evaluateRisk assesses a new payment against the recent payments of the user.
+func (s *Service) evaluateRisk(ctx context.Context, request domain.CreatePaymentRequest, funding domain.Money) (*domain.RiskAssessment, error) {
+	recent, err := s.paymentRepo.List(ctx, domain.PaymentFilter{
+		UserID:      request.UserID,
+		CreatedFrom: time.Now().Add(-_riskHistoryWindow),
+		Limit:       domain.MaxPageSize,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get payment history for risk evaluation",
+			slog.Any("error", err))
+
+		return nil, domain.ErrEvaluateRisk
+	}
+
+	assessment, err := s.riskEvaluator.Evaluate(ctx, domain.RiskInput{
+		Request:  request,
+		Funding:  funding,
+		History:  recent.Payments,
+		Metadata: request.Metadata,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to evaluate payment risk",
+			slog.Any("error", err))
+
+		return nil, domain.ErrEvaluateRisk
+	}
+
+	return assessment, nil
+}
+
+// publishInitiated stores the PaymentInitiated event of the payment in the
+// outbox.
+func (s *Service) publishInitiated(ctx context.Context, tx pgx.Tx, payment *domain.Payment) error {
+	paymentInitiatedEvent := &domain.PaymentInitiatedEvent{
+		UserID:          payment.UserID,
+		ClientNumber:    payment.ClientNumber,
+		ServiceID:       payment.ServiceID,
+		Amount:          payment.Amount,
+		Currency:        payment.Currency,
+		FundingAmount:   payment.FundingAmount,
+		FundingCurrency: payment.FundingCurrency,
+		FXRate:          payment.FXRate,
+		FXQuoteID:       payment.FXQuoteID,
+		TransactionID:   payment.ID,
+		RequestID:       correlation.RequestID(ctx),
+	}
+
+	// the event is stored with the payment and relayed to the broker later,
+	// so a broker outage can neither lose it nor publish a rolled back payment
+	message, errMessage := domain.NewOutboxMessage(uidgen.NewUUID(),
+		domain.EventTypePaymentInitiated, payment.ID, paymentInitiatedEvent)
+	if errMessage != nil {
+		s.logger.ErrorContext(ctx, "failed to build outbox message",
+			slog.Any("error", errMessage))
+
+		return domain.ErrCreateOutboxMessage
+	}
+
+	errOutbox := s.outboxRepo.Create(ctx, tx, message)
+	if errOutbox != nil {
+		s.logger.ErrorContext(ctx, "failed to create outbox message",
+			slog.Any("error", errOutbox))
+
+		return domain.ErrCreateOutboxMessage
+	}
+
 	return nil
 }
+
+// publishCancelled stores the PaymentCancelled event of the payment in the
+// outbox, telling the processor to drop it.
+func (s *Service) publishCancelled(ctx context.Context, tx pgx.Tx, payment *domain.Payment) error {
+	paymentCancelledEvent := &domain.PaymentCancelledEvent{
+		UserID:        payment.UserID,
+		TransactionID: payment.ID,
+	}
+
+	message, errMessage := domain.NewOutboxMessage(uidgen.NewUUID(),
+		domain.EventTypePaymentCancelled, payment.ID, paymentCancelledEvent)
+	if errMessage != nil {
+		s.logger.ErrorContext(ctx, "failed to build outbox message",
+			slog.Any("error", errMessage))
+
+		return domain.ErrCreateOutboxMessage
+	}
+
+	errOutbox := s.outboxRepo.Create(ctx, tx, message)
+	if errOutbox != nil {
+		s.logger.ErrorContext(ctx, "failed to create outbox message",
+			slog.Any("error", errOutbox))
+
+		return domain.ErrCreateOutboxMessage
+	}
+
+	return nil
+}
+
+func (s *Service) Get(ctx context.Context, userID, paymentID string) (*domain.Payment, error) {
+	ctx = correlation.WithPaymentID(ctx, paymentID)
+
+	payment, err := s.paymentRepo.Get(ctx, userID, paymentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return nil, domain.ErrPaymentNotFound
+		}
+
+		s.logger.ErrorContext(ctx, "failed to get payment",
+			slog.Any("error", err))
+
+		return nil, domain.ErrGetPayment
+	}
+
+	return payment, nil
+}
+
+// History returns the status timeline of a payment of the user.
+func (s *Service) History(ctx context.Context, userID, paymentID string) ([]domain.PaymentStatusChange, error) {
+	ctx = correlation.WithPaymentID(ctx, paymentID)
+
+	if _, err := s.Get(ctx, userID, paymentID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.paymentRepo.History(ctx, paymentID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get payment history",
+			slog.Any("error", err))
+
+		return nil, domain.ErrGetPaymentHistory
+	}
+
+	return history, nil
+}
+
+func (s *Service) List(ctx context.Context, filter domain.PaymentFilter) (*domain.PaymentPage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = domain.DefaultPageSize
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidPaymentFilter, err)
+	}
+
+	page, err := s.paymentRepo.List(ctx, filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			return nil, domain.ErrInvalidCursor
+		}
+
+		s.logger.ErrorContext(ctx, "failed to list payments",
+			slog.Any("error", err),
+			slog.String("user_id", filter.UserID))
+
+		return nil, domain.ErrListPayments
+	}
+
+	return page, nil
+}
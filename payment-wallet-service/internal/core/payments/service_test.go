@@ -6,8 +6,12 @@ import (
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
 	"log/slog"
 	"testing"
+	"time"
 
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
@@ -18,14 +22,26 @@ func TestNewPaymentService(t *testing.T) {
 	mockDB := mocks.NewMockDatabase(gomock.NewController(t))
 	mockPaymentRepo := mocks.NewMockPaymentRepository(gomock.NewController(t))
 	mockBalanceService := mocks.NewMockBalanceService(gomock.NewController(t))
-	mockPublisher := mocks.NewMockPublisher(gomock.NewController(t))
+	mockOutboxRepo := mocks.NewMockOutboxRepository(gomock.NewController(t))
+	mockFXService := mocks.NewMockFXService(gomock.NewController(t))
+	mockLimitService := mocks.NewMockLimitService(gomock.NewController(t))
+	mockRiskEvaluator := mocks.NewMockRiskEvaluator(gomock.NewController(t))
+	mockBillerService := mocks.NewMockBillerService(gomock.NewController(t))
+	mockAuditService := mocks.NewMockAuditService(gomock.NewController(t))
+	mockMetrics := mocks.NewMockPaymentMetrics(gomock.NewController(t))
 
 	config := ServiceConfig{
 		Logger:            logger,
 		DB:                mockDB,
 		PaymentRepository: mockPaymentRepo,
 		BalanceService:    mockBalanceService,
-		PublisherService:  mockPublisher,
+		OutboxRepository:  mockOutboxRepo,
+		FXService:         mockFXService,
+		LimitService:      mockLimitService,
+		RiskEvaluator:     mockRiskEvaluator,
+		BillerService:     mockBillerService,
+		AuditService:      mockAuditService,
+		Metrics:           mockMetrics,
 	}
 
 	service := NewPaymentService(config)
@@ -35,7 +51,13 @@ func TestNewPaymentService(t *testing.T) {
 	assert.Equal(t, mockDB, service.db)
 	assert.Equal(t, mockPaymentRepo, service.paymentRepo)
 	assert.Equal(t, mockBalanceService, service.balanceService)
-	assert.Equal(t, mockPublisher, service.publisherService)
+	assert.Equal(t, mockOutboxRepo, service.outboxRepo)
+	assert.Equal(t, mockFXService, service.fxService)
+	assert.Equal(t, mockLimitService, service.limitService)
+	assert.Equal(t, mockRiskEvaluator, service.riskEvaluator)
+	assert.Equal(t, mockBillerService, service.billerService)
+	assert.Equal(t, mockAuditService, service.auditService)
+	assert.Equal(t, mockMetrics, service.metrics)
 }
 
 func TestService_Create(t *testing.T) {
@@ -45,23 +67,38 @@ func TestService_Create(t *testing.T) {
 	mockDB := mocks.NewMockDatabase(ctrl)
 	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
 	mockBalanceService := mocks.NewMockBalanceService(ctrl)
-	mockPublisher := mocks.NewMockPublisher(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockFXService := mocks.NewMockFXService(ctrl)
+	mockLimitService := mocks.NewMockLimitService(ctrl)
+	mockRiskEvaluator := mocks.NewMockRiskEvaluator(ctrl)
+	mockBillerService := mocks.NewMockBillerService(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
 
 	service := &Service{
-		logger:           slog.Default(),
-		db:               mockDB,
-		paymentRepo:      mockPaymentRepo,
-		balanceService:   mockBalanceService,
-		publisherService: mockPublisher,
+		logger:         slog.Default(),
+		db:             mockDB,
+		paymentRepo:    mockPaymentRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
+		fxService:      mockFXService,
+		limitService:   mockLimitService,
+		riskEvaluator:  mockRiskEvaluator,
+		billerService:  mockBillerService,
+		metrics:        mockMetrics,
 	}
 
-	ctx := context.Background()
+	ctx := correlation.WithRequestID(context.Background(), "request-123")
+	uidgen.UseUUID(func() string { return "payment-123" })
+	t.Cleanup(func() { uidgen.UseUUID(uuid.NewString) })
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
 	request := domain.CreatePaymentRequest{
-		IdempotencyKey: "test-key-123",
-		UserID:         "user-123",
-		Amount:         10050,
-		ServiceID:      "service-1",
-		ClientNumber:   "client-456",
+		IdempotencyKey:  "test-key-123",
+		UserID:          "user-123",
+		Amount:          10050,
+		Currency:        domain.CurrencyARS,
+		FundingCurrency: domain.CurrencyARS,
+		ServiceID:       "service-1",
+		ClientNumber:    "client-456",
 	}
 
 	t.Run("successful payment creation", func(t *testing.T) {
@@ -75,14 +112,38 @@ func TestService_Create(t *testing.T) {
 
 		mockPaymentRepo.EXPECT().
 			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
-			Return(false, nil).Times(1)
+			Return(nil, nil).Times(1)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil).Times(1)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filter domain.PaymentFilter) (*domain.PaymentPage, error) {
+				assert.Equal(t, request.UserID, filter.UserID)
+				assert.False(t, filter.CreatedFrom.IsZero())
+				return &domain.PaymentPage{}, nil
+			}).Times(1)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input domain.RiskInput) (*domain.RiskAssessment, error) {
+				assert.Equal(t, request, input.Request)
+				assert.Equal(t, domain.NewMoney(request.Amount, request.Currency), input.Funding)
+				return &domain.RiskAssessment{Decision: domain.RiskAllow}, nil
+			}).Times(1)
 
 		mockBalanceService.EXPECT().
-			ReserveFunds(ctx, gomock.Any(), request.UserID, request.Amount).
+			ReserveFunds(paymentCtx, gomock.Any(), request.UserID, gomock.Any(), domain.NewMoney(request.Amount, request.Currency)).
+			Return(nil).Times(1)
+
+		mockLimitService.EXPECT().
+			Check(paymentCtx, gomock.Any(), request.UserID, domain.NewMoney(request.Amount, request.Currency)).
 			Return(nil).Times(1)
 
 		mockPaymentRepo.EXPECT().
-			Create(ctx, gomock.Any(), gomock.Any()).
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
 			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment) error {
 				capturedTx = tx
 				assert.Nil(t, capturedTx)
@@ -90,7 +151,12 @@ func TestService_Create(t *testing.T) {
 				assert.Equal(t, request.IdempotencyKey, payment.IdempotencyKey)
 				assert.Equal(t, request.UserID, payment.UserID)
 				assert.Equal(t, request.Amount, payment.Amount)
-				assert.Equal(t, Pending, payment.Status)
+				assert.Equal(t, request.Currency, payment.Currency)
+				assert.Equal(t, request.Amount, payment.FundingAmount)
+				assert.Equal(t, request.FundingCurrency, payment.FundingCurrency)
+				assert.Empty(t, payment.FXRate)
+				assert.Empty(t, payment.FXQuoteID)
+				assert.Equal(t, domain.StatusPending, payment.Status)
 				assert.Equal(t, request.ServiceID, payment.ServiceID)
 				assert.Equal(t, request.ClientNumber, payment.ClientNumber)
 				assert.False(t, payment.CreatedAt.IsZero())
@@ -98,14 +164,152 @@ func TestService_Create(t *testing.T) {
 				return nil
 			}).Times(1)
 
-		mockPublisher.EXPECT().
-			Publish(ctx, gomock.Any()).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.NotEmpty(t, message.ID)
+				assert.NotEmpty(t, message.AggregateID)
+				assert.Equal(t, domain.EventTypePaymentInitiated, message.EventType)
+				assert.Contains(t, string(message.Payload), request.ServiceID)
+				assert.Contains(t, string(message.Payload), `"request_id":"request-123"`)
+				return nil
+			}).Times(1)
+
+		mockFXService.EXPECT().Quote(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockMetrics.EXPECT().
+			PaymentCreated(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusPending, payment.Status)
+			}).Times(1)
+
+		payment, replayed, err := service.Create(ctx, request)
+		assert.NoError(t, err)
+		assert.False(t, replayed)
+		assert.NotNil(t, payment)
+		assert.Equal(t, domain.StatusPending, payment.Status)
+	})
+
+	t.Run("payment funded in another currency reserves the quoted amount", func(t *testing.T) {
+		usdRequest := request
+		usdRequest.Amount = 10000
+		usdRequest.Currency = domain.CurrencyUSD
+		quote := &domain.FXQuote{
+			ID:     "quote-1",
+			Source: domain.NewMoney(10000, domain.CurrencyUSD),
+			Target: domain.NewMoney(12687500, domain.CurrencyARS),
+			Rate:   "1268.7500000000",
+		}
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		).Times(1)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), usdRequest.IdempotencyKey).
+			Return(nil, nil).Times(1)
+
+		mockBillerService.EXPECT().
+			Check(ctx, usdRequest).
+			Return(nil).Times(1)
+
+		mockFXService.EXPECT().
+			Quote(ctx, domain.NewMoney(10000, domain.CurrencyUSD), domain.CurrencyARS).
+			Return(quote, nil).Times(1)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(&domain.RiskAssessment{Decision: domain.RiskAllow}, nil)
+
+		mockBalanceService.EXPECT().
+			ReserveFunds(paymentCtx, gomock.Any(), usdRequest.UserID, gomock.Any(), quote.Target).
+			Return(nil).Times(1)
+
+		mockLimitService.EXPECT().
+			Check(paymentCtx, gomock.Any(), usdRequest.UserID, quote.Target).
+			Return(nil).Times(1)
+
+		mockPaymentRepo.EXPECT().
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment) error {
+				assert.Equal(t, int64(10000), payment.Amount)
+				assert.Equal(t, domain.CurrencyUSD, payment.Currency)
+				assert.Equal(t, int64(12687500), payment.FundingAmount)
+				assert.Equal(t, domain.CurrencyARS, payment.FundingCurrency)
+				assert.Equal(t, quote.Rate, payment.FXRate)
+				assert.Equal(t, quote.ID, payment.FXQuoteID)
+				return nil
+			}).Times(1)
+
+		mockOutboxRepo.EXPECT().
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Contains(t, string(message.Payload), `"fx_rate":"1268.7500000000"`)
+				assert.Contains(t, string(message.Payload), `"fx_quote_id":"quote-1"`)
+				assert.Contains(t, string(message.Payload), `"funding_amount":12687500`)
+				return nil
+			}).Times(1)
 
-		err := service.Create(ctx, request)
+		mockMetrics.EXPECT().PaymentCreated(gomock.Any()).Times(1)
+
+		payment, replayed, err := service.Create(ctx, usdRequest)
 		assert.NoError(t, err)
+		assert.False(t, replayed)
+		assert.Equal(t, quote.Target, payment.Funding())
+	})
+
+	t.Run("no exchange rate for the funding currency", func(t *testing.T) {
+		usdRequest := request
+		usdRequest.Currency = domain.CurrencyUSD
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		).Times(1)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), usdRequest.IdempotencyKey).
+			Return(nil, nil).Times(1)
+
+		mockBillerService.EXPECT().
+			Check(ctx, usdRequest).
+			Return(nil).Times(1)
+
+		mockFXService.EXPECT().
+			Quote(ctx, gomock.Any(), domain.CurrencyARS).
+			Return(nil, domain.ErrFXRateUnavailable).Times(1)
+
+		mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockPaymentRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, _, err := service.Create(ctx, usdRequest)
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrFXRateUnavailable, err)
 	})
 
 	t.Run("idempotency key already exists", func(t *testing.T) {
+		existing := &domain.Payment{
+			ID:              "payment-1",
+			IdempotencyKey:  request.IdempotencyKey,
+			UserID:          request.UserID,
+			Amount:          request.Amount,
+			Currency:        request.Currency,
+			FundingAmount:   request.Amount,
+			FundingCurrency: request.FundingCurrency,
+			Status:          domain.StatusApproved,
+			ServiceID:       request.ServiceID,
+			ClientNumber:    request.ClientNumber,
+		}
+
 		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
 			func(ctx context.Context, fn func(*pgx.Tx) error) error {
 				dummyTx := new(pgx.Tx)
@@ -115,14 +319,90 @@ func TestService_Create(t *testing.T) {
 
 		mockPaymentRepo.EXPECT().
 			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
-			Return(true, nil)
+			Return(existing, nil)
 
-		mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 		mockPaymentRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
-		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockMetrics.EXPECT().PaymentCreated(gomock.Any()).Times(0)
 
-		err := service.Create(ctx, request)
+		payment, replayed, err := service.Create(ctx, request)
 		assert.NoError(t, err)
+		assert.True(t, replayed)
+		assert.Equal(t, existing, payment)
+	})
+
+	t.Run("idempotency key reused with a different request", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(&domain.Payment{
+				ID:             "payment-1",
+				IdempotencyKey: request.IdempotencyKey,
+				UserID:         request.UserID,
+				Amount:         request.Amount + 1,
+				ServiceID:      request.ServiceID,
+				ClientNumber:   request.ClientNumber,
+			}, nil)
+
+		mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockPaymentRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockMetrics.EXPECT().PaymentRefused(domain.RefusalIdempotencyKeyReused).Times(1)
+
+		payment, replayed, err := service.Create(ctx, request)
+		assert.Nil(t, payment)
+		assert.False(t, replayed)
+		assert.Equal(t, domain.ErrIdempotencyKeyReused, err)
+	})
+
+	t.Run("concurrent request with the same idempotency key", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(&domain.RiskAssessment{Decision: domain.RiskAllow}, nil)
+
+		mockBalanceService.EXPECT().
+			ReserveFunds(paymentCtx, gomock.Any(), request.UserID, gomock.Any(), domain.NewMoney(request.Amount, request.Currency)).
+			Return(nil)
+
+		mockLimitService.EXPECT().
+			Check(paymentCtx, gomock.Any(), request.UserID, gomock.Any()).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
+			Return(domain.ErrIdempotencyKeyConflict)
+
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrIdempotencyKeyConflict, err)
 	})
 
 	t.Run("error checking idempotency", func(t *testing.T) {
@@ -137,13 +417,185 @@ func TestService_Create(t *testing.T) {
 
 		mockPaymentRepo.EXPECT().
 			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
-			Return(false, expectedError)
+			Return(nil, expectedError)
 
-		err := service.Create(ctx, request)
+		_, _, err := service.Create(ctx, request)
 		assert.Error(t, err)
 		assert.Equal(t, domain.ErrCheckIdempotency, err)
 	})
 
+	t.Run("biller does not accept the payment", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(domain.ErrBillerDisabled)
+
+		mockPaymentRepo.EXPECT().List(gomock.Any(), gomock.Any()).Times(0)
+		mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockPaymentRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockMetrics.EXPECT().PaymentRefused(domain.RefusalBiller).Times(1)
+
+		payment, _, err := service.Create(ctx, request)
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrBillerDisabled, err)
+	})
+
+	t.Run("payment flagged for review is held without notifying the processor", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(&domain.RiskAssessment{
+				Decision: domain.RiskReview,
+				Reasons:  []string{"large amount to a new service"},
+			}, nil)
+
+		mockBalanceService.EXPECT().
+			ReserveFunds(paymentCtx, gomock.Any(), request.UserID, gomock.Any(), domain.NewMoney(request.Amount, request.Currency)).
+			Return(nil)
+
+		mockLimitService.EXPECT().
+			Check(paymentCtx, gomock.Any(), request.UserID, gomock.Any()).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment) error {
+				assert.Equal(t, domain.StatusPendingReview, payment.Status)
+				return nil
+			})
+
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockMetrics.EXPECT().
+			PaymentCreated(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusPendingReview, payment.Status)
+			}).Times(1)
+
+		payment, _, err := service.Create(ctx, request)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusPendingReview, payment.Status)
+	})
+
+	t.Run("payment denied by the risk evaluation", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(&domain.RiskAssessment{Decision: domain.RiskDeny}, nil)
+
+		mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockPaymentRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockMetrics.EXPECT().PaymentRefused(domain.RefusalRiskDenied).Times(1)
+
+		payment, _, err := service.Create(ctx, request)
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentDenied, err)
+	})
+
+	t.Run("error getting the payment history for the risk evaluation", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(nil, errors.New("database error"))
+
+		mockRiskEvaluator.EXPECT().Evaluate(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrEvaluateRisk, err)
+	})
+
+	t.Run("error evaluating risk", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(nil, errors.New("risk engine error"))
+
+		mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrEvaluateRisk, err)
+	})
+
 	t.Run("error reserving funds", func(t *testing.T) {
 		expectedError := errors.New("insufficient balance")
 
@@ -156,17 +608,82 @@ func TestService_Create(t *testing.T) {
 
 		mockPaymentRepo.EXPECT().
 			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
-			Return(false, nil)
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(&domain.RiskAssessment{Decision: domain.RiskAllow}, nil)
 
 		mockBalanceService.EXPECT().
-			ReserveFunds(ctx, gomock.Any(), request.UserID, request.Amount).
+			ReserveFunds(paymentCtx, gomock.Any(), request.UserID, gomock.Any(), domain.NewMoney(request.Amount, request.Currency)).
 			Return(expectedError)
 
-		err := service.Create(ctx, request)
+		mockMetrics.EXPECT().PaymentRefused(gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
 		assert.Error(t, err)
 		assert.Equal(t, expectedError, err)
 	})
 
+	t.Run("spending limit exceeded", func(t *testing.T) {
+		limitErr := &domain.LimitExceededError{
+			Limit:     domain.LimitDailyAmount,
+			Max:       20000,
+			Used:      15000,
+			Requested: request.Amount,
+			Currency:  request.Currency,
+		}
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(&domain.RiskAssessment{Decision: domain.RiskAllow}, nil)
+
+		mockBalanceService.EXPECT().
+			ReserveFunds(paymentCtx, gomock.Any(), request.UserID, gomock.Any(), domain.NewMoney(request.Amount, request.Currency)).
+			Return(nil)
+
+		mockLimitService.EXPECT().
+			Check(paymentCtx, gomock.Any(), request.UserID, domain.NewMoney(request.Amount, request.Currency)).
+			Return(limitErr)
+
+		mockPaymentRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		mockMetrics.EXPECT().PaymentRefused(domain.RefusalLimitExceeded).Times(1)
+
+		payment, _, err := service.Create(ctx, request)
+		assert.Nil(t, payment)
+		assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+		assert.Equal(t, limitErr, err)
+	})
+
 	t.Run("error creating payment", func(t *testing.T) {
 		expectedError := errors.New("create payment error")
 
@@ -179,31 +696,1052 @@ func TestService_Create(t *testing.T) {
 
 		mockPaymentRepo.EXPECT().
 			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
-			Return(false, nil)
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(&domain.RiskAssessment{Decision: domain.RiskAllow}, nil)
 
 		mockBalanceService.EXPECT().
-			ReserveFunds(ctx, gomock.Any(), request.UserID, request.Amount).
+			ReserveFunds(paymentCtx, gomock.Any(), request.UserID, gomock.Any(), domain.NewMoney(request.Amount, request.Currency)).
+			Return(nil)
+
+		mockLimitService.EXPECT().
+			Check(paymentCtx, gomock.Any(), request.UserID, gomock.Any()).
 			Return(nil)
 
 		mockPaymentRepo.EXPECT().
-			Create(ctx, gomock.Any(), gomock.Any()).
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
 			Return(expectedError)
 
-		mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-		err := service.Create(ctx, request)
+		_, _, err := service.Create(ctx, request)
 		assert.Error(t, err)
 		assert.Equal(t, domain.ErrCreatePayment, err)
 	})
 
-	t.Run("transaction rollback on error", func(t *testing.T) {
-		expectedError := errors.New("transaction error")
-
-		mockDB.EXPECT().WithTx(ctx, gomock.Any()).Return(expectedError)
-
-		err := service.Create(ctx, request)
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+	t.Run("error creating outbox message", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(*pgx.Tx) error) error {
+				dummyTx := new(pgx.Tx)
+				return fn(dummyTx)
+			},
+		)
+
+		mockPaymentRepo.EXPECT().
+			CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, nil)
+
+		mockBillerService.EXPECT().
+			Check(ctx, request).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			List(ctx, gomock.Any()).
+			Return(&domain.PaymentPage{}, nil)
+
+		mockRiskEvaluator.EXPECT().
+			Evaluate(ctx, gomock.Any()).
+			Return(&domain.RiskAssessment{Decision: domain.RiskAllow}, nil)
+
+		mockBalanceService.EXPECT().
+			ReserveFunds(paymentCtx, gomock.Any(), request.UserID, gomock.Any(), domain.NewMoney(request.Amount, request.Currency)).
+			Return(nil)
+
+		mockLimitService.EXPECT().
+			Check(paymentCtx, gomock.Any(), request.UserID, gomock.Any()).
+			Return(nil)
+
+		mockPaymentRepo.EXPECT().
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		mockOutboxRepo.EXPECT().
+			Create(paymentCtx, gomock.Any(), gomock.Any()).
+			Return(errors.New("outbox error"))
+
+		_, _, err := service.Create(ctx, request)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrCreateOutboxMessage, err)
+	})
+
+	t.Run("transaction rollback on error", func(t *testing.T) {
+		expectedError := errors.New("transaction error")
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).Return(expectedError)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Error(t, err)
+		assert.Equal(t, expectedError, err)
+	})
+}
+
+func TestService_Update(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		paymentRepo:    mockPaymentRepo,
+		balanceService: mockBalanceService,
+		metrics:        mockMetrics,
+	}
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	pendingPayment := func() *domain.Payment {
+		return &domain.Payment{
+			ID:              "payment-123",
+			UserID:          "user-123",
+			Amount:          10050,
+			Currency:        domain.CurrencyARS,
+			FundingAmount:   10050,
+			FundingCurrency: domain.CurrencyARS,
+			Status:          domain.StatusPending,
+		}
+	}
+
+	t.Run("approved result confirms reserve", func(t *testing.T) {
+		event := domain.PaymentResultEvent{
+			TransactionID:      "payment-123",
+			Status:             domain.StatusApproved,
+			ProcessorReference: "proc-ref-1",
+		}
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(pendingPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusApproved, payment.Status)
+				assert.Equal(t, event.ProcessorReference, payment.ProcessorReference)
+				assert.False(t, payment.UpdatedAt.IsZero())
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(10050, domain.CurrencyARS), true).Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusApproved, payment.Status)
+			}).Times(1)
+
+		err := service.Update(ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejected result releases funds", func(t *testing.T) {
+		event := domain.PaymentResultEvent{
+			TransactionID: "payment-123",
+			Status:        domain.StatusRejected,
+			FailureReason: "declined by biller",
+		}
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(pendingPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusRejected, payment.Status)
+				assert.Equal(t, event.FailureReason, payment.FailureReason)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(10050, domain.CurrencyARS), false).Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusRejected, payment.Status)
+			}).Times(1)
+
+		err := service.Update(ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("processing result keeps the reserve", func(t *testing.T) {
+		event := domain.PaymentResultEvent{TransactionID: "payment-123", Status: domain.StatusProcessing}
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(pendingPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusProcessing, payment.Status)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusProcessing, payment.Status)
+			}).Times(1)
+
+		err := service.Update(ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("result for a processing payment settles it", func(t *testing.T) {
+		event := domain.PaymentResultEvent{TransactionID: "payment-123", Status: domain.StatusRejected}
+		payment := pendingPayment()
+		payment.Status = domain.StatusProcessing
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(payment, nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusProcessing).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(10050, domain.CurrencyARS), false).Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusRejected, payment.Status)
+			}).Times(1)
+
+		err := service.Update(ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("payment already in a final state", func(t *testing.T) {
+		event := domain.PaymentResultEvent{TransactionID: "payment-123", Status: domain.StatusApproved}
+		payment := pendingPayment()
+		payment.Status = domain.StatusApproved
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(payment, nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockBalanceService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMetrics.EXPECT().PaymentStatusChanged(gomock.Any()).Times(0)
+
+		err := service.Update(ctx, event)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid result status", func(t *testing.T) {
+		event := domain.PaymentResultEvent{TransactionID: "payment-123", Status: "UNKNOWN"}
+
+		err := service.Update(ctx, event)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidPaymentResult, err)
+	})
+
+	t.Run("missing transaction id", func(t *testing.T) {
+		event := domain.PaymentResultEvent{Status: domain.StatusApproved}
+
+		err := service.Update(ctx, event)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrInvalidPaymentResult, err)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		event := domain.PaymentResultEvent{TransactionID: "payment-123", Status: domain.StatusApproved}
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(nil, domain.ErrPaymentNotFound).Times(1)
+
+		err := service.Update(ctx, event)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrPaymentNotFound, err)
+	})
+
+	t.Run("error getting payment", func(t *testing.T) {
+		event := domain.PaymentResultEvent{TransactionID: "payment-123", Status: domain.StatusApproved}
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(nil, errors.New("database error")).Times(1)
+
+		err := service.Update(ctx, event)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrGetPayment, err)
+	})
+
+	t.Run("error updating payment", func(t *testing.T) {
+		event := domain.PaymentResultEvent{TransactionID: "payment-123", Status: domain.StatusApproved}
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(pendingPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			Return(errors.New("database error")).Times(1)
+		mockBalanceService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.Update(ctx, event)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrUpdatePayment, err)
+	})
+
+	t.Run("error updating balance", func(t *testing.T) {
+		event := domain.PaymentResultEvent{TransactionID: "payment-123", Status: domain.StatusApproved}
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), event.TransactionID).
+			Return(pendingPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(10050, domain.CurrencyARS), true).
+			Return(domain.ErrUpdateBalance).Times(1)
+
+		err := service.Update(ctx, event)
+		assert.Error(t, err)
+		assert.Equal(t, domain.ErrUpdateBalance, err)
+	})
+}
+
+func TestService_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		paymentRepo:    mockPaymentRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
+		metrics:        mockMetrics,
+	}
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	pendingPayment := func() *domain.Payment {
+		return &domain.Payment{
+			ID:              "payment-123",
+			UserID:          "user-123",
+			Amount:          5000,
+			Currency:        domain.CurrencyUSD,
+			FundingAmount:   6343750,
+			FundingCurrency: domain.CurrencyARS,
+			FXRate:          "1268.7500000000",
+			Status:          domain.StatusPending,
+			Version:         3,
+		}
+	}
+
+	t.Run("pending payment is cancelled and funds released", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").Return(pendingPayment(), nil).Times(1)
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusCancelled, payment.Status)
+				assert.Equal(t, int64(3), payment.Version)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(6343750, domain.CurrencyARS), false).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Equal(t, domain.EventTypePaymentCancelled, message.EventType)
+				assert.Equal(t, "payment-123", message.AggregateID)
+				return nil
+			}).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusCancelled, payment.Status)
+			}).Times(1)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusCancelled, payment.Status)
+		assert.Equal(t, int64(4), payment.Version)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").
+			Return(nil, domain.ErrPaymentNotFound).Times(1)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentNotFound, err)
+	})
+
+	t.Run("payment is not pending", func(t *testing.T) {
+		approved := pendingPayment()
+		approved.Status = domain.StatusApproved
+
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").Return(approved, nil).Times(1)
+		mockDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentNotCancellable, err)
+	})
+
+	t.Run("payment picked up by the processor", func(t *testing.T) {
+		processing := pendingPayment()
+		processing.Status = domain.StatusProcessing
+
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").Return(processing, nil).Times(1)
+		mockDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentNotCancellable, err)
+	})
+
+	t.Run("payment modified concurrently", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").Return(pendingPayment(), nil).Times(1)
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			Return(domain.ErrPaymentConflict).Times(1)
+		mockBalanceService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentConflict, err)
+	})
+
+	t.Run("error updating payment", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").Return(pendingPayment(), nil).Times(1)
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			Return(errors.New("database error")).Times(1)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrUpdatePayment, err)
+	})
+
+	t.Run("error releasing funds", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").Return(pendingPayment(), nil).Times(1)
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(6343750, domain.CurrencyARS), false).
+			Return(domain.ErrUpdateBalance).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrUpdateBalance, err)
+	})
+
+	t.Run("error creating outbox message", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").Return(pendingPayment(), nil).Times(1)
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(6343750, domain.CurrencyARS), false).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			Return(errors.New("outbox error")).Times(1)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrCreateOutboxMessage, err)
+	})
+
+	t.Run("payment held for review is cancelled without notifying the processor", func(t *testing.T) {
+		held := pendingPayment()
+		held.Status = domain.StatusPendingReview
+
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").Return(held, nil).Times(1)
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPendingReview).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(6343750, domain.CurrencyARS), false).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusCancelled, payment.Status)
+			}).Times(1)
+
+		payment, err := service.Cancel(ctx, "user-123", "payment-123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusCancelled, payment.Status)
+	})
+}
+
+func TestService_Approve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockAuditService := mocks.NewMockAuditService(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		paymentRepo:    mockPaymentRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
+		auditService:   mockAuditService,
+		metrics:        mockMetrics,
+	}
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
+	operator := domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleOps}}
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	heldPayment := func() *domain.Payment {
+		return &domain.Payment{
+			ID:              "payment-123",
+			UserID:          "user-123",
+			Amount:          5000,
+			Currency:        domain.CurrencyARS,
+			FundingAmount:   5000,
+			FundingCurrency: domain.CurrencyARS,
+			ServiceID:       "service-1",
+			Status:          domain.StatusPendingReview,
+			Version:         1,
+		}
+	}
+
+	t.Run("held payment is released to the processor", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(heldPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPendingReview).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusPending, payment.Status)
+				return nil
+			}).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Equal(t, domain.EventTypePaymentInitiated, message.EventType)
+				assert.Equal(t, "payment-123", message.AggregateID)
+				return nil
+			}).Times(1)
+		mockAuditService.EXPECT().Append(paymentCtx, gomock.Any(), operator, domain.AuditApprovePayment, "payment-123", "", gomock.Any()).
+			Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusPending, payment.Status)
+			}).Times(1)
+
+		payment, err := service.Approve(ctx, operator, "payment-123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusPending, payment.Status)
+		assert.Equal(t, int64(2), payment.Version)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(nil, domain.ErrPaymentNotFound).Times(1)
+
+		payment, err := service.Approve(ctx, operator, "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentNotFound, err)
+	})
+
+	t.Run("payment is not under review", func(t *testing.T) {
+		pending := heldPayment()
+		pending.Status = domain.StatusPending
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(pending, nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.Approve(ctx, operator, "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentNotUnderReview, err)
+	})
+
+	t.Run("error getting payment", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(nil, errors.New("database error")).Times(1)
+
+		payment, err := service.Approve(ctx, operator, "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrGetPayment, err)
+	})
+
+	t.Run("error updating payment", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(heldPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPendingReview).
+			Return(errors.New("database error")).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.Approve(ctx, operator, "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrUpdatePayment, err)
+	})
+
+	t.Run("error creating outbox message", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(heldPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPendingReview).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			Return(errors.New("outbox error")).Times(1)
+
+		payment, err := service.Approve(ctx, operator, "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrCreateOutboxMessage, err)
+	})
+
+	t.Run("error recording the audit entry", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(heldPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPendingReview).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockAuditService.EXPECT().Append(paymentCtx, gomock.Any(), operator, domain.AuditApprovePayment, "payment-123", "", gomock.Any()).
+			Return(domain.ErrRecordAudit).Times(1)
+
+		payment, err := service.Approve(ctx, operator, "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrRecordAudit, err)
+	})
+}
+
+func TestService_Reject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockAuditService := mocks.NewMockAuditService(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		paymentRepo:    mockPaymentRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
+		auditService:   mockAuditService,
+		metrics:        mockMetrics,
+	}
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
+	operator := domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleOps}}
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	heldPayment := func() *domain.Payment {
+		return &domain.Payment{
+			ID:              "payment-123",
+			UserID:          "user-123",
+			Amount:          5000,
+			Currency:        domain.CurrencyUSD,
+			FundingAmount:   6343750,
+			FundingCurrency: domain.CurrencyARS,
+			Status:          domain.StatusPendingReview,
+			Version:         1,
+		}
+	}
+
+	t.Run("held payment is rejected and funds released", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(heldPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPendingReview).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusRejected, payment.Status)
+				assert.NotEmpty(t, payment.FailureReason)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(6343750, domain.CurrencyARS), false).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockAuditService.EXPECT().Append(paymentCtx, gomock.Any(), operator, domain.AuditRejectPayment, "payment-123", "", gomock.Any()).
+			Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusRejected, payment.Status)
+			}).Times(1)
+
+		payment, err := service.Reject(ctx, operator, "payment-123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusRejected, payment.Status)
+	})
+
+	t.Run("payment already settled", func(t *testing.T) {
+		approved := heldPayment()
+		approved.Status = domain.StatusApproved
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(approved, nil).Times(1)
+		mockBalanceService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.Reject(ctx, operator, "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentNotUnderReview, err)
+	})
+
+	t.Run("error releasing funds", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(heldPayment(), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPendingReview).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", domain.NewMoney(6343750, domain.CurrencyARS), false).
+			Return(domain.ErrUpdateBalance).Times(1)
+
+		payment, err := service.Reject(ctx, operator, "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrUpdateBalance, err)
+	})
+}
+
+func TestService_ForceStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockAuditService := mocks.NewMockAuditService(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		paymentRepo:    mockPaymentRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
+		auditService:   mockAuditService,
+		metrics:        mockMetrics,
+	}
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
+	operator := domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleOps}}
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	stuckPayment := func(status domain.PaymentStatus) *domain.Payment {
+		return &domain.Payment{
+			ID:              "payment-123",
+			UserID:          "user-123",
+			Amount:          5000,
+			Currency:        domain.CurrencyARS,
+			FundingAmount:   5000,
+			FundingCurrency: domain.CurrencyARS,
+			Status:          status,
+			Version:         3,
+		}
+	}
+	request := func(status domain.PaymentStatus) domain.ForcePaymentStatusRequest {
+		return domain.ForcePaymentStatusRequest{
+			PaymentID: "payment-123",
+			Status:    status,
+			Reason:    "processor confirmed by phone",
+		}
+	}
+	funding := domain.NewMoney(5000, domain.CurrencyARS)
+
+	t.Run("processing payment forced to approved", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(stuckPayment(domain.StatusProcessing), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusProcessing).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusApproved, payment.Status)
+				assert.Empty(t, payment.FailureReason)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", funding, true).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockAuditService.EXPECT().
+			Append(paymentCtx, gomock.Any(), operator, domain.AuditForcePaymentStatus, "payment-123", "processor confirmed by phone", gomock.Any()).
+			Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusApproved, payment.Status)
+			}).Times(1)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusApproved))
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusApproved, payment.Status)
+		assert.Equal(t, int64(4), payment.Version)
+	})
+
+	t.Run("processing payment forced to rejected tells the processor", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(stuckPayment(domain.StatusProcessing), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusProcessing).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusRejected, payment.Status)
+				assert.Equal(t, "processor confirmed by phone", payment.FailureReason)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", funding, false).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Equal(t, domain.EventTypePaymentCancelled, message.EventType)
+				return nil
+			}).Times(1)
+		mockAuditService.EXPECT().
+			Append(paymentCtx, gomock.Any(), operator, domain.AuditForcePaymentStatus, "payment-123", "processor confirmed by phone", gomock.Any()).
+			Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusRejected, payment.Status)
+			}).Times(1)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusRejected))
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusRejected, payment.Status)
+	})
+
+	t.Run("held payment forced to expired is not published", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(stuckPayment(domain.StatusPendingReview), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPendingReview).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", funding, false).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockAuditService.EXPECT().
+			Append(paymentCtx, gomock.Any(), operator, domain.AuditForcePaymentStatus, "payment-123", "processor confirmed by phone", gomock.Any()).
+			Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusExpired, payment.Status)
+			}).Times(1)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusExpired))
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusExpired, payment.Status)
+	})
+
+	t.Run("settled payment cannot be forced", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(stuckPayment(domain.StatusRejected), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusApproved))
+		assert.Nil(t, payment)
+		assert.ErrorIs(t, err, domain.ErrPaymentNotForceable)
+	})
+
+	t.Run("held payment cannot be forced to approved", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(stuckPayment(domain.StatusPendingReview), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockBalanceService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusApproved))
+		assert.Nil(t, payment)
+		assert.ErrorIs(t, err, domain.ErrPaymentNotForceable)
+	})
+
+	t.Run("pending payment forced to approved", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(stuckPayment(domain.StatusPending), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).Return(nil).Times(1)
+		mockBalanceService.EXPECT().Update(paymentCtx, gomock.Any(), "user-123", "payment-123", funding, true).Return(nil).Times(1)
+		mockAuditService.EXPECT().
+			Append(paymentCtx, gomock.Any(), operator, domain.AuditForcePaymentStatus, "payment-123", "processor confirmed by phone", gomock.Any()).
+			Return(nil).Times(1)
+		mockMetrics.EXPECT().PaymentStatusChanged(gomock.Any()).Times(1)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusApproved))
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusApproved, payment.Status)
+	})
+
+	t.Run("payment cannot be forced to a non final status", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(stuckPayment(domain.StatusProcessing), nil).Times(1)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusPending))
+		assert.Nil(t, payment)
+		assert.ErrorIs(t, err, domain.ErrPaymentNotForceable)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(nil, domain.ErrPaymentNotFound).Times(1)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusApproved))
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentNotFound, err)
+	})
+
+	t.Run("concurrent change", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), "payment-123").Return(stuckPayment(domain.StatusPending), nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPending).
+			Return(domain.ErrPaymentConflict).Times(1)
+		mockBalanceService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		payment, err := service.ForceStatus(ctx, operator, request(domain.StatusApproved))
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentConflict, err)
+	})
+}
+
+func TestService_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		paymentRepo: mockPaymentRepo,
+	}
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
+
+	t.Run("payment found", func(t *testing.T) {
+		expected := &domain.Payment{ID: "payment-123", UserID: "user-123"}
+		mockPaymentRepo.EXPECT().Get(paymentCtx, "user-123", "payment-123").Return(expected, nil).Times(1)
+
+		payment, err := service.Get(ctx, "user-123", "payment-123")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, payment)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(paymentCtx, "user-123", "payment-123").
+			Return(nil, domain.ErrPaymentNotFound).Times(1)
+
+		payment, err := service.Get(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrPaymentNotFound, err)
+	})
+
+	t.Run("error getting payment", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(paymentCtx, "user-123", "payment-123").
+			Return(nil, errors.New("database error")).Times(1)
+
+		payment, err := service.Get(ctx, "user-123", "payment-123")
+		assert.Nil(t, payment)
+		assert.Equal(t, domain.ErrGetPayment, err)
+	})
+}
+
+func TestService_History(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		paymentRepo: mockPaymentRepo,
+	}
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
+
+	t.Run("history of an owned payment", func(t *testing.T) {
+		expected := []domain.PaymentStatusChange{
+			{PaymentID: "payment-123", To: domain.StatusPending},
+			{PaymentID: "payment-123", From: domain.StatusPending, To: domain.StatusApproved},
+		}
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").
+			Return(&domain.Payment{ID: "payment-123"}, nil).Times(1)
+		mockPaymentRepo.EXPECT().History(paymentCtx, "payment-123").Return(expected, nil).Times(1)
+
+		history, err := service.History(ctx, "user-123", "payment-123")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, history)
+	})
+
+	t.Run("payment of another user", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").
+			Return(nil, domain.ErrPaymentNotFound).Times(1)
+		mockPaymentRepo.EXPECT().History(gomock.Any(), gomock.Any()).Times(0)
+
+		history, err := service.History(ctx, "user-123", "payment-123")
+		assert.Nil(t, history)
+		assert.Equal(t, domain.ErrPaymentNotFound, err)
+	})
+
+	t.Run("error getting history", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().Get(correlation.WithPaymentID(paymentCtx, "payment-123"), "user-123", "payment-123").
+			Return(&domain.Payment{ID: "payment-123"}, nil).Times(1)
+		mockPaymentRepo.EXPECT().History(paymentCtx, "payment-123").Return(nil, errors.New("database error")).Times(1)
+
+		history, err := service.History(ctx, "user-123", "payment-123")
+		assert.Nil(t, history)
+		assert.Equal(t, domain.ErrGetPaymentHistory, err)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		paymentRepo: mockPaymentRepo,
+	}
+
+	ctx := context.Background()
+
+	t.Run("default page size is applied", func(t *testing.T) {
+		expected := &domain.PaymentPage{Payments: []domain.Payment{{ID: "payment-123"}}, NextCursor: "next"}
+		mockPaymentRepo.EXPECT().List(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, filter domain.PaymentFilter) (*domain.PaymentPage, error) {
+				assert.Equal(t, domain.DefaultPageSize, filter.Limit)
+				assert.Equal(t, "user-123", filter.UserID)
+				assert.Equal(t, domain.StatusPending, filter.Status)
+				return expected, nil
+			}).Times(1)
+
+		page, err := service.List(ctx, domain.PaymentFilter{UserID: "user-123", Status: domain.StatusPending})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, page)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		tests := []domain.PaymentFilter{
+			{UserID: "user-123", Limit: domain.MaxPageSize + 1},
+			{UserID: "user-123", MinAmount: 100, MaxAmount: 10},
+			{UserID: "user-123", CreatedFrom: time.Now(), CreatedTo: time.Now().Add(-time.Hour)},
+			{UserID: "user-123", Status: "SETTLED"},
+			{UserID: "user-123", MinAmount: -1},
+		}
+
+		for _, filter := range tests {
+			page, err := service.List(ctx, filter)
+			assert.Nil(t, page)
+			assert.ErrorIs(t, err, domain.ErrInvalidPaymentFilter)
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().List(ctx, gomock.Any()).Return(nil, domain.ErrInvalidCursor).Times(1)
+
+		page, err := service.List(ctx, domain.PaymentFilter{UserID: "user-123", Cursor: "bogus"})
+		assert.Nil(t, page)
+		assert.Equal(t, domain.ErrInvalidCursor, err)
+	})
+
+	t.Run("error listing payments", func(t *testing.T) {
+		mockPaymentRepo.EXPECT().List(ctx, gomock.Any()).Return(nil, errors.New("database error")).Times(1)
+
+		page, err := service.List(ctx, domain.PaymentFilter{UserID: "user-123"})
+		assert.Nil(t, page)
+		assert.Equal(t, domain.ErrListPayments, err)
 	})
 }
 
@@ -214,14 +1752,14 @@ func BenchmarkService_Create(b *testing.B) {
 	mockDB := mocks.NewMockDatabase(ctrl)
 	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
 	mockBalanceService := mocks.NewMockBalanceService(ctrl)
-	mockPublisher := mocks.NewMockPublisher(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
 
 	service := &Service{
-		logger:           slog.Default(),
-		db:               mockDB,
-		paymentRepo:      mockPaymentRepo,
-		balanceService:   mockBalanceService,
-		publisherService: mockPublisher,
+		logger:         slog.Default(),
+		db:             mockDB,
+		paymentRepo:    mockPaymentRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
 	}
 
 	ctx := context.Background()
@@ -229,6 +1767,7 @@ func BenchmarkService_Create(b *testing.B) {
 		IdempotencyKey: "bench-key",
 		UserID:         "user-123",
 		Amount:         100.0,
+		Currency:       domain.CurrencyARS,
 		ServiceID:      "service-1",
 		ClientNumber:   "client-456",
 	}
@@ -240,13 +1779,13 @@ func BenchmarkService_Create(b *testing.B) {
 		},
 	).AnyTimes()
 
-	mockPaymentRepo.EXPECT().CheckIdempotency(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
-	mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockPaymentRepo.EXPECT().CheckIdempotency(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	mockBalanceService.EXPECT().ReserveFunds(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	mockPaymentRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
-	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes()
+	mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_ = service.Create(ctx, request)
+		_, _, _ = service.Create(ctx, request)
 	}
 }
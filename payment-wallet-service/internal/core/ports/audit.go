@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate mockgen -destination=../mocks/audit_ports_mock.go -package=mocks -source=audit.go
+
+type AuditRepository interface {
+	Append(ctx context.Context, tx pgx.Tx, entry domain.AuditEntry) error
+}
+
+type AuditService interface {
+	Append(ctx context.Context, tx pgx.Tx, operator domain.Operator, action, targetID, reason string, details any) error
+	Record(ctx context.Context, operator domain.Operator, action, targetID string, details any) error
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+//go:generate mockgen -destination=../mocks/auth_ports_mock.go -package=mocks -source=auth.go
+
+type TokenVerifier interface {
+	// Verify returns the subject of a valid bearer token.
+	Verify(ctx context.Context, token string) (string, error)
+}
+
+type OperatorVerifier interface {
+	// VerifyOperator returns the operator a valid admin token was issued to.
+	VerifyOperator(ctx context.Context, token string) (*domain.Operator, error)
+}
@@ -10,13 +10,25 @@ import (
 //go:generate mockgen -destination=../mocks/balance_ports_mock.go -package=mocks -source=balance.go
 
 type BalanceRepository interface {
-	Get(ctx context.Context, userID string) (*domain.Balance, error)
-	ReserveFunds(ctx context.Context, tx pgx.Tx, userID string, amount int64) error
-	ReleaseFunds(ctx context.Context, userID string, amount int64) error
-	ConfirmReserve(ctx context.Context, userID string, amount int64) error
+	Create(ctx context.Context, tx pgx.Tx, balance domain.Balance) error
+	Get(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error)
+	ReadByUserID(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.Balance, error)
+	FindByUserID(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.Balance, error)
+	FindAllByUserID(ctx context.Context, tx pgx.Tx, userID string) ([]domain.Balance, error)
+	UpdateStatus(ctx context.Context, tx pgx.Tx, userID string, status domain.WalletStatus) error
+	ReserveFunds(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error
+	ReleaseFunds(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error
+	ConfirmReserve(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error
+	Credit(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error
+	Debit(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error
 }
 
 type BalanceService interface {
-	ReserveFunds(ctx context.Context, tx pgx.Tx, userID string, amount int64) error
-	Update(ctx context.Context, userID string, amount int64) error
+	Get(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error)
+	ReserveFunds(ctx context.Context, tx pgx.Tx, userID, paymentID string, amount domain.Money) error
+	Update(ctx context.Context, tx pgx.Tx, userID, paymentID string, amount domain.Money, approved bool) error
+	Credit(ctx context.Context, tx pgx.Tx, userID, referenceID, movement string, amount domain.Money) error
+	Debit(ctx context.Context, tx pgx.Tx, userID, referenceID, movement string, amount domain.Money) error
+	Transfer(ctx context.Context, tx pgx.Tx, fromUserID, toUserID, transferID string, amount domain.Money) error
+	Statement(ctx context.Context, userID string, currency domain.Currency, limit int) (*domain.Statement, error)
 }
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+//go:generate mockgen -destination=../mocks/billers_ports_mock.go -package=mocks -source=billers.go
+
+type BillerRepository interface {
+	Get(ctx context.Context, billerID string) (*domain.Biller, error)
+	ListEnabled(ctx context.Context) ([]domain.Biller, error)
+}
+
+type BillerService interface {
+	Check(ctx context.Context, request domain.CreatePaymentRequest) error
+	List(ctx context.Context) ([]domain.Biller, error)
+}
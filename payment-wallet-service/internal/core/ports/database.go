@@ -10,4 +10,5 @@ import (
 
 type Database interface {
 	WithTx(ctx context.Context, fn func(*pgx.Tx) error) error
+	WithSnapshot(ctx context.Context, fn func(*pgx.Tx) error) error
 }
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+//go:generate mockgen -destination=../mocks/fx_ports_mock.go -package=mocks -source=fx.go
+
+// FXRateProvider returns the mid-market rate between two currencies, in major
+// units of to per major unit of from. Unknown pairs fail with
+// domain.ErrFXRateUnavailable.
+type FXRateProvider interface {
+	Rate(ctx context.Context, from, to domain.Currency) (*big.Rat, error)
+}
+
+type FXService interface {
+	Quote(ctx context.Context, amount domain.Money, to domain.Currency) (*domain.FXQuote, error)
+}
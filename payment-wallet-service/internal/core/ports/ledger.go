@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate mockgen -destination=../mocks/ledger_ports_mock.go -package=mocks -source=ledger.go
+
+type LedgerRepository interface {
+	Post(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error
+	GetEntries(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency, limit int) ([]domain.LedgerEntry, error)
+	GetBalance(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.Balance, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate mockgen -destination=../mocks/limits_ports_mock.go -package=mocks -source=limits.go
+
+type LimitRepository interface {
+	FindOverride(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.LimitOverride, error)
+	Usage(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency, windows domain.LimitWindows) (*domain.LimitUsage, error)
+}
+
+type LimitService interface {
+	Check(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+//go:generate mockgen -destination=../mocks/metrics_ports_mock.go -package=mocks -source=metrics.go
+
+// PaymentMetrics records the business metrics of payments. Recording is best
+// effort and never fails the operation being measured.
+type PaymentMetrics interface {
+	// PaymentCreated counts a new payment in its initial status and observes
+	// its amount.
+	PaymentCreated(payment domain.Payment)
+	// PaymentRefused counts a payment request refused before the payment was
+	// created, by one of the domain.Refusal reasons.
+	PaymentRefused(reason string)
+	// PaymentStatusChanged counts a payment reaching its current status.
+	PaymentStatusChanged(payment domain.Payment)
+}
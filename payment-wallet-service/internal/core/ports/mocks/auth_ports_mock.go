@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auth.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/auth_ports_mock.go -package=mocks -source=auth.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTokenVerifier is a mock of TokenVerifier interface.
+type MockTokenVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenVerifierMockRecorder is the mock recorder for MockTokenVerifier.
+type MockTokenVerifierMockRecorder struct {
+	mock *MockTokenVerifier
+}
+
+// NewMockTokenVerifier creates a new mock instance.
+func NewMockTokenVerifier(ctrl *gomock.Controller) *MockTokenVerifier {
+	mock := &MockTokenVerifier{ctrl: ctrl}
+	mock.recorder = &MockTokenVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenVerifier) EXPECT() *MockTokenVerifierMockRecorder {
+	return m.recorder
+}
+
+// Verify mocks base method.
+func (m *MockTokenVerifier) Verify(ctx context.Context, token string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, token)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockTokenVerifierMockRecorder) Verify(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockTokenVerifier)(nil).Verify), ctx, token)
+}
+
+// MockOperatorVerifier is a mock of OperatorVerifier interface.
+type MockOperatorVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockOperatorVerifierMockRecorder
+	isgomock struct{}
+}
+
+// MockOperatorVerifierMockRecorder is the mock recorder for MockOperatorVerifier.
+type MockOperatorVerifierMockRecorder struct {
+	mock *MockOperatorVerifier
+}
+
+// NewMockOperatorVerifier creates a new mock instance.
+func NewMockOperatorVerifier(ctrl *gomock.Controller) *MockOperatorVerifier {
+	mock := &MockOperatorVerifier{ctrl: ctrl}
+	mock.recorder = &MockOperatorVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOperatorVerifier) EXPECT() *MockOperatorVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyOperator mocks base method.
+func (m *MockOperatorVerifier) VerifyOperator(ctx context.Context, token string) (*domain.Operator, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyOperator", ctx, token)
+	ret0, _ := ret[0].(*domain.Operator)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyOperator indicates an expected call of VerifyOperator.
+func (mr *MockOperatorVerifierMockRecorder) VerifyOperator(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyOperator", reflect.TypeOf((*MockOperatorVerifier)(nil).VerifyOperator), ctx, token)
+}
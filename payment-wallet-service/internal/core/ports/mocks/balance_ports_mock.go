@@ -43,50 +43,137 @@ func (m *MockBalanceRepository) EXPECT() *MockBalanceRepositoryMockRecorder {
 }
 
 // ConfirmReserve mocks base method.
-func (m *MockBalanceRepository) ConfirmReserve(ctx context.Context, userID string, amount int64) error {
+func (m *MockBalanceRepository) ConfirmReserve(ctx context.Context, tx v5.Tx, userID string, amount domain.Money) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ConfirmReserve", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "ConfirmReserve", ctx, tx, userID, amount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ConfirmReserve indicates an expected call of ConfirmReserve.
-func (mr *MockBalanceRepositoryMockRecorder) ConfirmReserve(ctx, userID, amount any) *gomock.Call {
+func (mr *MockBalanceRepositoryMockRecorder) ConfirmReserve(ctx, tx, userID, amount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReserve", reflect.TypeOf((*MockBalanceRepository)(nil).ConfirmReserve), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmReserve", reflect.TypeOf((*MockBalanceRepository)(nil).ConfirmReserve), ctx, tx, userID, amount)
+}
+
+// Create mocks base method.
+func (m *MockBalanceRepository) Create(ctx context.Context, tx v5.Tx, balance domain.Balance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tx, balance)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockBalanceRepositoryMockRecorder) Create(ctx, tx, balance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBalanceRepository)(nil).Create), ctx, tx, balance)
+}
+
+// Credit mocks base method.
+func (m *MockBalanceRepository) Credit(ctx context.Context, tx v5.Tx, userID string, amount domain.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credit", ctx, tx, userID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockBalanceRepositoryMockRecorder) Credit(ctx, tx, userID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockBalanceRepository)(nil).Credit), ctx, tx, userID, amount)
+}
+
+// Debit mocks base method.
+func (m *MockBalanceRepository) Debit(ctx context.Context, tx v5.Tx, userID string, amount domain.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Debit", ctx, tx, userID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Debit indicates an expected call of Debit.
+func (mr *MockBalanceRepositoryMockRecorder) Debit(ctx, tx, userID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debit", reflect.TypeOf((*MockBalanceRepository)(nil).Debit), ctx, tx, userID, amount)
+}
+
+// FindAllByUserID mocks base method.
+func (m *MockBalanceRepository) FindAllByUserID(ctx context.Context, tx v5.Tx, userID string) ([]domain.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAllByUserID", ctx, tx, userID)
+	ret0, _ := ret[0].([]domain.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAllByUserID indicates an expected call of FindAllByUserID.
+func (mr *MockBalanceRepositoryMockRecorder) FindAllByUserID(ctx, tx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAllByUserID", reflect.TypeOf((*MockBalanceRepository)(nil).FindAllByUserID), ctx, tx, userID)
+}
+
+// FindByUserID mocks base method.
+func (m *MockBalanceRepository) FindByUserID(ctx context.Context, tx v5.Tx, userID string, currency domain.Currency) (*domain.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUserID", ctx, tx, userID, currency)
+	ret0, _ := ret[0].(*domain.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUserID indicates an expected call of FindByUserID.
+func (mr *MockBalanceRepositoryMockRecorder) FindByUserID(ctx, tx, userID, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUserID", reflect.TypeOf((*MockBalanceRepository)(nil).FindByUserID), ctx, tx, userID, currency)
 }
 
 // Get mocks base method.
-func (m *MockBalanceRepository) Get(ctx context.Context, userID string) (*domain.Balance, error) {
+func (m *MockBalanceRepository) Get(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret := m.ctrl.Call(m, "Get", ctx, userID, currency)
 	ret0, _ := ret[0].(*domain.Balance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockBalanceRepositoryMockRecorder) Get(ctx, userID any) *gomock.Call {
+func (mr *MockBalanceRepositoryMockRecorder) Get(ctx, userID, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBalanceRepository)(nil).Get), ctx, userID, currency)
+}
+
+// ReadByUserID mocks base method.
+func (m *MockBalanceRepository) ReadByUserID(ctx context.Context, tx v5.Tx, userID string, currency domain.Currency) (*domain.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadByUserID", ctx, tx, userID, currency)
+	ret0, _ := ret[0].(*domain.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadByUserID indicates an expected call of ReadByUserID.
+func (mr *MockBalanceRepositoryMockRecorder) ReadByUserID(ctx, tx, userID, currency any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBalanceRepository)(nil).Get), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadByUserID", reflect.TypeOf((*MockBalanceRepository)(nil).ReadByUserID), ctx, tx, userID, currency)
 }
 
 // ReleaseFunds mocks base method.
-func (m *MockBalanceRepository) ReleaseFunds(ctx context.Context, userID string, amount int64) error {
+func (m *MockBalanceRepository) ReleaseFunds(ctx context.Context, tx v5.Tx, userID string, amount domain.Money) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReleaseFunds", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "ReleaseFunds", ctx, tx, userID, amount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ReleaseFunds indicates an expected call of ReleaseFunds.
-func (mr *MockBalanceRepositoryMockRecorder) ReleaseFunds(ctx, userID, amount any) *gomock.Call {
+func (mr *MockBalanceRepositoryMockRecorder) ReleaseFunds(ctx, tx, userID, amount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseFunds", reflect.TypeOf((*MockBalanceRepository)(nil).ReleaseFunds), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseFunds", reflect.TypeOf((*MockBalanceRepository)(nil).ReleaseFunds), ctx, tx, userID, amount)
 }
 
 // ReserveFunds mocks base method.
-func (m *MockBalanceRepository) ReserveFunds(ctx context.Context, tx v5.Tx, userID string, amount int64) error {
+func (m *MockBalanceRepository) ReserveFunds(ctx context.Context, tx v5.Tx, userID string, amount domain.Money) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ReserveFunds", ctx, tx, userID, amount)
 	ret0, _ := ret[0].(error)
@@ -99,6 +186,20 @@ func (mr *MockBalanceRepositoryMockRecorder) ReserveFunds(ctx, tx, userID, amoun
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveFunds", reflect.TypeOf((*MockBalanceRepository)(nil).ReserveFunds), ctx, tx, userID, amount)
 }
 
+// UpdateStatus mocks base method.
+func (m *MockBalanceRepository) UpdateStatus(ctx context.Context, tx v5.Tx, userID string, status domain.WalletStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, tx, userID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockBalanceRepositoryMockRecorder) UpdateStatus(ctx, tx, userID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockBalanceRepository)(nil).UpdateStatus), ctx, tx, userID, status)
+}
+
 // MockBalanceService is a mock of BalanceService interface.
 type MockBalanceService struct {
 	ctrl     *gomock.Controller
@@ -123,30 +224,102 @@ func (m *MockBalanceService) EXPECT() *MockBalanceServiceMockRecorder {
 	return m.recorder
 }
 
+// Credit mocks base method.
+func (m *MockBalanceService) Credit(ctx context.Context, tx v5.Tx, userID, referenceID, movement string, amount domain.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credit", ctx, tx, userID, referenceID, movement, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockBalanceServiceMockRecorder) Credit(ctx, tx, userID, referenceID, movement, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockBalanceService)(nil).Credit), ctx, tx, userID, referenceID, movement, amount)
+}
+
+// Debit mocks base method.
+func (m *MockBalanceService) Debit(ctx context.Context, tx v5.Tx, userID, referenceID, movement string, amount domain.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Debit", ctx, tx, userID, referenceID, movement, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Debit indicates an expected call of Debit.
+func (mr *MockBalanceServiceMockRecorder) Debit(ctx, tx, userID, referenceID, movement, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debit", reflect.TypeOf((*MockBalanceService)(nil).Debit), ctx, tx, userID, referenceID, movement, amount)
+}
+
+// Get mocks base method.
+func (m *MockBalanceService) Get(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, currency)
+	ret0, _ := ret[0].(*domain.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBalanceServiceMockRecorder) Get(ctx, userID, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBalanceService)(nil).Get), ctx, userID, currency)
+}
+
 // ReserveFunds mocks base method.
-func (m *MockBalanceService) ReserveFunds(ctx context.Context, tx v5.Tx, userID string, amount int64) error {
+func (m *MockBalanceService) ReserveFunds(ctx context.Context, tx v5.Tx, userID, paymentID string, amount domain.Money) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ReserveFunds", ctx, tx, userID, amount)
+	ret := m.ctrl.Call(m, "ReserveFunds", ctx, tx, userID, paymentID, amount)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ReserveFunds indicates an expected call of ReserveFunds.
-func (mr *MockBalanceServiceMockRecorder) ReserveFunds(ctx, tx, userID, amount any) *gomock.Call {
+func (mr *MockBalanceServiceMockRecorder) ReserveFunds(ctx, tx, userID, paymentID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveFunds", reflect.TypeOf((*MockBalanceService)(nil).ReserveFunds), ctx, tx, userID, paymentID, amount)
+}
+
+// Statement mocks base method.
+func (m *MockBalanceService) Statement(ctx context.Context, userID string, currency domain.Currency, limit int) (*domain.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statement", ctx, userID, currency, limit)
+	ret0, _ := ret[0].(*domain.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Statement indicates an expected call of Statement.
+func (mr *MockBalanceServiceMockRecorder) Statement(ctx, userID, currency, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statement", reflect.TypeOf((*MockBalanceService)(nil).Statement), ctx, userID, currency, limit)
+}
+
+// Transfer mocks base method.
+func (m *MockBalanceService) Transfer(ctx context.Context, tx v5.Tx, fromUserID, toUserID, transferID string, amount domain.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transfer", ctx, tx, fromUserID, toUserID, transferID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transfer indicates an expected call of Transfer.
+func (mr *MockBalanceServiceMockRecorder) Transfer(ctx, tx, fromUserID, toUserID, transferID, amount any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveFunds", reflect.TypeOf((*MockBalanceService)(nil).ReserveFunds), ctx, tx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockBalanceService)(nil).Transfer), ctx, tx, fromUserID, toUserID, transferID, amount)
 }
 
 // Update mocks base method.
-func (m *MockBalanceService) Update(ctx context.Context, userID string, amount int64) error {
+func (m *MockBalanceService) Update(ctx context.Context, tx v5.Tx, userID, paymentID string, amount domain.Money, approved bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, userID, amount)
+	ret := m.ctrl.Call(m, "Update", ctx, tx, userID, paymentID, amount, approved)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockBalanceServiceMockRecorder) Update(ctx, userID, amount any) *gomock.Call {
+func (mr *MockBalanceServiceMockRecorder) Update(ctx, tx, userID, paymentID, amount, approved any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBalanceService)(nil).Update), ctx, userID, amount)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockBalanceService)(nil).Update), ctx, tx, userID, paymentID, amount, approved)
 }
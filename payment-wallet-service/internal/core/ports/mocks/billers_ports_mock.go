@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: billers.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/billers_ports_mock.go -package=mocks -source=billers.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBillerRepository is a mock of BillerRepository interface.
+type MockBillerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBillerRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBillerRepositoryMockRecorder is the mock recorder for MockBillerRepository.
+type MockBillerRepositoryMockRecorder struct {
+	mock *MockBillerRepository
+}
+
+// NewMockBillerRepository creates a new mock instance.
+func NewMockBillerRepository(ctrl *gomock.Controller) *MockBillerRepository {
+	mock := &MockBillerRepository{ctrl: ctrl}
+	mock.recorder = &MockBillerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBillerRepository) EXPECT() *MockBillerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockBillerRepository) Get(ctx context.Context, billerID string) (*domain.Biller, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, billerID)
+	ret0, _ := ret[0].(*domain.Biller)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockBillerRepositoryMockRecorder) Get(ctx, billerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBillerRepository)(nil).Get), ctx, billerID)
+}
+
+// ListEnabled mocks base method.
+func (m *MockBillerRepository) ListEnabled(ctx context.Context) ([]domain.Biller, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEnabled", ctx)
+	ret0, _ := ret[0].([]domain.Biller)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEnabled indicates an expected call of ListEnabled.
+func (mr *MockBillerRepositoryMockRecorder) ListEnabled(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEnabled", reflect.TypeOf((*MockBillerRepository)(nil).ListEnabled), ctx)
+}
+
+// MockBillerService is a mock of BillerService interface.
+type MockBillerService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBillerServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockBillerServiceMockRecorder is the mock recorder for MockBillerService.
+type MockBillerServiceMockRecorder struct {
+	mock *MockBillerService
+}
+
+// NewMockBillerService creates a new mock instance.
+func NewMockBillerService(ctrl *gomock.Controller) *MockBillerService {
+	mock := &MockBillerService{ctrl: ctrl}
+	mock.recorder = &MockBillerServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBillerService) EXPECT() *MockBillerServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockBillerService) Check(ctx context.Context, request domain.CreatePaymentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, request)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockBillerServiceMockRecorder) Check(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockBillerService)(nil).Check), ctx, request)
+}
+
+// List mocks base method.
+func (m *MockBillerService) List(ctx context.Context) ([]domain.Biller, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.Biller)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockBillerServiceMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockBillerService)(nil).List), ctx)
+}
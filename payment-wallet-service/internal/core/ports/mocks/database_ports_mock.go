@@ -41,6 +41,20 @@ func (m *MockDatabase) EXPECT() *MockDatabaseMockRecorder {
 	return m.recorder
 }
 
+// WithSnapshot mocks base method.
+func (m *MockDatabase) WithSnapshot(ctx context.Context, fn func(*v5.Tx) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithSnapshot", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithSnapshot indicates an expected call of WithSnapshot.
+func (mr *MockDatabaseMockRecorder) WithSnapshot(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithSnapshot", reflect.TypeOf((*MockDatabase)(nil).WithSnapshot), ctx, fn)
+}
+
 // WithTx mocks base method.
 func (m *MockDatabase) WithTx(ctx context.Context, fn func(*v5.Tx) error) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: fx.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/fx_ports_mock.go -package=mocks -source=fx.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	big "math/big"
+	reflect "reflect"
+
+	domain "github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFXRateProvider is a mock of FXRateProvider interface.
+type MockFXRateProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockFXRateProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockFXRateProviderMockRecorder is the mock recorder for MockFXRateProvider.
+type MockFXRateProviderMockRecorder struct {
+	mock *MockFXRateProvider
+}
+
+// NewMockFXRateProvider creates a new mock instance.
+func NewMockFXRateProvider(ctrl *gomock.Controller) *MockFXRateProvider {
+	mock := &MockFXRateProvider{ctrl: ctrl}
+	mock.recorder = &MockFXRateProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFXRateProvider) EXPECT() *MockFXRateProviderMockRecorder {
+	return m.recorder
+}
+
+// Rate mocks base method.
+func (m *MockFXRateProvider) Rate(ctx context.Context, from, to domain.Currency) (*big.Rat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rate", ctx, from, to)
+	ret0, _ := ret[0].(*big.Rat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rate indicates an expected call of Rate.
+func (mr *MockFXRateProviderMockRecorder) Rate(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rate", reflect.TypeOf((*MockFXRateProvider)(nil).Rate), ctx, from, to)
+}
+
+// MockFXService is a mock of FXService interface.
+type MockFXService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFXServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockFXServiceMockRecorder is the mock recorder for MockFXService.
+type MockFXServiceMockRecorder struct {
+	mock *MockFXService
+}
+
+// NewMockFXService creates a new mock instance.
+func NewMockFXService(ctrl *gomock.Controller) *MockFXService {
+	mock := &MockFXService{ctrl: ctrl}
+	mock.recorder = &MockFXServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFXService) EXPECT() *MockFXServiceMockRecorder {
+	return m.recorder
+}
+
+// Quote mocks base method.
+func (m *MockFXService) Quote(ctx context.Context, amount domain.Money, to domain.Currency) (*domain.FXQuote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Quote", ctx, amount, to)
+	ret0, _ := ret[0].(*domain.FXQuote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Quote indicates an expected call of Quote.
+func (mr *MockFXServiceMockRecorder) Quote(ctx, amount, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Quote", reflect.TypeOf((*MockFXService)(nil).Quote), ctx, amount, to)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: limits.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/limits_ports_mock.go -package=mocks -source=limits.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	v5 "github.com/jackc/pgx/v5"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLimitRepository is a mock of LimitRepository interface.
+type MockLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLimitRepositoryMockRecorder is the mock recorder for MockLimitRepository.
+type MockLimitRepositoryMockRecorder struct {
+	mock *MockLimitRepository
+}
+
+// NewMockLimitRepository creates a new mock instance.
+func NewMockLimitRepository(ctrl *gomock.Controller) *MockLimitRepository {
+	mock := &MockLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimitRepository) EXPECT() *MockLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// FindOverride mocks base method.
+func (m *MockLimitRepository) FindOverride(ctx context.Context, tx v5.Tx, userID string, currency domain.Currency) (*domain.LimitOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOverride", ctx, tx, userID, currency)
+	ret0, _ := ret[0].(*domain.LimitOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOverride indicates an expected call of FindOverride.
+func (mr *MockLimitRepositoryMockRecorder) FindOverride(ctx, tx, userID, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOverride", reflect.TypeOf((*MockLimitRepository)(nil).FindOverride), ctx, tx, userID, currency)
+}
+
+// Usage mocks base method.
+func (m *MockLimitRepository) Usage(ctx context.Context, tx v5.Tx, userID string, currency domain.Currency, windows domain.LimitWindows) (*domain.LimitUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, tx, userID, currency, windows)
+	ret0, _ := ret[0].(*domain.LimitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockLimitRepositoryMockRecorder) Usage(ctx, tx, userID, currency, windows any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockLimitRepository)(nil).Usage), ctx, tx, userID, currency, windows)
+}
+
+// MockLimitService is a mock of LimitService interface.
+type MockLimitService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockLimitServiceMockRecorder is the mock recorder for MockLimitService.
+type MockLimitServiceMockRecorder struct {
+	mock *MockLimitService
+}
+
+// NewMockLimitService creates a new mock instance.
+func NewMockLimitService(ctrl *gomock.Controller) *MockLimitService {
+	mock := &MockLimitService{ctrl: ctrl}
+	mock.recorder = &MockLimitServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLimitService) EXPECT() *MockLimitServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLimitService) Check(ctx context.Context, tx v5.Tx, userID string, amount domain.Money) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, tx, userID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLimitServiceMockRecorder) Check(ctx, tx, userID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLimitService)(nil).Check), ctx, tx, userID, amount)
+}
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: metrics.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/metrics_ports_mock.go -package=mocks -source=metrics.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPaymentMetrics is a mock of PaymentMetrics interface.
+type MockPaymentMetrics struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentMetricsMockRecorder
+	isgomock struct{}
+}
+
+// MockPaymentMetricsMockRecorder is the mock recorder for MockPaymentMetrics.
+type MockPaymentMetricsMockRecorder struct {
+	mock *MockPaymentMetrics
+}
+
+// NewMockPaymentMetrics creates a new mock instance.
+func NewMockPaymentMetrics(ctrl *gomock.Controller) *MockPaymentMetrics {
+	mock := &MockPaymentMetrics{ctrl: ctrl}
+	mock.recorder = &MockPaymentMetricsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentMetrics) EXPECT() *MockPaymentMetricsMockRecorder {
+	return m.recorder
+}
+
+// PaymentCreated mocks base method.
+func (m *MockPaymentMetrics) PaymentCreated(payment domain.Payment) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PaymentCreated", payment)
+}
+
+// PaymentCreated indicates an expected call of PaymentCreated.
+func (mr *MockPaymentMetricsMockRecorder) PaymentCreated(payment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PaymentCreated", reflect.TypeOf((*MockPaymentMetrics)(nil).PaymentCreated), payment)
+}
+
+// PaymentRefused mocks base method.
+func (m *MockPaymentMetrics) PaymentRefused(reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PaymentRefused", reason)
+}
+
+// PaymentRefused indicates an expected call of PaymentRefused.
+func (mr *MockPaymentMetricsMockRecorder) PaymentRefused(reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PaymentRefused", reflect.TypeOf((*MockPaymentMetrics)(nil).PaymentRefused), reason)
+}
+
+// PaymentStatusChanged mocks base method.
+func (m *MockPaymentMetrics) PaymentStatusChanged(payment domain.Payment) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PaymentStatusChanged", payment)
+}
+
+// PaymentStatusChanged indicates an expected call of PaymentStatusChanged.
+func (mr *MockPaymentMetricsMockRecorder) PaymentStatusChanged(payment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PaymentStatusChanged", reflect.TypeOf((*MockPaymentMetrics)(nil).PaymentStatusChanged), payment)
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
 	v5 "github.com/jackc/pgx/v5"
@@ -43,10 +44,10 @@ func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
 }
 
 // CheckIdempotency mocks base method.
-func (m *MockPaymentRepository) CheckIdempotency(ctx context.Context, tx v5.Tx, idempotencyKey string) (bool, error) {
+func (m *MockPaymentRepository) CheckIdempotency(ctx context.Context, tx v5.Tx, idempotencyKey string) (*domain.Payment, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CheckIdempotency", ctx, tx, idempotencyKey)
-	ret0, _ := ret[0].(bool)
+	ret0, _ := ret[0].(*domain.Payment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -71,18 +72,107 @@ func (mr *MockPaymentRepositoryMockRecorder) Create(ctx, tx, payment any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentRepository)(nil).Create), ctx, tx, payment)
 }
 
+// FindByID mocks base method.
+func (m *MockPaymentRepository) FindByID(ctx context.Context, tx v5.Tx, paymentID string) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, tx, paymentID)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockPaymentRepositoryMockRecorder) FindByID(ctx, tx, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPaymentRepository)(nil).FindByID), ctx, tx, paymentID)
+}
+
+// FindExpired mocks base method.
+func (m *MockPaymentRepository) FindExpired(ctx context.Context, tx v5.Tx, pendingBefore time.Time) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExpired", ctx, tx, pendingBefore)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExpired indicates an expected call of FindExpired.
+func (mr *MockPaymentRepositoryMockRecorder) FindExpired(ctx, tx, pendingBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExpired", reflect.TypeOf((*MockPaymentRepository)(nil).FindExpired), ctx, tx, pendingBefore)
+}
+
+// Get mocks base method.
+func (m *MockPaymentRepository) Get(ctx context.Context, userID, paymentID string) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, paymentID)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPaymentRepositoryMockRecorder) Get(ctx, userID, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPaymentRepository)(nil).Get), ctx, userID, paymentID)
+}
+
+// History mocks base method.
+func (m *MockPaymentRepository) History(ctx context.Context, paymentID string) ([]domain.PaymentStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, paymentID)
+	ret0, _ := ret[0].([]domain.PaymentStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockPaymentRepositoryMockRecorder) History(ctx, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockPaymentRepository)(nil).History), ctx, paymentID)
+}
+
+// List mocks base method.
+func (m *MockPaymentRepository) List(ctx context.Context, filter domain.PaymentFilter) (*domain.PaymentPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].(*domain.PaymentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPaymentRepositoryMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPaymentRepository)(nil).List), ctx, filter)
+}
+
+// PostponeExpiry mocks base method.
+func (m *MockPaymentRepository) PostponeExpiry(ctx context.Context, tx v5.Tx, paymentID string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PostponeExpiry", ctx, tx, paymentID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PostponeExpiry indicates an expected call of PostponeExpiry.
+func (mr *MockPaymentRepositoryMockRecorder) PostponeExpiry(ctx, tx, paymentID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostponeExpiry", reflect.TypeOf((*MockPaymentRepository)(nil).PostponeExpiry), ctx, tx, paymentID, at)
+}
+
 // Update mocks base method.
-func (m *MockPaymentRepository) Update(ctx context.Context, payment domain.Payment) error {
+func (m *MockPaymentRepository) Update(ctx context.Context, tx v5.Tx, payment domain.Payment, from domain.PaymentStatus) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, payment)
+	ret := m.ctrl.Call(m, "Update", ctx, tx, payment, from)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockPaymentRepositoryMockRecorder) Update(ctx, payment any) *gomock.Call {
+func (mr *MockPaymentRepositoryMockRecorder) Update(ctx, tx, payment, from any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPaymentRepository)(nil).Update), ctx, payment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPaymentRepository)(nil).Update), ctx, tx, payment, from)
 }
 
 // MockPaymentService is a mock of PaymentService interface.
@@ -109,12 +199,44 @@ func (m *MockPaymentService) EXPECT() *MockPaymentServiceMockRecorder {
 	return m.recorder
 }
 
+// Approve mocks base method.
+func (m *MockPaymentService) Approve(ctx context.Context, operator domain.Operator, paymentID string) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, operator, paymentID)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockPaymentServiceMockRecorder) Approve(ctx, operator, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockPaymentService)(nil).Approve), ctx, operator, paymentID)
+}
+
+// Cancel mocks base method.
+func (m *MockPaymentService) Cancel(ctx context.Context, userID, paymentID string) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, userID, paymentID)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockPaymentServiceMockRecorder) Cancel(ctx, userID, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockPaymentService)(nil).Cancel), ctx, userID, paymentID)
+}
+
 // Create mocks base method.
-func (m *MockPaymentService) Create(ctx context.Context, request domain.CreatePaymentRequest) error {
+func (m *MockPaymentService) Create(ctx context.Context, request domain.CreatePaymentRequest) (*domain.Payment, bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, request)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Create indicates an expected call of Create.
@@ -123,16 +245,91 @@ func (mr *MockPaymentServiceMockRecorder) Create(ctx, request any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentService)(nil).Create), ctx, request)
 }
 
+// ForceStatus mocks base method.
+func (m *MockPaymentService) ForceStatus(ctx context.Context, operator domain.Operator, request domain.ForcePaymentStatusRequest) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForceStatus", ctx, operator, request)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForceStatus indicates an expected call of ForceStatus.
+func (mr *MockPaymentServiceMockRecorder) ForceStatus(ctx, operator, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForceStatus", reflect.TypeOf((*MockPaymentService)(nil).ForceStatus), ctx, operator, request)
+}
+
+// Get mocks base method.
+func (m *MockPaymentService) Get(ctx context.Context, userID, paymentID string) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, paymentID)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPaymentServiceMockRecorder) Get(ctx, userID, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPaymentService)(nil).Get), ctx, userID, paymentID)
+}
+
+// History mocks base method.
+func (m *MockPaymentService) History(ctx context.Context, userID, paymentID string) ([]domain.PaymentStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, userID, paymentID)
+	ret0, _ := ret[0].([]domain.PaymentStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockPaymentServiceMockRecorder) History(ctx, userID, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockPaymentService)(nil).History), ctx, userID, paymentID)
+}
+
+// List mocks base method.
+func (m *MockPaymentService) List(ctx context.Context, filter domain.PaymentFilter) (*domain.PaymentPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, filter)
+	ret0, _ := ret[0].(*domain.PaymentPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockPaymentServiceMockRecorder) List(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPaymentService)(nil).List), ctx, filter)
+}
+
+// Reject mocks base method.
+func (m *MockPaymentService) Reject(ctx context.Context, operator domain.Operator, paymentID string) (*domain.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, operator, paymentID)
+	ret0, _ := ret[0].(*domain.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockPaymentServiceMockRecorder) Reject(ctx, operator, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockPaymentService)(nil).Reject), ctx, operator, paymentID)
+}
+
 // Update mocks base method.
-func (m *MockPaymentService) Update(ctx context.Context, paymentID, status string) error {
+func (m *MockPaymentService) Update(ctx context.Context, event domain.PaymentResultEvent) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, paymentID, status)
+	ret := m.ctrl.Call(m, "Update", ctx, event)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockPaymentServiceMockRecorder) Update(ctx, paymentID, status any) *gomock.Call {
+func (mr *MockPaymentServiceMockRecorder) Update(ctx, event any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPaymentService)(nil).Update), ctx, paymentID, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPaymentService)(nil).Update), ctx, event)
 }
@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: risk.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/risk_ports_mock.go -package=mocks -source=risk.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRiskEvaluator is a mock of RiskEvaluator interface.
+type MockRiskEvaluator struct {
+	ctrl     *gomock.Controller
+	recorder *MockRiskEvaluatorMockRecorder
+	isgomock struct{}
+}
+
+// MockRiskEvaluatorMockRecorder is the mock recorder for MockRiskEvaluator.
+type MockRiskEvaluatorMockRecorder struct {
+	mock *MockRiskEvaluator
+}
+
+// NewMockRiskEvaluator creates a new mock instance.
+func NewMockRiskEvaluator(ctrl *gomock.Controller) *MockRiskEvaluator {
+	mock := &MockRiskEvaluator{ctrl: ctrl}
+	mock.recorder = &MockRiskEvaluatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRiskEvaluator) EXPECT() *MockRiskEvaluatorMockRecorder {
+	return m.recorder
+}
+
+// Evaluate mocks base method.
+func (m *MockRiskEvaluator) Evaluate(ctx context.Context, input domain.RiskInput) (*domain.RiskAssessment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Evaluate", ctx, input)
+	ret0, _ := ret[0].(*domain.RiskAssessment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Evaluate indicates an expected call of Evaluate.
+func (mr *MockRiskEvaluatorMockRecorder) Evaluate(ctx, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Evaluate", reflect.TypeOf((*MockRiskEvaluator)(nil).Evaluate), ctx, input)
+}
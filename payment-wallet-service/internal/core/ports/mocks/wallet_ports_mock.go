@@ -0,0 +1,187 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: wallets.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=../mocks/wallet_ports_mock.go -package=mocks -source=wallets.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	v5 "github.com/jackc/pgx/v5"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTopUpRepository is a mock of TopUpRepository interface.
+type MockTopUpRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTopUpRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTopUpRepositoryMockRecorder is the mock recorder for MockTopUpRepository.
+type MockTopUpRepositoryMockRecorder struct {
+	mock *MockTopUpRepository
+}
+
+// NewMockTopUpRepository creates a new mock instance.
+func NewMockTopUpRepository(ctrl *gomock.Controller) *MockTopUpRepository {
+	mock := &MockTopUpRepository{ctrl: ctrl}
+	mock.recorder = &MockTopUpRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTopUpRepository) EXPECT() *MockTopUpRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CheckIdempotency mocks base method.
+func (m *MockTopUpRepository) CheckIdempotency(ctx context.Context, tx v5.Tx, idempotencyKey string) (*domain.TopUp, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckIdempotency", ctx, tx, idempotencyKey)
+	ret0, _ := ret[0].(*domain.TopUp)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckIdempotency indicates an expected call of CheckIdempotency.
+func (mr *MockTopUpRepositoryMockRecorder) CheckIdempotency(ctx, tx, idempotencyKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIdempotency", reflect.TypeOf((*MockTopUpRepository)(nil).CheckIdempotency), ctx, tx, idempotencyKey)
+}
+
+// Create mocks base method.
+func (m *MockTopUpRepository) Create(ctx context.Context, tx v5.Tx, topUp domain.TopUp) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, tx, topUp)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTopUpRepositoryMockRecorder) Create(ctx, tx, topUp any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTopUpRepository)(nil).Create), ctx, tx, topUp)
+}
+
+// MockWalletService is a mock of WalletService interface.
+type MockWalletService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWalletServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWalletServiceMockRecorder is the mock recorder for MockWalletService.
+type MockWalletServiceMockRecorder struct {
+	mock *MockWalletService
+}
+
+// NewMockWalletService creates a new mock instance.
+func NewMockWalletService(ctrl *gomock.Controller) *MockWalletService {
+	mock := &MockWalletService{ctrl: ctrl}
+	mock.recorder = &MockWalletServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWalletService) EXPECT() *MockWalletServiceMockRecorder {
+	return m.recorder
+}
+
+// Adjust mocks base method.
+func (m *MockWalletService) Adjust(ctx context.Context, operator domain.Operator, request domain.BalanceAdjustmentRequest) (*domain.BalanceAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Adjust", ctx, operator, request)
+	ret0, _ := ret[0].(*domain.BalanceAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Adjust indicates an expected call of Adjust.
+func (mr *MockWalletServiceMockRecorder) Adjust(ctx, operator, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Adjust", reflect.TypeOf((*MockWalletService)(nil).Adjust), ctx, operator, request)
+}
+
+// Close mocks base method.
+func (m *MockWalletService) Close(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx, operator, userID)
+	ret0, _ := ret[0].([]domain.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockWalletServiceMockRecorder) Close(ctx, operator, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockWalletService)(nil).Close), ctx, operator, userID)
+}
+
+// Create mocks base method.
+func (m *MockWalletService) Create(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, currency)
+	ret0, _ := ret[0].(*domain.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWalletServiceMockRecorder) Create(ctx, userID, currency any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWalletService)(nil).Create), ctx, userID, currency)
+}
+
+// Freeze mocks base method.
+func (m *MockWalletService) Freeze(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Freeze", ctx, operator, userID)
+	ret0, _ := ret[0].([]domain.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Freeze indicates an expected call of Freeze.
+func (mr *MockWalletServiceMockRecorder) Freeze(ctx, operator, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Freeze", reflect.TypeOf((*MockWalletService)(nil).Freeze), ctx, operator, userID)
+}
+
+// TopUp mocks base method.
+func (m *MockWalletService) TopUp(ctx context.Context, operator domain.Operator, request domain.CreateTopUpRequest) (*domain.TopUp, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TopUp", ctx, operator, request)
+	ret0, _ := ret[0].(*domain.TopUp)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// TopUp indicates an expected call of TopUp.
+func (mr *MockWalletServiceMockRecorder) TopUp(ctx, operator, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TopUp", reflect.TypeOf((*MockWalletService)(nil).TopUp), ctx, operator, request)
+}
+
+// Unfreeze mocks base method.
+func (m *MockWalletService) Unfreeze(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unfreeze", ctx, operator, userID)
+	ret0, _ := ret[0].([]domain.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unfreeze indicates an expected call of Unfreeze.
+func (mr *MockWalletServiceMockRecorder) Unfreeze(ctx, operator, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unfreeze", reflect.TypeOf((*MockWalletService)(nil).Unfreeze), ctx, operator, userID)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate mockgen -destination=../mocks/outbox_ports_mock.go -package=mocks -source=outbox.go
+
+type OutboxRepository interface {
+	Create(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error
+	ClaimPending(ctx context.Context, tx pgx.Tx, limit int, lease time.Duration) ([]domain.OutboxMessage, error)
+	MarkSent(ctx context.Context, tx pgx.Tx, messageID string) error
+	MarkFailed(ctx context.Context, tx pgx.Tx, messageID string, nextAttemptAt time.Time, reason string) error
+	Release(ctx context.Context, tx pgx.Tx, messageIDs []string) error
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
 	"github.com/jackc/pgx/v5"
@@ -10,12 +11,25 @@ import (
 //go:generate mockgen -destination=../mocks/payment_ports_mock.go -package=mocks -source=payments.go
 
 type PaymentRepository interface {
-	CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (bool, error)
+	CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.Payment, error)
 	Create(ctx context.Context, tx pgx.Tx, payment domain.Payment) error
-	Update(ctx context.Context, payment domain.Payment) error
+	FindByID(ctx context.Context, tx pgx.Tx, paymentID string) (*domain.Payment, error)
+	FindExpired(ctx context.Context, tx pgx.Tx, pendingBefore time.Time) (*domain.Payment, error)
+	PostponeExpiry(ctx context.Context, tx pgx.Tx, paymentID string, at time.Time) error
+	Update(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error
+	Get(ctx context.Context, userID, paymentID string) (*domain.Payment, error)
+	List(ctx context.Context, filter domain.PaymentFilter) (*domain.PaymentPage, error)
+	History(ctx context.Context, paymentID string) ([]domain.PaymentStatusChange, error)
 }
 
 type PaymentService interface {
-	Create(ctx context.Context, request domain.CreatePaymentRequest) error
-	Update(ctx context.Context, paymentID, status string) error
+	Create(ctx context.Context, request domain.CreatePaymentRequest) (*domain.Payment, bool, error)
+	Update(ctx context.Context, event domain.PaymentResultEvent) error
+	Cancel(ctx context.Context, userID, paymentID string) (*domain.Payment, error)
+	Get(ctx context.Context, userID, paymentID string) (*domain.Payment, error)
+	List(ctx context.Context, filter domain.PaymentFilter) (*domain.PaymentPage, error)
+	History(ctx context.Context, userID, paymentID string) ([]domain.PaymentStatusChange, error)
+	Approve(ctx context.Context, operator domain.Operator, paymentID string) (*domain.Payment, error)
+	Reject(ctx context.Context, operator domain.Operator, paymentID string) (*domain.Payment, error)
+	ForceStatus(ctx context.Context, operator domain.Operator, request domain.ForcePaymentStatusRequest) (*domain.Payment, error)
 }
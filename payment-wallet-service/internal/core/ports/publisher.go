@@ -9,5 +9,5 @@ import (
 //go:generate mockgen -destination=../mocks/publisher_ports_mock.go -package=mocks -source=publisher.go
 
 type Publisher interface {
-	Publish(ctx context.Context, event *domain.PaymentInitiatedEvent) error
+	Publish(ctx context.Context, message domain.OutboxMessage) error
 }
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate mockgen -destination=../mocks/refund_ports_mock.go -package=mocks -source=refunds.go
+
+type RefundRepository interface {
+	CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.Refund, error)
+	Create(ctx context.Context, tx pgx.Tx, refund domain.Refund) error
+}
+
+type RefundService interface {
+	Create(ctx context.Context, request domain.CreateRefundRequest) (*domain.Refund, bool, error)
+}
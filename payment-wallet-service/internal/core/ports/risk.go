@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+//go:generate mockgen -destination=../mocks/risk_ports_mock.go -package=mocks -source=risk.go
+
+type RiskEvaluator interface {
+	Evaluate(ctx context.Context, input domain.RiskInput) (*domain.RiskAssessment, error)
+}
@@ -6,4 +6,5 @@ import "context"
 
 type Subscriber interface {
 	Listen(ctx context.Context)
+	Close() error
 }
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate mockgen -destination=../mocks/transfer_ports_mock.go -package=mocks -source=transfers.go
+
+type TransferRepository interface {
+	CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.Transfer, error)
+	Create(ctx context.Context, tx pgx.Tx, transfer domain.Transfer) error
+}
+
+type TransferService interface {
+	Create(ctx context.Context, request domain.CreateTransferRequest) (*domain.Transfer, bool, error)
+}
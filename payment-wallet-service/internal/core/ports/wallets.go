@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/jackc/pgx/v5"
+)
+
+//go:generate mockgen -destination=../mocks/wallet_ports_mock.go -package=mocks -source=wallets.go
+
+type TopUpRepository interface {
+	CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.TopUp, error)
+	Create(ctx context.Context, tx pgx.Tx, topUp domain.TopUp) error
+}
+
+type WalletService interface {
+	Create(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error)
+	Freeze(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error)
+	Unfreeze(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error)
+	Close(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error)
+	Adjust(ctx context.Context, operator domain.Operator, request domain.BalanceAdjustmentRequest) (*domain.BalanceAdjustment, error)
+	TopUp(ctx context.Context, operator domain.Operator, request domain.CreateTopUpRequest) (*domain.TopUp, bool, error)
+}
@@ -0,0 +1,201 @@
+package refunds
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
+	"github.com/jackc/pgx/v5"
+)
+
+type ServiceConfig struct {
+	Logger            *slog.Logger
+	DB                ports.Database
+	RefundRepository  ports.RefundRepository
+	PaymentRepository ports.PaymentRepository
+	BalanceService    ports.BalanceService
+	OutboxRepository  ports.OutboxRepository
+	Metrics           ports.PaymentMetrics
+}
+
+type Service struct {
+	logger         *slog.Logger
+	db             ports.Database
+	refundRepo     ports.RefundRepository
+	paymentRepo    ports.PaymentRepository
+	balanceService ports.BalanceService
+	outboxRepo     ports.OutboxRepository
+	metrics        ports.PaymentMetrics
+}
+
+func NewRefundService(config ServiceConfig) *Service {
+	return &Service{
+		logger:         config.Logger,
+		db:             config.DB,
+		refundRepo:     config.RefundRepository,
+		paymentRepo:    config.PaymentRepository,
+		balanceService: config.BalanceService,
+		outboxRepo:     config.OutboxRepository,
+		metrics:        config.Metrics,
+	}
+}
+
+// Create refunds part or all of an approved payment, crediting the amount back
+// to the available balance of the user. The payment is locked for the whole
+// transaction, so concurrent refunds of the same payment can never add up to
+// more than its amount. Idempotency keys behave as in payment creation.
+func (s *Service) Create(ctx context.Context, request domain.CreateRefundRequest) (*domain.Refund, bool, error) {
+	ctx = correlation.WithPaymentID(ctx, request.PaymentID)
+
+	var (
+		refund   *domain.Refund
+		refunded *domain.Payment
+		replayed bool
+	)
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		payment, err := s.paymentRepo.FindByID(ctx, *tx, request.PaymentID)
+		if err != nil {
+			if errors.Is(err, domain.ErrPaymentNotFound) {
+				return domain.ErrPaymentNotFound
+			}
+
+			s.logger.ErrorContext(ctx, "failed to get payment",
+				slog.Any("error", err))
+
+			return domain.ErrGetPayment
+		}
+
+		if payment.UserID != request.UserID {
+			return domain.ErrPaymentNotFound
+		}
+
+		existing, err := s.refundRepo.CheckIdempotency(ctx, *tx, request.IdempotencyKey)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to check idempotency",
+				slog.Any("error", err),
+				slog.String("idempotency_key", request.IdempotencyKey))
+
+			return domain.ErrCheckIdempotency
+		}
+
+		if existing != nil {
+			if !existing.Matches(request) {
+				s.logger.WarnContext(ctx, "idempotency key reused with a different request",
+					slog.String("idempotency_key", request.IdempotencyKey),
+					slog.String("refund_id", existing.ID))
+
+				return domain.ErrIdempotencyKeyReused
+			}
+
+			refund = existing
+			replayed = true
+			return nil
+		}
+
+		refundable := payment.Refundable()
+		if refundable == 0 {
+			return domain.ErrPaymentNotRefundable
+		}
+		if request.Amount > refundable {
+			return domain.ErrRefundExceedsPayment
+		}
+
+		refund = &domain.Refund{
+			ID:             uidgen.NewUUID(),
+			PaymentID:      payment.ID,
+			UserID:         payment.UserID,
+			Amount:         request.Amount,
+			Currency:       payment.Currency,
+			IdempotencyKey: request.IdempotencyKey,
+			CreatedAt:      time.Now(),
+		}
+
+		errCreate := s.refundRepo.Create(ctx, *tx, *refund)
+		if errCreate != nil {
+			if errors.Is(errCreate, domain.ErrIdempotencyKeyConflict) {
+				return domain.ErrIdempotencyKeyConflict
+			}
+
+			s.logger.ErrorContext(ctx, "failed to create refund",
+				slog.Any("error", errCreate))
+
+			return domain.ErrCreateRefund
+		}
+
+		// the wallet gets back its share of the funded amount, at the rate
+		// locked when the payment was created
+		credit := payment.FundingRefund(refund.Amount)
+
+		from := payment.Status
+		payment.RefundedAmount += refund.Amount
+		next := domain.StatusPartiallyRefunded
+		if payment.RefundedAmount == payment.Amount {
+			next = domain.StatusRefunded
+		}
+		if err = payment.TransitionTo(next); err != nil {
+			return err
+		}
+
+		errUpdate := s.paymentRepo.Update(ctx, *tx, *payment, from)
+		if errUpdate != nil {
+			s.logger.ErrorContext(ctx, "failed to update refunded payment",
+				slog.Any("error", errUpdate))
+
+			return domain.ErrUpdatePayment
+		}
+
+		err = s.balanceService.Credit(ctx, *tx, payment.UserID, refund.ID, domain.MovementRefund, credit)
+		if err != nil {
+			return err
+		}
+
+		refundRequestedEvent := &domain.RefundRequestedEvent{
+			RefundID:      refund.ID,
+			TransactionID: payment.ID,
+			UserID:        payment.UserID,
+			Amount:        refund.Amount,
+			Currency:      refund.Currency,
+		}
+
+		message, errMessage := domain.NewOutboxMessage(uidgen.NewUUID(),
+			domain.EventTypeRefundRequested, payment.ID, refundRequestedEvent)
+		if errMessage != nil {
+			s.logger.ErrorContext(ctx, "failed to build outbox message",
+				slog.Any("error", errMessage),
+				slog.String("refund_id", refund.ID))
+
+			return domain.ErrCreateOutboxMessage
+		}
+
+		errOutbox := s.outboxRepo.Create(ctx, *tx, message)
+		if errOutbox != nil {
+			s.logger.ErrorContext(ctx, "failed to create outbox message",
+				slog.Any("error", errOutbox),
+				slog.String("refund_id", refund.ID))
+
+			return domain.ErrCreateOutboxMessage
+		}
+
+		s.logger.InfoContext(ctx, "Refund created",
+			slog.String("refund_id", refund.ID),
+			slog.String("status", string(payment.Status)))
+
+		refunded = payment
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if refunded != nil {
+		s.metrics.PaymentStatusChanged(*refunded)
+	}
+
+	return refund, replayed, nil
+}
@@ -0,0 +1,333 @@
+package refunds
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewRefundService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockRefundRepo := mocks.NewMockRefundRepository(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+	logger := slog.Default()
+
+	service := NewRefundService(ServiceConfig{
+		Logger:            logger,
+		DB:                mockDB,
+		RefundRepository:  mockRefundRepo,
+		PaymentRepository: mockPaymentRepo,
+		BalanceService:    mockBalanceService,
+		OutboxRepository:  mockOutboxRepo,
+		Metrics:           mockMetrics,
+	})
+
+	assert.NotNil(t, service)
+	assert.Equal(t, logger, service.logger)
+	assert.Equal(t, mockDB, service.db)
+	assert.Equal(t, mockRefundRepo, service.refundRepo)
+	assert.Equal(t, mockPaymentRepo, service.paymentRepo)
+	assert.Equal(t, mockBalanceService, service.balanceService)
+	assert.Equal(t, mockOutboxRepo, service.outboxRepo)
+	assert.Equal(t, mockMetrics, service.metrics)
+}
+
+func TestService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockRefundRepo := mocks.NewMockRefundRepository(ctrl)
+	mockPaymentRepo := mocks.NewMockPaymentRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockMetrics := mocks.NewMockPaymentMetrics(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		refundRepo:     mockRefundRepo,
+		paymentRepo:    mockPaymentRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
+		metrics:        mockMetrics,
+	}
+
+	ctx := context.Background()
+	paymentCtx := correlation.WithPaymentID(ctx, "payment-123")
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	request := domain.CreateRefundRequest{
+		UserID:         "user-123",
+		PaymentID:      "payment-123",
+		Amount:         4000,
+		IdempotencyKey: "refund-key-1",
+	}
+	approvedPayment := func() *domain.Payment {
+		return &domain.Payment{
+			ID:              "payment-123",
+			UserID:          "user-123",
+			Amount:          10000,
+			Currency:        domain.CurrencyUSD,
+			FundingAmount:   10000,
+			FundingCurrency: domain.CurrencyUSD,
+			Status:          domain.StatusApproved,
+			Version:         2,
+		}
+	}
+
+	t.Run("partial refund", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(approvedPayment(), nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockRefundRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, refund domain.Refund) error {
+				assert.NotEmpty(t, refund.ID)
+				assert.Equal(t, request.PaymentID, refund.PaymentID)
+				assert.Equal(t, request.Amount, refund.Amount)
+				assert.Equal(t, domain.CurrencyUSD, refund.Currency)
+				return nil
+			}).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusApproved).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusPartiallyRefunded, payment.Status)
+				assert.Equal(t, int64(4000), payment.RefundedAmount)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(paymentCtx, gomock.Any(), "user-123", gomock.Any(), domain.MovementRefund, domain.NewMoney(4000, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Equal(t, domain.EventTypeRefundRequested, message.EventType)
+				assert.Equal(t, "payment-123", message.AggregateID)
+				return nil
+			}).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusPartiallyRefunded, payment.Status)
+			}).Times(1)
+
+		refund, replayed, err := service.Create(ctx, request)
+		assert.NoError(t, err)
+		assert.False(t, replayed)
+		assert.Equal(t, request.Amount, refund.Amount)
+	})
+
+	t.Run("refund of the remaining amount", func(t *testing.T) {
+		payment := approvedPayment()
+		payment.Status = domain.StatusPartiallyRefunded
+		payment.RefundedAmount = 6000
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(payment, nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockRefundRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusPartiallyRefunded).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, payment domain.Payment, from domain.PaymentStatus) error {
+				assert.Equal(t, domain.StatusRefunded, payment.Status)
+				assert.Equal(t, payment.Amount, payment.RefundedAmount)
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(paymentCtx, gomock.Any(), "user-123", gomock.Any(), domain.MovementRefund, domain.NewMoney(4000, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockMetrics.EXPECT().
+			PaymentStatusChanged(gomock.Any()).
+			Do(func(payment domain.Payment) {
+				assert.Equal(t, domain.StatusRefunded, payment.Status)
+			}).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.NoError(t, err)
+	})
+
+	t.Run("refunds of a converted payment credit the funding currency", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			refunded int64
+			amount   int64
+			credit   int64
+		}{
+			{name: "first refund", refunded: 0, amount: 3333, credit: 4228744},
+			{name: "last refund takes the rounding remainder", refunded: 6667, amount: 3333, credit: 4228745},
+		}
+
+		for _, tt := range tests {
+			payment := approvedPayment()
+			payment.FundingAmount = 12687501
+			payment.FundingCurrency = domain.CurrencyARS
+			payment.FXRate = "1268.7501000000"
+			payment.RefundedAmount = tt.refunded
+			if tt.refunded > 0 {
+				payment.Status = domain.StatusPartiallyRefunded
+			}
+			refundRequest := request
+			refundRequest.Amount = tt.amount
+
+			mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+			mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(payment, nil).Times(1)
+			mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+			mockRefundRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			mockBalanceService.EXPECT().
+				Credit(paymentCtx, gomock.Any(), "user-123", gomock.Any(), domain.MovementRefund, domain.NewMoney(tt.credit, domain.CurrencyARS)).
+				Return(nil).Times(1)
+			mockOutboxRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+			mockMetrics.EXPECT().PaymentStatusChanged(gomock.Any()).Times(1)
+
+			_, _, err := service.Create(ctx, refundRequest)
+			assert.NoError(t, err, tt.name)
+		}
+	})
+
+	t.Run("refund exceeds the amount left", func(t *testing.T) {
+		payment := approvedPayment()
+		payment.Status = domain.StatusPartiallyRefunded
+		payment.RefundedAmount = 7000
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(payment, nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockRefundRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		refund, _, err := service.Create(ctx, request)
+		assert.Nil(t, refund)
+		assert.Equal(t, domain.ErrRefundExceedsPayment, err)
+	})
+
+	t.Run("payment not approved", func(t *testing.T) {
+		payment := approvedPayment()
+		payment.Status = domain.StatusPending
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(payment, nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockRefundRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrPaymentNotRefundable, err)
+	})
+
+	t.Run("payment of another user", func(t *testing.T) {
+		payment := approvedPayment()
+		payment.UserID = "user-456"
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(payment, nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrPaymentNotFound, err)
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).
+			Return(nil, domain.ErrPaymentNotFound).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrPaymentNotFound, err)
+	})
+
+	t.Run("error getting payment", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).
+			Return(nil, errors.New("database error")).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrGetPayment, err)
+	})
+
+	t.Run("idempotent replay", func(t *testing.T) {
+		existing := &domain.Refund{
+			ID:             "refund-1",
+			PaymentID:      request.PaymentID,
+			UserID:         request.UserID,
+			Amount:         request.Amount,
+			IdempotencyKey: request.IdempotencyKey,
+		}
+		payment := approvedPayment()
+		payment.Status = domain.StatusRefunded
+		payment.RefundedAmount = payment.Amount
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(payment, nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(existing, nil).Times(1)
+		mockRefundRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockBalanceService.EXPECT().Credit(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		refund, replayed, err := service.Create(ctx, request)
+		assert.NoError(t, err)
+		assert.True(t, replayed)
+		assert.Equal(t, existing, refund)
+	})
+
+	t.Run("idempotency key reused with a different amount", func(t *testing.T) {
+		existing := &domain.Refund{
+			ID:        "refund-1",
+			PaymentID: request.PaymentID,
+			UserID:    request.UserID,
+			Amount:    request.Amount + 1,
+		}
+
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(approvedPayment(), nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(existing, nil).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrIdempotencyKeyReused, err)
+	})
+
+	t.Run("error checking idempotency", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(approvedPayment(), nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, errors.New("database error")).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrCheckIdempotency, err)
+	})
+
+	t.Run("error creating refund", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(approvedPayment(), nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockRefundRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+		mockPaymentRepo.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrCreateRefund, err)
+	})
+
+	t.Run("error crediting balance", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(paymentCtx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockPaymentRepo.EXPECT().FindByID(paymentCtx, gomock.Any(), request.PaymentID).Return(approvedPayment(), nil).Times(1)
+		mockRefundRepo.EXPECT().CheckIdempotency(paymentCtx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockRefundRepo.EXPECT().Create(paymentCtx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockPaymentRepo.EXPECT().Update(paymentCtx, gomock.Any(), gomock.Any(), domain.StatusApproved).Return(nil).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(paymentCtx, gomock.Any(), "user-123", gomock.Any(), domain.MovementRefund, domain.NewMoney(4000, domain.CurrencyUSD)).
+			Return(domain.ErrCreditBalance).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrCreditBalance, err)
+	})
+}
@@ -0,0 +1,125 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+)
+
+const (
+	_defaultBurstWindow = 10 * time.Minute
+)
+
+type RulesConfig struct {
+	Logger *slog.Logger
+	// LargeAmounts is the funding amount from which a payment is considered
+	// large, by wallet currency. Currencies not listed have no large payments.
+	LargeAmounts map[domain.Currency]int64
+	// BurstWindow is the period payments are counted over to detect bursts.
+	BurstWindow time.Duration
+	// ReviewBurst and DenyBurst are the number of payments already made in the
+	// window from which the next one is sent to review or denied. Zero
+	// disables the rule.
+	ReviewBurst int
+	DenyBurst   int
+}
+
+// RulesEvaluator is the default risk evaluator. It flags, in order of
+// severity:
+//   - bursts of payments, denied or sent to review depending on their size
+//   - large payments to a service the user never paid before
+//   - large payments from requests that do not identify the device
+type RulesEvaluator struct {
+	logger       *slog.Logger
+	largeAmounts map[domain.Currency]int64
+	burstWindow  time.Duration
+	reviewBurst  int
+	denyBurst    int
+	now          func() time.Time
+}
+
+func NewRulesEvaluator(config RulesConfig) *RulesEvaluator {
+	evaluator := &RulesEvaluator{
+		logger:       config.Logger,
+		largeAmounts: config.LargeAmounts,
+		burstWindow:  config.BurstWindow,
+		reviewBurst:  config.ReviewBurst,
+		denyBurst:    config.DenyBurst,
+		now:          time.Now,
+	}
+
+	if evaluator.burstWindow <= 0 {
+		evaluator.burstWindow = _defaultBurstWindow
+	}
+
+	return evaluator
+}
+
+func (e *RulesEvaluator) Evaluate(ctx context.Context, input domain.RiskInput) (*domain.RiskAssessment, error) {
+	assessment := &domain.RiskAssessment{Decision: domain.RiskAllow}
+	flag := func(decision domain.RiskDecision, reason string) {
+		if decision == domain.RiskDeny || assessment.Decision == domain.RiskAllow {
+			assessment.Decision = decision
+		}
+		assessment.Reasons = append(assessment.Reasons, reason)
+	}
+
+	burst := e.burst(input.History)
+	switch {
+	case e.denyBurst > 0 && burst >= e.denyBurst:
+		flag(domain.RiskDeny, fmt.Sprintf("%d payments in the last %s", burst, e.burstWindow))
+	case e.reviewBurst > 0 && burst >= e.reviewBurst:
+		flag(domain.RiskReview, fmt.Sprintf("%d payments in the last %s", burst, e.burstWindow))
+	}
+
+	if e.large(input.Funding) {
+		if !paidBefore(input.History, input.Request.ServiceID) {
+			flag(domain.RiskReview, "large amount to a new service")
+		}
+
+		if input.Metadata.DeviceID == "" {
+			flag(domain.RiskReview, "large amount from an unidentified device")
+		}
+	}
+
+	if assessment.Decision != domain.RiskAllow {
+		e.logger.WarnContext(ctx, "payment flagged by risk rules",
+			slog.String("decision", string(assessment.Decision)),
+			slog.Any("reasons", assessment.Reasons),
+			slog.String("ip", input.Metadata.IP))
+	}
+
+	return assessment, nil
+}
+
+// burst counts the payments of the history made within the burst window.
+func (e *RulesEvaluator) burst(history []domain.Payment) int {
+	since := e.now().Add(-e.burstWindow)
+
+	count := 0
+	for _, payment := range history {
+		if payment.CreatedAt.After(since) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (e *RulesEvaluator) large(funding domain.Money) bool {
+	threshold := e.largeAmounts[funding.Currency]
+	return threshold > 0 && funding.Amount >= threshold
+}
+
+func paidBefore(history []domain.Payment, serviceID string) bool {
+	for _, payment := range history {
+		if payment.ServiceID == serviceID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+package risk
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRulesEvaluator(t *testing.T) {
+	logger := slog.Default()
+	largeAmounts := map[domain.Currency]int64{domain.CurrencyARS: 1000000}
+
+	evaluator := NewRulesEvaluator(RulesConfig{
+		Logger:       logger,
+		LargeAmounts: largeAmounts,
+		ReviewBurst:  3,
+		DenyBurst:    6,
+	})
+
+	assert.NotNil(t, evaluator)
+	assert.Equal(t, logger, evaluator.logger)
+	assert.Equal(t, largeAmounts, evaluator.largeAmounts)
+	assert.Equal(t, _defaultBurstWindow, evaluator.burstWindow)
+	assert.Equal(t, 3, evaluator.reviewBurst)
+	assert.Equal(t, 6, evaluator.denyBurst)
+	assert.NotNil(t, evaluator.now)
+}
+
+func TestRulesEvaluator_Evaluate(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 30, 0, 0, time.UTC)
+	payments := func(count int, serviceID string, age time.Duration) []domain.Payment {
+		history := make([]domain.Payment, count)
+		for i := range history {
+			history[i] = domain.Payment{ServiceID: serviceID, CreatedAt: now.Add(-age)}
+		}
+		return history
+	}
+	device := domain.RequestMetadata{IP: "10.0.0.1", DeviceID: "device-1"}
+
+	tests := []struct {
+		name             string
+		funding          domain.Money
+		history          []domain.Payment
+		metadata         domain.RequestMetadata
+		expectedDecision domain.RiskDecision
+		expectedReasons  int
+	}{
+		{
+			name:             "usual payment",
+			funding:          domain.NewMoney(5000, domain.CurrencyARS),
+			history:          payments(2, "service-1", time.Minute),
+			metadata:         device,
+			expectedDecision: domain.RiskAllow,
+		},
+		{
+			name:             "burst sent to review",
+			funding:          domain.NewMoney(5000, domain.CurrencyARS),
+			history:          payments(3, "service-1", time.Minute),
+			metadata:         device,
+			expectedDecision: domain.RiskReview,
+			expectedReasons:  1,
+		},
+		{
+			name:             "burst denied",
+			funding:          domain.NewMoney(5000, domain.CurrencyARS),
+			history:          payments(6, "service-1", time.Minute),
+			metadata:         device,
+			expectedDecision: domain.RiskDeny,
+			expectedReasons:  1,
+		},
+		{
+			name:             "payments outside the burst window are not counted",
+			funding:          domain.NewMoney(5000, domain.CurrencyARS),
+			history:          payments(6, "service-1", time.Hour),
+			metadata:         device,
+			expectedDecision: domain.RiskAllow,
+		},
+		{
+			name:             "large amount to a service paid before",
+			funding:          domain.NewMoney(1000000, domain.CurrencyARS),
+			history:          payments(1, "service-1", time.Hour),
+			metadata:         device,
+			expectedDecision: domain.RiskAllow,
+		},
+		{
+			name:             "large amount to a new service",
+			funding:          domain.NewMoney(1000000, domain.CurrencyARS),
+			history:          payments(1, "service-2", time.Hour),
+			metadata:         device,
+			expectedDecision: domain.RiskReview,
+			expectedReasons:  1,
+		},
+		{
+			name:             "large amount from an unidentified device",
+			funding:          domain.NewMoney(1000000, domain.CurrencyARS),
+			history:          payments(1, "service-1", time.Hour),
+			metadata:         domain.RequestMetadata{IP: "10.0.0.1"},
+			expectedDecision: domain.RiskReview,
+			expectedReasons:  1,
+		},
+		{
+			name:             "denial prevails over review",
+			funding:          domain.NewMoney(1000000, domain.CurrencyARS),
+			history:          payments(6, "service-2", time.Minute),
+			metadata:         device,
+			expectedDecision: domain.RiskDeny,
+			expectedReasons:  2,
+		},
+		{
+			name:             "currency without large amount",
+			funding:          domain.NewMoney(100000000, domain.CurrencyUSD),
+			metadata:         domain.RequestMetadata{},
+			expectedDecision: domain.RiskAllow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evaluator := NewRulesEvaluator(RulesConfig{
+				Logger:       slog.Default(),
+				LargeAmounts: map[domain.Currency]int64{domain.CurrencyARS: 1000000},
+				ReviewBurst:  3,
+				DenyBurst:    6,
+			})
+			evaluator.now = func() time.Time { return now }
+
+			assessment, err := evaluator.Evaluate(context.Background(), domain.RiskInput{
+				Request:  domain.CreatePaymentRequest{UserID: "user-1", ServiceID: "service-1"},
+				Funding:  tt.funding,
+				History:  tt.history,
+				Metadata: tt.metadata,
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedDecision, assessment.Decision)
+			assert.Len(t, assessment.Reasons, tt.expectedReasons)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package transfers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
+	"github.com/jackc/pgx/v5"
+)
+
+type ServiceConfig struct {
+	Logger             *slog.Logger
+	DB                 ports.Database
+	TransferRepository ports.TransferRepository
+	BalanceService     ports.BalanceService
+	OutboxRepository   ports.OutboxRepository
+}
+
+type Service struct {
+	logger         *slog.Logger
+	db             ports.Database
+	transferRepo   ports.TransferRepository
+	balanceService ports.BalanceService
+	outboxRepo     ports.OutboxRepository
+}
+
+func NewTransferService(config ServiceConfig) *Service {
+	return &Service{
+		logger:         config.Logger,
+		db:             config.DB,
+		transferRepo:   config.TransferRepository,
+		balanceService: config.BalanceService,
+		outboxRepo:     config.OutboxRepository,
+	}
+}
+
+// Create moves money between the wallets of two users. The debit, the credit,
+// the transfer record and the TransferCompleted event are stored in a single
+// transaction, so either all of them happen or none does. Idempotency keys
+// behave as in payment creation.
+func (s *Service) Create(ctx context.Context, request domain.CreateTransferRequest) (*domain.Transfer, bool, error) {
+	var (
+		transfer *domain.Transfer
+		replayed bool
+	)
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		existing, err := s.transferRepo.CheckIdempotency(ctx, *tx, request.IdempotencyKey)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to check idempotency",
+				slog.Any("error", err),
+				slog.String("idempotency_key", request.IdempotencyKey))
+
+			return domain.ErrCheckIdempotency
+		}
+
+		if existing != nil {
+			if !existing.Matches(request) {
+				s.logger.WarnContext(ctx, "idempotency key reused with a different request",
+					slog.String("idempotency_key", request.IdempotencyKey),
+					slog.String("transfer_id", existing.ID))
+
+				return domain.ErrIdempotencyKeyReused
+			}
+
+			transfer = existing
+			replayed = true
+			return nil
+		}
+
+		transfer = &domain.Transfer{
+			ID:             uidgen.NewUUID(),
+			FromUserID:     request.FromUserID,
+			ToUserID:       request.ToUserID,
+			Amount:         request.Amount,
+			Currency:       request.Currency,
+			IdempotencyKey: request.IdempotencyKey,
+			CreatedAt:      time.Now(),
+		}
+
+		err = s.balanceService.Transfer(ctx, *tx, transfer.FromUserID, transfer.ToUserID, transfer.ID, transfer.Money())
+		if err != nil {
+			return err
+		}
+
+		errCreate := s.transferRepo.Create(ctx, *tx, *transfer)
+		if errCreate != nil {
+			if errors.Is(errCreate, domain.ErrIdempotencyKeyConflict) {
+				return domain.ErrIdempotencyKeyConflict
+			}
+
+			s.logger.ErrorContext(ctx, "failed to create transfer",
+				slog.Any("error", errCreate),
+				slog.String("transfer_id", transfer.ID))
+
+			return domain.ErrCreateTransfer
+		}
+
+		transferCompletedEvent := &domain.TransferCompletedEvent{
+			TransferID: transfer.ID,
+			FromUserID: transfer.FromUserID,
+			ToUserID:   transfer.ToUserID,
+			Amount:     transfer.Amount,
+			Currency:   transfer.Currency,
+		}
+
+		message, errMessage := domain.NewOutboxMessage(uidgen.NewUUID(),
+			domain.EventTypeTransferCompleted, transfer.ID, transferCompletedEvent)
+		if errMessage != nil {
+			s.logger.ErrorContext(ctx, "failed to build outbox message",
+				slog.Any("error", errMessage),
+				slog.String("transfer_id", transfer.ID))
+
+			return domain.ErrCreateOutboxMessage
+		}
+
+		errOutbox := s.outboxRepo.Create(ctx, *tx, message)
+		if errOutbox != nil {
+			s.logger.ErrorContext(ctx, "failed to create outbox message",
+				slog.Any("error", errOutbox),
+				slog.String("transfer_id", transfer.ID))
+
+			return domain.ErrCreateOutboxMessage
+		}
+
+		s.logger.InfoContext(ctx, "Transfer completed", slog.String("transfer_id", transfer.ID))
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return transfer, replayed, nil
+}
@@ -0,0 +1,476 @@
+package transfers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/balance"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewTransferService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockTransferRepo := mocks.NewMockTransferRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	logger := slog.Default()
+
+	service := NewTransferService(ServiceConfig{
+		Logger:             logger,
+		DB:                 mockDB,
+		TransferRepository: mockTransferRepo,
+		BalanceService:     mockBalanceService,
+		OutboxRepository:   mockOutboxRepo,
+	})
+
+	assert.NotNil(t, service)
+	assert.Equal(t, logger, service.logger)
+	assert.Equal(t, mockDB, service.db)
+	assert.Equal(t, mockTransferRepo, service.transferRepo)
+	assert.Equal(t, mockBalanceService, service.balanceService)
+	assert.Equal(t, mockOutboxRepo, service.outboxRepo)
+}
+
+func TestService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockTransferRepo := mocks.NewMockTransferRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		transferRepo:   mockTransferRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
+	}
+
+	ctx := context.Background()
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	request := domain.CreateTransferRequest{
+		FromUserID:     "user-123",
+		ToUserID:       "user-456",
+		Amount:         2500,
+		Currency:       domain.CurrencyUSD,
+		IdempotencyKey: "transfer-key-1",
+	}
+
+	t.Run("successful transfer", func(t *testing.T) {
+		var transferID string
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTransferRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceService.EXPECT().
+			Transfer(ctx, gomock.Any(), "user-123", "user-456", gomock.Any(), domain.NewMoney(2500, domain.CurrencyUSD)).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, fromUserID, toUserID, id string, amount domain.Money) error {
+				transferID = id
+				return nil
+			}).Times(1)
+		mockTransferRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, transfer domain.Transfer) error {
+				assert.Equal(t, transferID, transfer.ID)
+				assert.Equal(t, request.FromUserID, transfer.FromUserID)
+				assert.Equal(t, request.ToUserID, transfer.ToUserID)
+				assert.Equal(t, request.Currency, transfer.Currency)
+				return nil
+			}).Times(1)
+		mockOutboxRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Equal(t, domain.EventTypeTransferCompleted, message.EventType)
+				assert.Equal(t, transferID, message.AggregateID)
+				return nil
+			}).Times(1)
+
+		transfer, replayed, err := service.Create(ctx, request)
+		assert.NoError(t, err)
+		assert.False(t, replayed)
+		assert.Equal(t, request.Amount, transfer.Amount)
+	})
+
+	t.Run("idempotent replay", func(t *testing.T) {
+		existing := &domain.Transfer{
+			ID:             "transfer-1",
+			FromUserID:     request.FromUserID,
+			ToUserID:       request.ToUserID,
+			Amount:         request.Amount,
+			Currency:       request.Currency,
+			IdempotencyKey: request.IdempotencyKey,
+		}
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTransferRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(existing, nil).Times(1)
+		mockBalanceService.EXPECT().
+			Transfer(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		transfer, replayed, err := service.Create(ctx, request)
+		assert.NoError(t, err)
+		assert.True(t, replayed)
+		assert.Equal(t, existing, transfer)
+	})
+
+	t.Run("idempotency key reused with a different receiver", func(t *testing.T) {
+		existing := &domain.Transfer{
+			ID:         "transfer-1",
+			FromUserID: request.FromUserID,
+			ToUserID:   "user-789",
+			Amount:     request.Amount,
+			Currency:   request.Currency,
+		}
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTransferRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(existing, nil).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrIdempotencyKeyReused, err)
+	})
+
+	t.Run("error checking idempotency", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTransferRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, errors.New("database error")).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrCheckIdempotency, err)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTransferRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceService.EXPECT().
+			Transfer(ctx, gomock.Any(), "user-123", "user-456", gomock.Any(), domain.NewMoney(2500, domain.CurrencyUSD)).
+			Return(domain.ErrInsufficientFunds).Times(1)
+		mockTransferRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrInsufficientFunds, err)
+	})
+
+	t.Run("concurrent request with the same key", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTransferRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceService.EXPECT().
+			Transfer(ctx, gomock.Any(), "user-123", "user-456", gomock.Any(), domain.NewMoney(2500, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockTransferRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyConflict).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrIdempotencyKeyConflict, err)
+	})
+
+	t.Run("error creating transfer", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTransferRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceService.EXPECT().
+			Transfer(ctx, gomock.Any(), "user-123", "user-456", gomock.Any(), domain.NewMoney(2500, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockTransferRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrCreateTransfer, err)
+	})
+
+	t.Run("error creating outbox message", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTransferRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceService.EXPECT().
+			Transfer(ctx, gomock.Any(), "user-123", "user-456", gomock.Any(), domain.NewMoney(2500, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockTransferRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+
+		_, _, err := service.Create(ctx, request)
+		assert.Equal(t, domain.ErrCreateOutboxMessage, err)
+	})
+}
+
+// TestService_Create_Concurrent runs transfers in every direction between a
+// few wallets at once, through the real balance service and in-memory
+// repositories that lock wallet rows like SELECT ... FOR UPDATE does. It hangs
+// if the lock order lets two transfers deadlock and fails if the service moves
+// money it did not record. The fakes never roll back, so it says nothing about
+// the atomicity of the database transaction.
+func TestService_Create_Concurrent(t *testing.T) {
+	const (
+		initialBalance = int64(1000)
+		workers        = 8
+		transfersEach  = 200
+	)
+
+	users := []string{"user-a", "user-b", "user-c", "user-d"}
+	balanceRepo := newFakeBalanceRepository(users, initialBalance)
+	ledgerRepo := &fakeLedgerRepository{}
+	transferRepo := newFakeTransferRepository()
+	outboxRepo := &fakeOutboxRepository{}
+
+	service := NewTransferService(ServiceConfig{
+		Logger: slog.New(slog.DiscardHandler),
+		DB:     fakeDatabase{},
+		BalanceService: balance.NewBalanceService(&balance.ServiceConfig{
+			Logger:            slog.New(slog.DiscardHandler),
+			BalanceRepository: balanceRepo,
+			LedgerRepository:  ledgerRepo,
+		}),
+		TransferRepository: transferRepo,
+		OutboxRepository:   outboxRepo,
+	})
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(int64(worker)))
+
+			for i := 0; i < transfersEach; i++ {
+				from := users[random.Intn(len(users))]
+				to := users[random.Intn(len(users))]
+				if from == to {
+					continue
+				}
+
+				_, _, err := service.Create(context.Background(), domain.CreateTransferRequest{
+					FromUserID:     from,
+					ToUserID:       to,
+					Amount:         1 + random.Int63n(300),
+					Currency:       domain.CurrencyARS,
+					IdempotencyKey: fmt.Sprintf("worker-%d-transfer-%d", worker, i),
+				})
+				if err != nil && !errors.Is(err, domain.ErrInsufficientFunds) {
+					t.Errorf("unexpected transfer error: %v", err)
+				}
+			}
+		}(w)
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("transfers deadlocked")
+	}
+
+	completed := transferRepo.all()
+	require.NotEmpty(t, completed)
+	assert.Len(t, outboxRepo.messages, len(completed))
+
+	expected := make(map[string]int64, len(users))
+	for _, userID := range users {
+		expected[userID] = initialBalance
+	}
+	for _, transfer := range completed {
+		expected[transfer.FromUserID] -= transfer.Amount
+		expected[transfer.ToUserID] += transfer.Amount
+	}
+
+	var total int64
+	for _, userID := range users {
+		available := balanceRepo.available(userID)
+		assert.GreaterOrEqual(t, available, int64(0), userID)
+		assert.Equal(t, expected[userID], available, userID)
+		total += available
+	}
+	assert.Equal(t, initialBalance*int64(len(users)), total)
+
+	// both legs of every transfer were posted and the clearing account is flat
+	assert.Len(t, ledgerRepo.transactions, 2*len(completed))
+	var clearing int64
+	for _, transaction := range ledgerRepo.transactions {
+		assert.True(t, transaction.Balanced())
+		for _, entry := range transaction.Entries {
+			if entry.Account != domain.AccountTransfers {
+				continue
+			}
+			if entry.Direction == domain.EntryDebit {
+				clearing += entry.Amount
+			} else {
+				clearing -= entry.Amount
+			}
+		}
+	}
+	assert.Zero(t, clearing)
+}
+
+// fakeTx stands in for a database transaction, holding the wallet row locks
+// taken through it until the transaction ends. Rollbacks are not modelled:
+// wallets are only written once every check passed.
+type fakeTx struct {
+	pgx.Tx
+	locks []*sync.Mutex
+}
+
+// fakeDatabase runs transactions in memory; calling any method but WithTx
+// panics through the nil embedded interface.
+type fakeDatabase struct {
+	ports.Database
+}
+
+func (fakeDatabase) WithTx(ctx context.Context, fn func(*pgx.Tx) error) error {
+	ftx := &fakeTx{}
+	defer func() {
+		for i := len(ftx.locks) - 1; i >= 0; i-- {
+			ftx.locks[i].Unlock()
+		}
+	}()
+
+	var tx pgx.Tx = ftx
+	return fn(&tx)
+}
+
+type fakeWallet struct {
+	mu      sync.Mutex
+	balance domain.Balance
+}
+
+// fakeBalanceRepository implements the methods used by transfers; calling any
+// other one panics through the nil embedded interface.
+type fakeBalanceRepository struct {
+	ports.BalanceRepository
+	wallets map[string]*fakeWallet
+}
+
+func newFakeBalanceRepository(users []string, available int64) *fakeBalanceRepository {
+	repo := &fakeBalanceRepository{wallets: make(map[string]*fakeWallet, len(users))}
+	for _, userID := range users {
+		repo.wallets[userID] = &fakeWallet{balance: domain.Balance{
+			UserID:    userID,
+			Currency:  domain.CurrencyARS,
+			Available: available,
+			Status:    domain.WalletActive,
+		}}
+	}
+
+	return repo
+}
+
+func (r *fakeBalanceRepository) FindByUserID(ctx context.Context, tx pgx.Tx, userID string, currency domain.Currency) (*domain.Balance, error) {
+	wallet, ok := r.wallets[userID]
+	if !ok {
+		return nil, domain.ErrWalletNotFound
+	}
+	if wallet.balance.Currency != currency {
+		return nil, domain.ErrCurrencyNotHeld
+	}
+
+	wallet.mu.Lock()
+	ftx := tx.(*fakeTx)
+	ftx.locks = append(ftx.locks, &wallet.mu)
+	// let other transactions run between the two wallet locks of a transfer
+	runtime.Gosched()
+
+	balance := wallet.balance
+	return &balance, nil
+}
+
+func (r *fakeBalanceRepository) Debit(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error {
+	wallet := r.wallets[userID]
+	if wallet.balance.Available < amount.Amount {
+		return domain.ErrInsufficientFunds
+	}
+
+	wallet.balance.Available -= amount.Amount
+	return nil
+}
+
+func (r *fakeBalanceRepository) Credit(ctx context.Context, tx pgx.Tx, userID string, amount domain.Money) error {
+	r.wallets[userID].balance.Available += amount.Amount
+	return nil
+}
+
+func (r *fakeBalanceRepository) available(userID string) int64 {
+	return r.wallets[userID].balance.Available
+}
+
+type fakeLedgerRepository struct {
+	ports.LedgerRepository
+	mu           sync.Mutex
+	transactions []domain.LedgerTransaction
+}
+
+func (r *fakeLedgerRepository) Post(ctx context.Context, tx pgx.Tx, transaction domain.LedgerTransaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.transactions = append(r.transactions, transaction)
+	return nil
+}
+
+type fakeTransferRepository struct {
+	mu        sync.Mutex
+	transfers map[string]domain.Transfer
+}
+
+func newFakeTransferRepository() *fakeTransferRepository {
+	return &fakeTransferRepository{transfers: make(map[string]domain.Transfer)}
+}
+
+func (r *fakeTransferRepository) CheckIdempotency(ctx context.Context, tx pgx.Tx, idempotencyKey string) (*domain.Transfer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transfer, ok := r.transfers[idempotencyKey]
+	if !ok {
+		return nil, nil
+	}
+
+	return &transfer, nil
+}
+
+func (r *fakeTransferRepository) Create(ctx context.Context, tx pgx.Tx, transfer domain.Transfer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.transfers[transfer.IdempotencyKey]; ok {
+		return domain.ErrIdempotencyKeyConflict
+	}
+
+	r.transfers[transfer.IdempotencyKey] = transfer
+	return nil
+}
+
+func (r *fakeTransferRepository) all() []domain.Transfer {
+	transfers := make([]domain.Transfer, 0, len(r.transfers))
+	for _, transfer := range r.transfers {
+		transfers = append(transfers, transfer)
+	}
+
+	return transfers
+}
+
+type fakeOutboxRepository struct {
+	ports.OutboxRepository
+	mu       sync.Mutex
+	messages []domain.OutboxMessage
+}
+
+func (r *fakeOutboxRepository) Create(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = append(r.messages, message)
+	return nil
+}
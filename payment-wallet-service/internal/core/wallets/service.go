@@ -0,0 +1,398 @@
+package wallets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/uidgen"
+	"github.com/jackc/pgx/v5"
+)
+
+type ServiceConfig struct {
+	Logger            *slog.Logger
+	DB                ports.Database
+	TopUpRepository   ports.TopUpRepository
+	BalanceRepository ports.BalanceRepository
+	BalanceService    ports.BalanceService
+	OutboxRepository  ports.OutboxRepository
+	AuditService      ports.AuditService
+	// MaxTopUpAmounts caps a single top-up by currency, in its minor unit.
+	// Currencies without a cap cannot be topped up.
+	MaxTopUpAmounts map[domain.Currency]int64
+}
+
+type Service struct {
+	logger         *slog.Logger
+	db             ports.Database
+	topUpRepo      ports.TopUpRepository
+	balanceRepo    ports.BalanceRepository
+	balanceService ports.BalanceService
+	outboxRepo     ports.OutboxRepository
+	auditService   ports.AuditService
+	maxTopUps      map[domain.Currency]int64
+}
+
+func NewWalletService(config ServiceConfig) *Service {
+	return &Service{
+		logger:         config.Logger,
+		db:             config.DB,
+		topUpRepo:      config.TopUpRepository,
+		balanceRepo:    config.BalanceRepository,
+		balanceService: config.BalanceService,
+		outboxRepo:     config.OutboxRepository,
+		auditService:   config.AuditService,
+		maxTopUps:      config.MaxTopUpAmounts,
+	}
+}
+
+// Create opens an empty wallet for the user in the currency. A user holds one
+// wallet per currency and they all share a status, so a frozen user gets a
+// frozen wallet and a closed one cannot open any.
+func (s *Service) Create(ctx context.Context, userID string, currency domain.Currency) (*domain.Balance, error) {
+	wallet := &domain.Balance{
+		UserID:    userID,
+		Currency:  currency,
+		Status:    domain.WalletActive,
+		UpdatedAt: time.Now(),
+	}
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		existing, err := s.balanceRepo.FindAllByUserID(ctx, *tx, userID)
+		if err != nil && !errors.Is(err, domain.ErrWalletNotFound) {
+			s.logger.ErrorContext(ctx, "failed to get wallets",
+				slog.Any("error", err))
+
+			return domain.ErrGetBalance
+		}
+
+		if len(existing) > 0 {
+			wallet.Status = existing[0].Status
+		}
+
+		if wallet.Status == domain.WalletClosed {
+			return domain.ErrWalletClosed
+		}
+
+		errCreate := s.balanceRepo.Create(ctx, *tx, *wallet)
+		if errCreate != nil {
+			if errors.Is(errCreate, domain.ErrWalletAlreadyExists) {
+				return domain.ErrWalletAlreadyExists
+			}
+
+			s.logger.ErrorContext(ctx, "failed to create wallet",
+				slog.Any("error", errCreate),
+				slog.String("currency", string(currency)))
+
+			return domain.ErrCreateWallet
+		}
+
+		s.logger.InfoContext(ctx, "Wallet created",
+			slog.String("currency", string(currency)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// Freeze blocks new payments from the wallets of the user until they are
+// unfrozen. Incoming credits such as refunds are still accepted.
+func (s *Service) Freeze(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error) {
+	return s.changeStatus(ctx, operator, userID, domain.WalletFrozen, domain.AuditFreezeWallet)
+}
+
+func (s *Service) Unfreeze(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error) {
+	return s.changeStatus(ctx, operator, userID, domain.WalletActive, domain.AuditUnfreezeWallet)
+}
+
+// Close permanently closes the wallets of the user. It is refused while
+// payments still hold reserved funds in any currency, since settling them
+// needs the wallet.
+func (s *Service) Close(ctx context.Context, operator domain.Operator, userID string) ([]domain.Balance, error) {
+	return s.changeStatus(ctx, operator, userID, domain.WalletClosed, domain.AuditCloseWallet)
+}
+
+// changeStatus moves every currency wallet of the user to next, locking them so
+// the checks hold until the change is committed. The status belongs to the
+// user rather than to a currency, so all wallets share it. Moving wallets to
+// the status they already have is a no-op. The action is audited in the same
+// transaction.
+func (s *Service) changeStatus(ctx context.Context, operator domain.Operator, userID string,
+	next domain.WalletStatus, action string) ([]domain.Balance, error) {
+	var wallets []domain.Balance
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		var err error
+		wallets, err = s.balanceRepo.FindAllByUserID(ctx, *tx, userID)
+		if err != nil {
+			if errors.Is(err, domain.ErrWalletNotFound) {
+				return domain.ErrWalletNotFound
+			}
+
+			s.logger.ErrorContext(ctx, "failed to get wallet",
+				slog.Any("error", err),
+				slog.String("user_id", userID))
+
+			return domain.ErrGetBalance
+		}
+
+		current := wallets[0].Status
+		details := map[string]domain.WalletStatus{"from": current, "to": next}
+		if current == next {
+			return s.auditService.Append(ctx, *tx, operator, action, userID, "", details)
+		}
+
+		if !current.CanTransitionTo(next) {
+			return fmt.Errorf("%w: %s to %s", domain.ErrInvalidWalletTransition, current, next)
+		}
+
+		if next == domain.WalletClosed {
+			for _, wallet := range wallets {
+				if wallet.Reserved > 0 {
+					return domain.ErrWalletHasReservedFunds
+				}
+			}
+		}
+
+		errUpdate := s.balanceRepo.UpdateStatus(ctx, *tx, userID, next)
+		if errUpdate != nil {
+			s.logger.ErrorContext(ctx, "failed to update wallet status",
+				slog.Any("error", errUpdate),
+				slog.String("user_id", userID),
+				slog.String("status", string(next)))
+
+			return domain.ErrUpdateWallet
+		}
+
+		s.logger.InfoContext(ctx, "Wallet status changed",
+			slog.String("user_id", userID),
+			slog.String("from", string(current)),
+			slog.String("to", string(next)))
+
+		now := time.Now()
+		for i := range wallets {
+			wallets[i].Status = next
+			wallets[i].UpdatedAt = now
+		}
+
+		return s.auditService.Append(ctx, *tx, operator, action, userID, "", details)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+// Adjust corrects the available balance of a wallet by hand, crediting it for
+// positive amounts and debiting it for negative ones against the adjustments
+// ledger account. Frozen wallets can be adjusted, closed ones cannot, and a
+// debit cannot leave the balance negative. The adjustment is audited with its
+// reason in the same transaction.
+func (s *Service) Adjust(ctx context.Context, operator domain.Operator, request domain.BalanceAdjustmentRequest) (*domain.BalanceAdjustment, error) {
+	adjustment := &domain.BalanceAdjustment{
+		ID:       uidgen.NewUUID(),
+		UserID:   request.UserID,
+		Amount:   request.Amount,
+		Currency: request.Currency,
+		Reason:   request.Reason,
+	}
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		wallet, err := s.balanceRepo.FindByUserID(ctx, *tx, request.UserID, request.Currency)
+		if err != nil {
+			if errors.Is(err, domain.ErrWalletNotFound) {
+				return domain.ErrWalletNotFound
+			}
+
+			if errors.Is(err, domain.ErrCurrencyNotHeld) {
+				return domain.ErrCurrencyNotHeld
+			}
+
+			s.logger.ErrorContext(ctx, "failed to get wallet",
+				slog.Any("error", err),
+				slog.String("user_id", request.UserID))
+
+			return domain.ErrGetBalance
+		}
+
+		if wallet.Status == domain.WalletClosed {
+			return domain.ErrWalletClosed
+		}
+
+		amount := request.Money()
+		if request.Amount > 0 {
+			err = s.balanceService.Credit(ctx, *tx, request.UserID, adjustment.ID, domain.MovementAdjustmentCredit, amount)
+		} else {
+			err = s.balanceService.Debit(ctx, *tx, request.UserID, adjustment.ID, domain.MovementAdjustmentDebit, amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		errAudit := s.auditService.Append(ctx, *tx, operator, domain.AuditAdjustBalance, request.UserID, request.Reason,
+			map[string]any{"adjustment_id": adjustment.ID, "amount": request.Amount, "currency": request.Currency})
+		if errAudit != nil {
+			return errAudit
+		}
+
+		wallet.Available += request.Amount
+		wallet.UpdatedAt = time.Now()
+		adjustment.Balance = *wallet
+
+		s.logger.InfoContext(ctx, "Balance adjusted",
+			slog.String("adjustment_id", adjustment.ID),
+			slog.String("user_id", request.UserID),
+			slog.String("operator_id", operator.ID),
+			slog.Int64("amount", request.Amount))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustment, nil
+}
+
+// TopUp credits money coming from outside the system to the available balance
+// of the user on behalf of an operator; closed wallets cannot be funded and
+// amounts above the cap of the currency are refused. The top-up, the balance
+// credit, the WalletCredited event and the audit entry are stored in a single
+// transaction. Idempotency keys behave as in payment creation.
+func (s *Service) TopUp(ctx context.Context, operator domain.Operator, request domain.CreateTopUpRequest) (*domain.TopUp, bool, error) {
+	var (
+		topUp    *domain.TopUp
+		replayed bool
+	)
+
+	if request.Amount > s.maxTopUps[request.Currency] {
+		s.logger.WarnContext(ctx, "top-up above the maximum amount",
+			slog.String("operator_id", operator.ID),
+			slog.Int64("amount", request.Amount),
+			slog.String("currency", string(request.Currency)))
+
+		return nil, false, domain.ErrTopUpLimitExceeded
+	}
+
+	err := s.db.WithTx(ctx, func(tx *pgx.Tx) error {
+		existing, err := s.topUpRepo.CheckIdempotency(ctx, *tx, request.IdempotencyKey)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to check idempotency",
+				slog.Any("error", err),
+				slog.String("idempotency_key", request.IdempotencyKey))
+
+			return domain.ErrCheckIdempotency
+		}
+
+		if existing != nil {
+			if !existing.Matches(request) {
+				s.logger.WarnContext(ctx, "idempotency key reused with a different request",
+					slog.String("idempotency_key", request.IdempotencyKey),
+					slog.String("topup_id", existing.ID))
+
+				return domain.ErrIdempotencyKeyReused
+			}
+
+			topUp = existing
+			replayed = true
+			return nil
+		}
+
+		wallet, err := s.balanceRepo.FindByUserID(ctx, *tx, request.UserID, request.Currency)
+		if err != nil {
+			if errors.Is(err, domain.ErrWalletNotFound) {
+				return domain.ErrWalletNotFound
+			}
+
+			if errors.Is(err, domain.ErrCurrencyNotHeld) {
+				return domain.ErrCurrencyNotHeld
+			}
+
+			s.logger.ErrorContext(ctx, "failed to get wallet",
+				slog.Any("error", err),
+				slog.String("user_id", request.UserID))
+
+			return domain.ErrGetBalance
+		}
+
+		if wallet.Status == domain.WalletClosed {
+			return domain.ErrWalletClosed
+		}
+
+		topUp = &domain.TopUp{
+			ID:             uidgen.NewUUID(),
+			UserID:         request.UserID,
+			Amount:         request.Amount,
+			Currency:       request.Currency,
+			IdempotencyKey: request.IdempotencyKey,
+			CreatedAt:      time.Now(),
+		}
+
+		errCreate := s.topUpRepo.Create(ctx, *tx, *topUp)
+		if errCreate != nil {
+			if errors.Is(errCreate, domain.ErrIdempotencyKeyConflict) {
+				return domain.ErrIdempotencyKeyConflict
+			}
+			if errors.Is(errCreate, domain.ErrWalletNotFound) {
+				return domain.ErrWalletNotFound
+			}
+
+			s.logger.ErrorContext(ctx, "failed to create top-up",
+				slog.Any("error", errCreate),
+				slog.String("user_id", request.UserID))
+
+			return domain.ErrCreateTopUp
+		}
+
+		err = s.balanceService.Credit(ctx, *tx, topUp.UserID, topUp.ID, domain.MovementTopUp, topUp.Money())
+		if err != nil {
+			return err
+		}
+
+		walletCreditedEvent := &domain.WalletCreditedEvent{
+			TopUpID:  topUp.ID,
+			UserID:   topUp.UserID,
+			Amount:   topUp.Amount,
+			Currency: topUp.Currency,
+		}
+
+		message, errMessage := domain.NewOutboxMessage(uidgen.NewUUID(),
+			domain.EventTypeWalletCredited, topUp.UserID, walletCreditedEvent)
+		if errMessage != nil {
+			s.logger.ErrorContext(ctx, "failed to build outbox message",
+				slog.Any("error", errMessage),
+				slog.String("topup_id", topUp.ID))
+
+			return domain.ErrCreateOutboxMessage
+		}
+
+		errOutbox := s.outboxRepo.Create(ctx, *tx, message)
+		if errOutbox != nil {
+			s.logger.ErrorContext(ctx, "failed to create outbox message",
+				slog.Any("error", errOutbox),
+				slog.String("topup_id", topUp.ID))
+
+			return domain.ErrCreateOutboxMessage
+		}
+
+		s.logger.InfoContext(ctx, "Wallet credited",
+			slog.String("topup_id", topUp.ID),
+			slog.String("user_id", topUp.UserID),
+			slog.String("operator_id", operator.ID))
+
+		return s.auditService.Append(ctx, *tx, operator, domain.AuditTopUpWallet, topUp.UserID, "",
+			map[string]any{"topup_id": topUp.ID, "amount": topUp.Amount, "currency": topUp.Currency})
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return topUp, replayed, nil
+}
@@ -0,0 +1,615 @@
+package wallets
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/domain"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/internal/core/ports/mocks"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewWalletService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockTopUpRepo := mocks.NewMockTopUpRepository(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockAuditService := mocks.NewMockAuditService(ctrl)
+	logger := slog.Default()
+
+	service := NewWalletService(ServiceConfig{
+		Logger:            logger,
+		DB:                mockDB,
+		TopUpRepository:   mockTopUpRepo,
+		BalanceRepository: mockBalanceRepo,
+		BalanceService:    mockBalanceService,
+		OutboxRepository:  mockOutboxRepo,
+		AuditService:      mockAuditService,
+		MaxTopUpAmounts:   map[domain.Currency]int64{domain.CurrencyARS: 100000},
+	})
+
+	assert.NotNil(t, service)
+	assert.Equal(t, logger, service.logger)
+	assert.Equal(t, mockDB, service.db)
+	assert.Equal(t, mockTopUpRepo, service.topUpRepo)
+	assert.Equal(t, mockBalanceRepo, service.balanceRepo)
+	assert.Equal(t, mockBalanceService, service.balanceService)
+	assert.Equal(t, mockOutboxRepo, service.outboxRepo)
+	assert.Equal(t, mockAuditService, service.auditService)
+	assert.Equal(t, map[domain.Currency]int64{domain.CurrencyARS: 100000}, service.maxTopUps)
+}
+
+func TestService_TopUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockTopUpRepo := mocks.NewMockTopUpRepository(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockOutboxRepo := mocks.NewMockOutboxRepository(ctrl)
+	mockAuditService := mocks.NewMockAuditService(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		topUpRepo:      mockTopUpRepo,
+		balanceRepo:    mockBalanceRepo,
+		balanceService: mockBalanceService,
+		outboxRepo:     mockOutboxRepo,
+		auditService:   mockAuditService,
+		maxTopUps:      map[domain.Currency]int64{domain.CurrencyUSD: 10000},
+	}
+
+	ctx := context.Background()
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	request := domain.CreateTopUpRequest{
+		UserID:         "user-123",
+		Amount:         5000,
+		Currency:       domain.CurrencyUSD,
+		IdempotencyKey: "topup-key-1",
+	}
+	activeWallet := &domain.Balance{UserID: request.UserID, Status: domain.WalletActive}
+	operator := domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleOps}}
+
+	t.Run("successful top-up", func(t *testing.T) {
+		var topUpID string
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).Return(activeWallet, nil).Times(1)
+		mockTopUpRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, topUp domain.TopUp) error {
+				assert.NotEmpty(t, topUp.ID)
+				assert.Equal(t, request.UserID, topUp.UserID)
+				assert.Equal(t, request.Amount, topUp.Amount)
+				assert.Equal(t, request.Currency, topUp.Currency)
+				topUpID = topUp.ID
+				return nil
+			}).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(ctx, gomock.Any(), "user-123", gomock.Any(), domain.MovementTopUp, domain.NewMoney(5000, domain.CurrencyUSD)).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, userID, referenceID, movement string, amount domain.Money) error {
+				assert.Equal(t, topUpID, referenceID)
+				return nil
+			}).Times(1)
+		mockOutboxRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, message domain.OutboxMessage) error {
+				assert.Equal(t, domain.EventTypeWalletCredited, message.EventType)
+				assert.Equal(t, "user-123", message.AggregateID)
+				return nil
+			}).Times(1)
+		mockAuditService.EXPECT().
+			Append(ctx, gomock.Any(), operator, domain.AuditTopUpWallet, "user-123", "", gomock.Any()).
+			Return(nil).Times(1)
+
+		topUp, replayed, err := service.TopUp(ctx, operator, request)
+		assert.NoError(t, err)
+		assert.False(t, replayed)
+		assert.Equal(t, request.Amount, topUp.Amount)
+	})
+
+	t.Run("amount above the cap of the currency", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).Times(0)
+
+		capped := request
+		capped.Amount = 10001
+
+		_, _, err := service.TopUp(ctx, operator, capped)
+		assert.Equal(t, domain.ErrTopUpLimitExceeded, err)
+	})
+
+	t.Run("currency without a cap", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(gomock.Any(), gomock.Any()).Times(0)
+
+		uncapped := request
+		uncapped.Currency = domain.CurrencyARS
+
+		_, _, err := service.TopUp(ctx, operator, uncapped)
+		assert.Equal(t, domain.ErrTopUpLimitExceeded, err)
+	})
+
+	t.Run("error recording audit entry", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).Return(activeWallet, nil).Times(1)
+		mockTopUpRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(ctx, gomock.Any(), "user-123", gomock.Any(), domain.MovementTopUp, domain.NewMoney(5000, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockAuditService.EXPECT().
+			Append(ctx, gomock.Any(), operator, domain.AuditTopUpWallet, "user-123", "", gomock.Any()).
+			Return(domain.ErrRecordAudit).Times(1)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrRecordAudit, err)
+	})
+
+	t.Run("idempotent replay", func(t *testing.T) {
+		existing := &domain.TopUp{
+			ID:             "topup-1",
+			UserID:         request.UserID,
+			Amount:         request.Amount,
+			Currency:       request.Currency,
+			IdempotencyKey: request.IdempotencyKey,
+		}
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(existing, nil).Times(1)
+		mockTopUpRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockBalanceService.EXPECT().Credit(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		topUp, replayed, err := service.TopUp(ctx, operator, request)
+		assert.NoError(t, err)
+		assert.True(t, replayed)
+		assert.Equal(t, existing, topUp)
+	})
+
+	t.Run("idempotency key reused with a different amount", func(t *testing.T) {
+		existing := &domain.TopUp{
+			ID:     "topup-1",
+			UserID: request.UserID,
+			Amount: request.Amount + 1,
+		}
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(existing, nil).Times(1)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrIdempotencyKeyReused, err)
+	})
+
+	t.Run("error checking idempotency", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).
+			Return(nil, errors.New("database error")).Times(1)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrCheckIdempotency, err)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).
+			Return(nil, domain.ErrWalletNotFound).Times(1)
+		mockTopUpRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrWalletNotFound, err)
+	})
+
+	t.Run("currency not held", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).
+			Return(nil, domain.ErrCurrencyNotHeld).Times(1)
+		mockTopUpRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrCurrencyNotHeld, err)
+	})
+
+	t.Run("closed wallet", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).
+			Return(&domain.Balance{UserID: request.UserID, Status: domain.WalletClosed}, nil).Times(1)
+		mockTopUpRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrWalletClosed, err)
+	})
+
+	t.Run("concurrent request with the same key", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).Return(activeWallet, nil).Times(1)
+		mockTopUpRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(domain.ErrIdempotencyKeyConflict).Times(1)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrIdempotencyKeyConflict, err)
+	})
+
+	t.Run("error creating top-up", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).Return(activeWallet, nil).Times(1)
+		mockTopUpRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrCreateTopUp, err)
+	})
+
+	t.Run("error crediting balance", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).Return(activeWallet, nil).Times(1)
+		mockTopUpRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(ctx, gomock.Any(), "user-123", gomock.Any(), domain.MovementTopUp, domain.NewMoney(5000, domain.CurrencyUSD)).
+			Return(domain.ErrCreditBalance).Times(1)
+		mockOutboxRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrCreditBalance, err)
+	})
+
+	t.Run("error creating outbox message", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockTopUpRepo.EXPECT().CheckIdempotency(ctx, gomock.Any(), request.IdempotencyKey).Return(nil, nil).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), request.UserID, request.Currency).Return(activeWallet, nil).Times(1)
+		mockTopUpRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(ctx, gomock.Any(), "user-123", gomock.Any(), domain.MovementTopUp, domain.NewMoney(5000, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockOutboxRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+
+		_, _, err := service.TopUp(ctx, operator, request)
+		assert.Equal(t, domain.ErrCreateOutboxMessage, err)
+	})
+}
+
+func TestService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+
+	service := &Service{
+		logger:      slog.Default(),
+		db:          mockDB,
+		balanceRepo: mockBalanceRepo,
+	}
+
+	ctx := context.Background()
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+
+	t.Run("wallet created", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(nil, domain.ErrWalletNotFound).Times(1)
+		mockBalanceRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, balance domain.Balance) error {
+				assert.Equal(t, "user-123", balance.UserID)
+				assert.Equal(t, domain.CurrencyUSD, balance.Currency)
+				assert.Equal(t, domain.WalletActive, balance.Status)
+				assert.Zero(t, balance.Available)
+				return nil
+			}).Times(1)
+
+		wallet, err := service.Create(ctx, "user-123", domain.CurrencyUSD)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.WalletActive, wallet.Status)
+		assert.Equal(t, domain.CurrencyUSD, wallet.Currency)
+	})
+
+	t.Run("new currency of a frozen user starts frozen", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").
+			Return([]domain.Balance{{UserID: "user-123", Currency: domain.CurrencyARS, Status: domain.WalletFrozen}}, nil).Times(1)
+		mockBalanceRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, balance domain.Balance) error {
+				assert.Equal(t, domain.WalletFrozen, balance.Status)
+				return nil
+			}).Times(1)
+
+		wallet, err := service.Create(ctx, "user-123", domain.CurrencyUSD)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.WalletFrozen, wallet.Status)
+	})
+
+	t.Run("closed user cannot open a wallet", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").
+			Return([]domain.Balance{{UserID: "user-123", Currency: domain.CurrencyARS, Status: domain.WalletClosed}}, nil).Times(1)
+		mockBalanceRepo.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		wallet, err := service.Create(ctx, "user-123", domain.CurrencyUSD)
+		assert.Nil(t, wallet)
+		assert.Equal(t, domain.ErrWalletClosed, err)
+	})
+
+	t.Run("wallet already exists", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(nil, domain.ErrWalletNotFound).Times(1)
+		mockBalanceRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(domain.ErrWalletAlreadyExists).Times(1)
+
+		wallet, err := service.Create(ctx, "user-123", domain.CurrencyUSD)
+		assert.Nil(t, wallet)
+		assert.Equal(t, domain.ErrWalletAlreadyExists, err)
+	})
+
+	t.Run("error creating wallet", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(nil, domain.ErrWalletNotFound).Times(1)
+		mockBalanceRepo.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+
+		_, err := service.Create(ctx, "user-123", domain.CurrencyUSD)
+		assert.Equal(t, domain.ErrCreateWallet, err)
+	})
+}
+
+func TestService_ChangeStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockAuditService := mocks.NewMockAuditService(ctrl)
+
+	service := &Service{
+		logger:       slog.Default(),
+		db:           mockDB,
+		balanceRepo:  mockBalanceRepo,
+		auditService: mockAuditService,
+	}
+
+	ctx := context.Background()
+	operator := domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleOps}}
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	// the user holds two currencies; reserved funds are only ever in USD
+	wallets := func(status domain.WalletStatus, reserved int64) []domain.Balance {
+		return []domain.Balance{
+			{UserID: "user-123", Currency: domain.CurrencyARS, Available: 100, Status: status},
+			{UserID: "user-123", Currency: domain.CurrencyUSD, Available: 100, Reserved: reserved, Status: status},
+		}
+	}
+	assertStatus := func(t *testing.T, status domain.WalletStatus, result []domain.Balance) {
+		assert.Len(t, result, 2)
+		for _, wallet := range result {
+			assert.Equal(t, status, wallet.Status)
+		}
+	}
+
+	t.Run("freeze active wallet", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(wallets(domain.WalletActive, 0), nil).Times(1)
+		mockBalanceRepo.EXPECT().UpdateStatus(ctx, gomock.Any(), "user-123", domain.WalletFrozen).Return(nil).Times(1)
+		mockAuditService.EXPECT().Append(ctx, gomock.Any(), operator, domain.AuditFreezeWallet, "user-123", "", gomock.Any()).
+			Return(nil).Times(1)
+
+		result, err := service.Freeze(ctx, operator, "user-123")
+		assert.NoError(t, err)
+		assertStatus(t, domain.WalletFrozen, result)
+	})
+
+	t.Run("unfreeze frozen wallet", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(wallets(domain.WalletFrozen, 0), nil).Times(1)
+		mockBalanceRepo.EXPECT().UpdateStatus(ctx, gomock.Any(), "user-123", domain.WalletActive).Return(nil).Times(1)
+		mockAuditService.EXPECT().Append(ctx, gomock.Any(), operator, domain.AuditUnfreezeWallet, "user-123", "", gomock.Any()).
+			Return(nil).Times(1)
+
+		result, err := service.Unfreeze(ctx, operator, "user-123")
+		assert.NoError(t, err)
+		assertStatus(t, domain.WalletActive, result)
+	})
+
+	t.Run("freeze already frozen wallet is a no-op", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(wallets(domain.WalletFrozen, 0), nil).Times(1)
+		mockBalanceRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		mockAuditService.EXPECT().Append(ctx, gomock.Any(), operator, domain.AuditFreezeWallet, "user-123", "", gomock.Any()).
+			Return(nil).Times(1)
+
+		result, err := service.Freeze(ctx, operator, "user-123")
+		assert.NoError(t, err)
+		assertStatus(t, domain.WalletFrozen, result)
+	})
+
+	t.Run("close wallet without reserved funds", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(wallets(domain.WalletFrozen, 0), nil).Times(1)
+		mockBalanceRepo.EXPECT().UpdateStatus(ctx, gomock.Any(), "user-123", domain.WalletClosed).Return(nil).Times(1)
+		mockAuditService.EXPECT().Append(ctx, gomock.Any(), operator, domain.AuditCloseWallet, "user-123", "", gomock.Any()).
+			Return(nil).Times(1)
+
+		result, err := service.Close(ctx, operator, "user-123")
+		assert.NoError(t, err)
+		assertStatus(t, domain.WalletClosed, result)
+	})
+
+	t.Run("close wallet with reserved funds", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(wallets(domain.WalletActive, 50), nil).Times(1)
+		mockBalanceRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := service.Close(ctx, operator, "user-123")
+		assert.Equal(t, domain.ErrWalletHasReservedFunds, err)
+	})
+
+	t.Run("unfreeze closed wallet", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(wallets(domain.WalletClosed, 0), nil).Times(1)
+
+		_, err := service.Unfreeze(ctx, operator, "user-123")
+		assert.ErrorIs(t, err, domain.ErrInvalidWalletTransition)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(nil, domain.ErrWalletNotFound).Times(1)
+
+		_, err := service.Freeze(ctx, operator, "user-123")
+		assert.Equal(t, domain.ErrWalletNotFound, err)
+	})
+
+	t.Run("error updating wallet status", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(wallets(domain.WalletActive, 0), nil).Times(1)
+		mockBalanceRepo.EXPECT().UpdateStatus(ctx, gomock.Any(), "user-123", domain.WalletFrozen).
+			Return(errors.New("database error")).Times(1)
+
+		_, err := service.Freeze(ctx, operator, "user-123")
+		assert.Equal(t, domain.ErrUpdateWallet, err)
+	})
+
+	t.Run("error recording the audit entry", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindAllByUserID(ctx, gomock.Any(), "user-123").Return(wallets(domain.WalletActive, 0), nil).Times(1)
+		mockBalanceRepo.EXPECT().UpdateStatus(ctx, gomock.Any(), "user-123", domain.WalletFrozen).Return(nil).Times(1)
+		mockAuditService.EXPECT().Append(ctx, gomock.Any(), operator, domain.AuditFreezeWallet, "user-123", "", gomock.Any()).
+			Return(domain.ErrRecordAudit).Times(1)
+
+		_, err := service.Freeze(ctx, operator, "user-123")
+		assert.Equal(t, domain.ErrRecordAudit, err)
+	})
+}
+
+func TestService_Adjust(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDB := mocks.NewMockDatabase(ctrl)
+	mockBalanceRepo := mocks.NewMockBalanceRepository(ctrl)
+	mockBalanceService := mocks.NewMockBalanceService(ctrl)
+	mockAuditService := mocks.NewMockAuditService(ctrl)
+
+	service := &Service{
+		logger:         slog.Default(),
+		db:             mockDB,
+		balanceRepo:    mockBalanceRepo,
+		balanceService: mockBalanceService,
+		auditService:   mockAuditService,
+	}
+
+	ctx := context.Background()
+	operator := domain.Operator{ID: "operator-1", Roles: []domain.Role{domain.RoleFinance}}
+	withTx := func(ctx context.Context, fn func(*pgx.Tx) error) error {
+		dummyTx := new(pgx.Tx)
+		return fn(dummyTx)
+	}
+	request := func(amount int64) domain.BalanceAdjustmentRequest {
+		return domain.BalanceAdjustmentRequest{
+			UserID:   "user-123",
+			Amount:   amount,
+			Currency: domain.CurrencyUSD,
+			Reason:   "duplicated charge",
+		}
+	}
+	wallet := func(status domain.WalletStatus) *domain.Balance {
+		return &domain.Balance{UserID: "user-123", Currency: domain.CurrencyUSD, Available: 1000, Status: status}
+	}
+
+	t.Run("credit adjustment", func(t *testing.T) {
+		var adjustmentID string
+
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), "user-123", domain.CurrencyUSD).
+			Return(wallet(domain.WalletFrozen), nil).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(ctx, gomock.Any(), "user-123", gomock.Any(), domain.MovementAdjustmentCredit, domain.NewMoney(250, domain.CurrencyUSD)).
+			DoAndReturn(func(ctx context.Context, tx pgx.Tx, userID, referenceID, movement string, amount domain.Money) error {
+				adjustmentID = referenceID
+				return nil
+			}).Times(1)
+		mockAuditService.EXPECT().
+			Append(ctx, gomock.Any(), operator, domain.AuditAdjustBalance, "user-123", "duplicated charge", gomock.Any()).
+			Return(nil).Times(1)
+
+		adjustment, err := service.Adjust(ctx, operator, request(250))
+		assert.NoError(t, err)
+		assert.Equal(t, adjustmentID, adjustment.ID)
+		assert.Equal(t, int64(1250), adjustment.Balance.Available)
+	})
+
+	t.Run("debit adjustment", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), "user-123", domain.CurrencyUSD).
+			Return(wallet(domain.WalletActive), nil).Times(1)
+		mockBalanceService.EXPECT().
+			Debit(ctx, gomock.Any(), "user-123", gomock.Any(), domain.MovementAdjustmentDebit, domain.NewMoney(250, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockAuditService.EXPECT().
+			Append(ctx, gomock.Any(), operator, domain.AuditAdjustBalance, "user-123", "duplicated charge", gomock.Any()).
+			Return(nil).Times(1)
+
+		adjustment, err := service.Adjust(ctx, operator, request(-250))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-250), adjustment.Amount)
+		assert.Equal(t, int64(750), adjustment.Balance.Available)
+	})
+
+	t.Run("debit over the available balance", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), "user-123", domain.CurrencyUSD).
+			Return(wallet(domain.WalletActive), nil).Times(1)
+		mockBalanceService.EXPECT().
+			Debit(ctx, gomock.Any(), "user-123", gomock.Any(), domain.MovementAdjustmentDebit, domain.NewMoney(2000, domain.CurrencyUSD)).
+			Return(domain.ErrInsufficientFunds).Times(1)
+		mockAuditService.EXPECT().Append(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := service.Adjust(ctx, operator, request(-2000))
+		assert.Equal(t, domain.ErrInsufficientFunds, err)
+	})
+
+	t.Run("closed wallet", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), "user-123", domain.CurrencyUSD).
+			Return(wallet(domain.WalletClosed), nil).Times(1)
+
+		_, err := service.Adjust(ctx, operator, request(250))
+		assert.Equal(t, domain.ErrWalletClosed, err)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), "user-123", domain.CurrencyUSD).
+			Return(nil, domain.ErrWalletNotFound).Times(1)
+
+		_, err := service.Adjust(ctx, operator, request(250))
+		assert.Equal(t, domain.ErrWalletNotFound, err)
+	})
+
+	t.Run("error recording the audit entry", func(t *testing.T) {
+		mockDB.EXPECT().WithTx(ctx, gomock.Any()).DoAndReturn(withTx).Times(1)
+		mockBalanceRepo.EXPECT().FindByUserID(ctx, gomock.Any(), "user-123", domain.CurrencyUSD).
+			Return(wallet(domain.WalletActive), nil).Times(1)
+		mockBalanceService.EXPECT().
+			Credit(ctx, gomock.Any(), "user-123", gomock.Any(), domain.MovementAdjustmentCredit, domain.NewMoney(250, domain.CurrencyUSD)).
+			Return(nil).Times(1)
+		mockAuditService.EXPECT().
+			Append(ctx, gomock.Any(), operator, domain.AuditAdjustBalance, "user-123", "duplicated charge", gomock.Any()).
+			Return(domain.ErrRecordAudit).Times(1)
+
+		_, err := service.Adjust(ctx, operator, request(250))
+		assert.Equal(t, domain.ErrRecordAudit, err)
+	})
+}
@@ -0,0 +1,14 @@
+ALTER TABLE payments
+    ADD COLUMN refunded_amount BIGINT NOT NULL DEFAULT 0,
+    ADD CONSTRAINT payments_refunded_within_amount CHECK (refunded_amount >= 0 AND refunded_amount <= amount);
+
+CREATE TABLE refunds (
+                          id UUID PRIMARY KEY,
+                          payment_id UUID NOT NULL REFERENCES payments (id),
+                          user_id UUID NOT NULL,
+                          amount BIGINT NOT NULL CHECK (amount > 0),
+                          idempotency_key VARCHAR(255) UNIQUE NOT NULL,
+                          created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX refunds_payment_idx ON refunds (payment_id);
@@ -0,0 +1,2 @@
+-- the seeded wallets are kept, their opening postings cannot be deleted
+DROP TABLE IF EXISTS topups;
@@ -0,0 +1,28 @@
+CREATE TABLE topups (
+                          id UUID PRIMARY KEY,
+                          user_id UUID NOT NULL REFERENCES balance (user_id),
+                          amount BIGINT NOT NULL CHECK (amount > 0),
+                          idempotency_key VARCHAR(255) UNIQUE NOT NULL,
+                          created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX topups_user_idx ON topups (user_id, created_at);
+
+-- the seed of 1_initial_schema only inserted the first wallet, the other rows
+-- were standalone VALUES statements; create the missing ones with their
+-- opening postings
+WITH seeded AS (
+    INSERT INTO balance (user_id, available_balance, reserved_balance, updated_at)
+    VALUES ('550e8400-e29b-41d4-a716-446655440001', 10000, 0, NOW()),
+           ('550e8400-e29b-41d4-a716-446655440002', 10000, 0, NOW()),
+           ('550e8400-e29b-41d4-a716-446655440003', 10000, 0, NOW())
+    ON CONFLICT (user_id) DO NOTHING
+    RETURNING user_id, available_balance
+), opening AS (
+    SELECT gen_random_uuid() AS transaction_id, user_id, available_balance AS amount
+    FROM seeded
+)
+INSERT INTO ledger_entries (transaction_id, movement, user_id, account, direction, amount, reference_id)
+SELECT transaction_id, 'OPENING', user_id, 'funding', 'DEBIT', amount, 'opening' FROM opening
+UNION ALL
+SELECT transaction_id, 'OPENING', user_id, 'available', 'CREDIT', amount, 'opening' FROM opening;
@@ -0,0 +1,3 @@
+ALTER TABLE balance
+    DROP CONSTRAINT IF EXISTS balance_valid_status,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,3 @@
+ALTER TABLE balance
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE',
+    ADD CONSTRAINT balance_valid_status CHECK (status IN ('ACTIVE', 'FROZEN', 'CLOSED'));
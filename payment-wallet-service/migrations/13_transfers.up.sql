@@ -0,0 +1,12 @@
+CREATE TABLE transfers (
+                          id UUID PRIMARY KEY,
+                          from_user_id UUID NOT NULL REFERENCES balance (user_id),
+                          to_user_id UUID NOT NULL REFERENCES balance (user_id),
+                          amount BIGINT NOT NULL CHECK (amount > 0),
+                          idempotency_key VARCHAR(255) UNIQUE NOT NULL,
+                          created_at TIMESTAMP DEFAULT NOW(),
+                          CONSTRAINT transfers_distinct_wallets CHECK (from_user_id <> to_user_id)
+);
+
+CREATE INDEX transfers_from_user_idx ON transfers (from_user_id, created_at);
+CREATE INDEX transfers_to_user_idx ON transfers (to_user_id, created_at);
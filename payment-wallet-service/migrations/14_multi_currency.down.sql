@@ -0,0 +1,30 @@
+DROP INDEX ledger_entries_user_idx;
+CREATE INDEX ledger_entries_user_idx ON ledger_entries (user_id, id);
+
+ALTER TABLE topups DROP CONSTRAINT topups_wallet_fkey;
+ALTER TABLE transfers
+    DROP CONSTRAINT transfers_from_wallet_fkey,
+    DROP CONSTRAINT transfers_to_wallet_fkey;
+
+-- only the ARS wallets survive; ledger entries are immutable and keep the
+-- postings of the other currencies
+DELETE FROM topups WHERE currency <> 'ARS';
+DELETE FROM transfers WHERE currency <> 'ARS';
+DELETE FROM balance WHERE currency <> 'ARS';
+
+ALTER TABLE balance
+    DROP CONSTRAINT balance_pkey,
+    ADD PRIMARY KEY (user_id);
+
+ALTER TABLE topups
+    ADD CONSTRAINT topups_user_id_fkey FOREIGN KEY (user_id) REFERENCES balance (user_id);
+ALTER TABLE transfers
+    ADD CONSTRAINT transfers_from_user_id_fkey FOREIGN KEY (from_user_id) REFERENCES balance (user_id),
+    ADD CONSTRAINT transfers_to_user_id_fkey FOREIGN KEY (to_user_id) REFERENCES balance (user_id);
+
+ALTER TABLE ledger_entries DROP COLUMN currency;
+ALTER TABLE transfers DROP COLUMN currency;
+ALTER TABLE topups DROP COLUMN currency;
+ALTER TABLE refunds DROP COLUMN currency;
+ALTER TABLE payments DROP COLUMN currency;
+ALTER TABLE balance DROP COLUMN currency;
@@ -0,0 +1,26 @@
+-- every amount was implicitly ARS until now
+ALTER TABLE balance ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'ARS';
+ALTER TABLE payments ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'ARS';
+ALTER TABLE refunds ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'ARS';
+ALTER TABLE topups ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'ARS';
+ALTER TABLE transfers ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'ARS';
+ALTER TABLE ledger_entries ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'ARS';
+
+-- a user holds one wallet per currency
+ALTER TABLE topups DROP CONSTRAINT topups_user_id_fkey;
+ALTER TABLE transfers
+    DROP CONSTRAINT transfers_from_user_id_fkey,
+    DROP CONSTRAINT transfers_to_user_id_fkey;
+
+ALTER TABLE balance
+    DROP CONSTRAINT balance_pkey,
+    ADD PRIMARY KEY (user_id, currency);
+
+ALTER TABLE topups
+    ADD CONSTRAINT topups_wallet_fkey FOREIGN KEY (user_id, currency) REFERENCES balance (user_id, currency);
+ALTER TABLE transfers
+    ADD CONSTRAINT transfers_from_wallet_fkey FOREIGN KEY (from_user_id, currency) REFERENCES balance (user_id, currency),
+    ADD CONSTRAINT transfers_to_wallet_fkey FOREIGN KEY (to_user_id, currency) REFERENCES balance (user_id, currency);
+
+DROP INDEX ledger_entries_user_idx;
+CREATE INDEX ledger_entries_user_idx ON ledger_entries (user_id, currency, id);
@@ -0,0 +1,5 @@
+ALTER TABLE payments
+    DROP COLUMN fx_quote_id,
+    DROP COLUMN fx_rate,
+    DROP COLUMN funding_currency,
+    DROP COLUMN funding_amount;
@@ -0,0 +1,13 @@
+-- the wallet a payment is funded from, and the rate locked when it was
+-- converted from the currency of the invoice
+ALTER TABLE payments
+    ADD COLUMN funding_amount BIGINT,
+    ADD COLUMN funding_currency CHAR(3),
+    ADD COLUMN fx_rate NUMERIC(20, 10),
+    ADD COLUMN fx_quote_id UUID;
+
+UPDATE payments SET funding_amount = amount, funding_currency = currency;
+
+ALTER TABLE payments
+    ALTER COLUMN funding_amount SET NOT NULL,
+    ALTER COLUMN funding_currency SET NOT NULL;
@@ -0,0 +1,13 @@
+-- per wallet limits configuration; wallets without a row use the default tier
+CREATE TABLE user_limits (
+                            user_id UUID NOT NULL,
+                            currency CHAR(3) NOT NULL,
+                            tier VARCHAR(50),
+                            per_transaction BIGINT CHECK (per_transaction >= 0),
+                            daily_amount BIGINT CHECK (daily_amount >= 0),
+                            monthly_amount BIGINT CHECK (monthly_amount >= 0),
+                            hourly_count BIGINT CHECK (hourly_count >= 0),
+                            updated_at TIMESTAMP DEFAULT NOW(),
+                            PRIMARY KEY (user_id, currency),
+                            CONSTRAINT user_limits_wallet_fkey FOREIGN KEY (user_id, currency) REFERENCES balance (user_id, currency)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS payments_pending_updated_idx;
+
+CREATE INDEX payments_pending_created_idx ON payments (created_at) WHERE status = 'PENDING';
@@ -0,0 +1,5 @@
+-- payments approved after a review start pending when they are released, so
+-- the sweeper looks for expired ones by their last update
+DROP INDEX IF EXISTS payments_pending_created_idx;
+
+CREATE INDEX payments_pending_updated_idx ON payments (updated_at) WHERE status = 'PENDING';
@@ -0,0 +1,21 @@
+-- payment entities users can pay to; the id is the service_id of the payments
+CREATE TABLE billers (
+                          id VARCHAR(100) PRIMARY KEY,
+                          name VARCHAR(255) NOT NULL,
+                          currency CHAR(3) NOT NULL,
+                          min_amount BIGINT NOT NULL DEFAULT 0 CHECK (min_amount >= 0),
+                          max_amount BIGINT NOT NULL DEFAULT 0 CHECK (max_amount >= 0),
+                          enabled BOOLEAN NOT NULL DEFAULT TRUE,
+                          client_number_pattern VARCHAR(255),
+                          check_digit VARCHAR(20) CHECK (check_digit IN ('LUHN')),
+                          created_at TIMESTAMP DEFAULT NOW(),
+                          updated_at TIMESTAMP DEFAULT NOW(),
+                          CONSTRAINT billers_valid_range CHECK (max_amount = 0 OR max_amount >= min_amount)
+);
+
+INSERT INTO billers (id, name, currency, min_amount, max_amount, client_number_pattern, check_digit)
+VALUES
+    ('a1b2c3d4-e5f6-7890-abcd-1234567890ef', 'Electricidad del Norte', 'ARS', 100, 50000000, '[0-9]{11}', 'LUHN'),
+    ('b7e1c2d3-4f5a-4b6c-9d7e-8f9a0b1c2d3e', 'Aguas del Sur', 'ARS', 100, 20000000, '[0-9]{6,10}', NULL),
+    ('c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e6f', 'Telefonía Federal', 'ARS', 500, 0, '[A-Z]{2}[0-9]{8}', NULL),
+    ('d9e8f7a6-b5c4-4d3e-9f2a-1b0c9d8e7f6a', 'Cloud Hosting Inc.', 'USD', 100, 500000, NULL, NULL);
@@ -0,0 +1,29 @@
+-- actions of operators on the admin API
+CREATE TABLE admin_audit (
+                          id UUID PRIMARY KEY,
+                          operator_id VARCHAR(255) NOT NULL,
+                          operator_roles TEXT[] NOT NULL,
+                          action VARCHAR(50) NOT NULL,
+                          target_id VARCHAR(255) NOT NULL,
+                          reason TEXT,
+                          details JSONB,
+                          created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX admin_audit_target_idx ON admin_audit (target_id, created_at);
+CREATE INDEX admin_audit_operator_idx ON admin_audit (operator_id, created_at);
+
+-- the audit trail is append-only
+CREATE FUNCTION admin_audit_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'admin audit entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER admin_audit_no_update
+    BEFORE UPDATE OR DELETE ON admin_audit
+    FOR EACH ROW EXECUTE FUNCTION admin_audit_immutable();
+
+CREATE TRIGGER admin_audit_no_truncate
+    BEFORE TRUNCATE ON admin_audit
+    FOR EACH STATEMENT EXECUTE FUNCTION admin_audit_immutable();
@@ -0,0 +1,2 @@
+ALTER TABLE outbox
+    ADD COLUMN request_id VARCHAR(128);
@@ -0,0 +1,5 @@
+-- payment times are compared against instants built in Go, such as the limit
+-- windows, so they keep their time zone; earlier rows were written in UTC
+ALTER TABLE payments
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
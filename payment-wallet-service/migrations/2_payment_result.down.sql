@@ -0,0 +1,3 @@
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS processor_reference,
+    DROP COLUMN IF EXISTS failure_reason;
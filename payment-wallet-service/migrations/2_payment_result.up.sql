@@ -0,0 +1,3 @@
+ALTER TABLE payments
+    ADD COLUMN processor_reference VARCHAR(100),
+    ADD COLUMN failure_reason VARCHAR(255);
@@ -0,0 +1,4 @@
+ALTER TABLE balance
+    DROP CONSTRAINT IF EXISTS balance_available_non_negative,
+    DROP CONSTRAINT IF EXISTS balance_reserved_non_negative,
+    ALTER COLUMN reserved_balance DROP NOT NULL;
@@ -0,0 +1,6 @@
+UPDATE balance SET reserved_balance = 0 WHERE reserved_balance IS NULL;
+
+ALTER TABLE balance
+    ALTER COLUMN reserved_balance SET NOT NULL,
+    ADD CONSTRAINT balance_available_non_negative CHECK (available_balance >= 0),
+    ADD CONSTRAINT balance_reserved_non_negative CHECK (reserved_balance >= 0);
@@ -0,0 +1,13 @@
+CREATE TABLE outbox (
+                          id UUID PRIMARY KEY,
+                          aggregate_id VARCHAR(100) NOT NULL,
+                          event_type VARCHAR(100) NOT NULL,
+                          payload JSONB NOT NULL,
+                          attempts INT NOT NULL DEFAULT 0,
+                          last_error TEXT,
+                          next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+                          created_at TIMESTAMP DEFAULT NOW(),
+                          sent_at TIMESTAMP
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (next_attempt_at) WHERE sent_at IS NULL;
@@ -0,0 +1,57 @@
+CREATE TABLE ledger_entries (
+                          id BIGSERIAL PRIMARY KEY,
+                          transaction_id UUID NOT NULL,
+                          movement VARCHAR(20) NOT NULL,
+                          user_id UUID NOT NULL,
+                          account VARCHAR(20) NOT NULL,
+                          direction VARCHAR(6) NOT NULL CHECK (direction IN ('DEBIT', 'CREDIT')),
+                          amount BIGINT NOT NULL CHECK (amount > 0),
+                          reference_id VARCHAR(100) NOT NULL,
+                          created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX ledger_entries_user_idx ON ledger_entries (user_id, id);
+CREATE INDEX ledger_entries_transaction_idx ON ledger_entries (transaction_id);
+
+-- postings are append-only
+CREATE FUNCTION ledger_entries_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'ledger entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_entries_no_update
+    BEFORE UPDATE OR DELETE ON ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_entries_immutable();
+
+-- every ledger transaction must be balanced when the database transaction commits
+CREATE FUNCTION ledger_transaction_balanced() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(CASE WHEN direction = 'DEBIT' THEN amount ELSE -amount END)
+        FROM ledger_entries
+        WHERE transaction_id = NEW.transaction_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % is not balanced', NEW.transaction_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_transaction_balanced();
+
+-- opening postings so the ledger matches the balances that existed before it
+WITH opening AS (
+    SELECT gen_random_uuid() AS transaction_id, user_id, available_balance AS amount, 'available' AS account
+    FROM balance
+    WHERE available_balance > 0
+    UNION ALL
+    SELECT gen_random_uuid(), user_id, reserved_balance, 'reserved'
+    FROM balance
+    WHERE reserved_balance > 0
+)
+INSERT INTO ledger_entries (transaction_id, movement, user_id, account, direction, amount, reference_id)
+SELECT transaction_id, 'OPENING', user_id, 'funding', 'DEBIT', amount, 'opening' FROM opening
+UNION ALL
+SELECT transaction_id, 'OPENING', user_id, account, 'CREDIT', amount, 'opening' FROM opening;
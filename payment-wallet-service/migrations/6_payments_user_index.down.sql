@@ -0,0 +1 @@
+DROP INDEX IF EXISTS payments_user_created_idx;
@@ -0,0 +1 @@
+CREATE INDEX payments_user_created_idx ON payments (user_id, created_at DESC, id DESC);
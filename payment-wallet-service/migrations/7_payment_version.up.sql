@@ -0,0 +1,2 @@
+ALTER TABLE payments
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 0;
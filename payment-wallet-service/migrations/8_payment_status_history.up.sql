@@ -0,0 +1,15 @@
+CREATE TABLE payment_status_history (
+                          id BIGSERIAL PRIMARY KEY,
+                          payment_id UUID NOT NULL REFERENCES payments (id),
+                          from_status VARCHAR(20),
+                          to_status VARCHAR(20) NOT NULL,
+                          created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX payment_status_history_payment_idx ON payment_status_history (payment_id, id);
+
+-- payments created before the history existed start their timeline at the
+-- status they currently have
+INSERT INTO payment_status_history (payment_id, from_status, to_status, created_at)
+SELECT id, NULL, status, COALESCE(updated_at, created_at, NOW())
+FROM payments;
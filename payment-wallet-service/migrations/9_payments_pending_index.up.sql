@@ -0,0 +1 @@
+CREATE INDEX payments_pending_created_idx ON payments (created_at) WHERE status = 'PENDING';
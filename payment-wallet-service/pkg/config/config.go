@@ -5,25 +5,127 @@ import (
 
 	"os"
 	"path/filepath"
+	"time"
 )
 
 type Config struct {
-	AppID         string         `yaml:"app-id"`
-	Version       string         `yaml:"version"`
-	Env           string         `yaml:"env"`
-	Port          int            `yaml:"port"`
-	StorageConfig *StorageConfig `yaml:"storage"`
-	PubConfig     *PubConfig     `yaml:"pub"`
+	AppID           string         `yaml:"app-id"`
+	Version         string         `yaml:"version"`
+	Env             string         `yaml:"env"`
+	Port            int            `yaml:"port"`
+	TrustedProxies  []string       `yaml:"trusted-proxies"`
+	StorageConfig   *StorageConfig `yaml:"storage"`
+	PubConfig       *PubConfig     `yaml:"pub"`
+	SubConfig       *SubConfig     `yaml:"sub"`
+	OutboxConfig    *OutboxConfig  `yaml:"outbox"`
+	ExpiryConfig    *ExpiryConfig  `yaml:"expiry"`
+	FXConfig        *FXConfig      `yaml:"fx"`
+	LimitsConfig    *LimitsConfig  `yaml:"limits"`
+	RiskConfig      *RiskConfig    `yaml:"risk"`
+	TopUpConfig     *TopUpConfig   `yaml:"topups"`
+	AuthConfig      *AuthConfig    `yaml:"auth"`
+	AdminAuthConfig *AuthConfig    `yaml:"admin-auth"`
+	MetricsConfig   *MetricsConfig `yaml:"metrics"`
+	TracingConfig   *TracingConfig `yaml:"tracing"`
 }
 
 type StorageConfig struct {
 	Dsn string `yaml:"dsn"`
 }
 
+// PubConfig sets where outbox messages are published. Routing keys are set by
+// event type, and an event type without one is not published.
 type PubConfig struct {
-	RabbitURL  string `yaml:"rabbit-url"`
-	Exchange   string `yaml:"exchange"`
-	RoutingKey string `yaml:"routing-key"`
+	RabbitURL   string            `yaml:"rabbit-url"`
+	Exchange    string            `yaml:"exchange"`
+	RoutingKeys map[string]string `yaml:"routing-keys"`
+}
+
+type SubConfig struct {
+	Brokers []string `yaml:"brokers"`
+	Topic   string   `yaml:"topic"`
+	GroupID string   `yaml:"group-id"`
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration `yaml:"poll-interval"`
+	BatchSize    int           `yaml:"batch-size"`
+	BaseBackoff  time.Duration `yaml:"base-backoff"`
+	MaxBackoff   time.Duration `yaml:"max-backoff"`
+	ClaimLease   time.Duration `yaml:"claim-lease"`
+}
+
+type ExpiryConfig struct {
+	TTL          time.Duration `yaml:"ttl"`
+	PollInterval time.Duration `yaml:"poll-interval"`
+	BatchSize    int           `yaml:"batch-size"`
+}
+
+type FXConfig struct {
+	SpreadBps int64             `yaml:"spread-bps"`
+	Rounding  string            `yaml:"rounding"`
+	RatesFile string            `yaml:"rates-file"`
+	Rates     map[string]string `yaml:"rates"`
+}
+
+// LimitsConfig holds the spending limits of each tier by wallet currency.
+type LimitsConfig struct {
+	DefaultTier string                            `yaml:"default-tier"`
+	Tiers       map[string]map[string]LimitConfig `yaml:"tiers"`
+}
+
+type LimitConfig struct {
+	PerTransaction int64 `yaml:"per-transaction"`
+	DailyAmount    int64 `yaml:"daily-amount"`
+	MonthlyAmount  int64 `yaml:"monthly-amount"`
+	HourlyCount    int64 `yaml:"hourly-count"`
+}
+
+// RiskConfig tunes the rules of the default risk evaluator. Large amounts are
+// in the minor unit of each wallet currency.
+type RiskConfig struct {
+	LargeAmounts map[string]int64 `yaml:"large-amounts"`
+	BurstWindow  time.Duration    `yaml:"burst-window"`
+	ReviewBurst  int              `yaml:"review-burst"`
+	DenyBurst    int              `yaml:"deny-burst"`
+}
+
+// TopUpConfig caps a single top-up by wallet currency, in its minor unit.
+type TopUpConfig struct {
+	MaxAmounts map[string]int64 `yaml:"max-amounts"`
+}
+
+// AuthConfig sets how bearer tokens are verified. Static keys, PEM encoded
+// public keys by key ID, take precedence over the JWKS file and URL and are
+// only accepted with env local.
+type AuthConfig struct {
+	Issuer          string            `yaml:"issuer"`
+	Audience        string            `yaml:"audience"`
+	JWKSFile        string            `yaml:"jwks-file"`
+	JWKSURL         string            `yaml:"jwks-url"`
+	StaticKeys      map[string]string `yaml:"static-keys"`
+	RefreshInterval time.Duration     `yaml:"refresh-interval"`
+	Leeway          time.Duration     `yaml:"leeway"`
+}
+
+type MetricsConfig struct {
+	Prometheus *PrometheusConfig `yaml:"prometheus"`
+}
+
+type PrometheusConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Port    int    `yaml:"port"`
+	Path    string `yaml:"path"`
+}
+
+// TracingConfig sets where spans are exported, either otlp or stdout. A sample
+// ratio of zero records every trace.
+type TracingConfig struct {
+	Enabled      bool    `yaml:"enabled"`
+	Exporter     string  `yaml:"exporter"`
+	OTLPEndpoint string  `yaml:"otlp-endpoint"`
+	Insecure     bool    `yaml:"insecure"`
+	SampleRatio  float64 `yaml:"sample-ratio"`
 }
 
 func Parse(path string, file string) (*Config, error) {
@@ -0,0 +1,43 @@
+package correlation
+
+import "context"
+
+type contextKey int
+
+const (
+	_requestIDKey contextKey = iota
+	_userIDKey
+	_paymentIDKey
+)
+
+// WithRequestID tags ctx with the ID of the request being served, which ties
+// together its logs and the events it publishes.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, _requestIDKey, requestID)
+}
+
+// RequestID returns the request ID of ctx, or an empty string when untagged.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(_requestIDKey).(string)
+	return requestID
+}
+
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, _userIDKey, userID)
+}
+
+// UserID returns the user ID of ctx, or an empty string when untagged.
+func UserID(ctx context.Context) string {
+	userID, _ := ctx.Value(_userIDKey).(string)
+	return userID
+}
+
+func WithPaymentID(ctx context.Context, paymentID string) context.Context {
+	return context.WithValue(ctx, _paymentIDKey, paymentID)
+}
+
+// PaymentID returns the payment ID of ctx, or an empty string when untagged.
+func PaymentID(ctx context.Context) string {
+	paymentID, _ := ctx.Value(_paymentIDKey).(string)
+	return paymentID
+}
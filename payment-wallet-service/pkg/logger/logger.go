@@ -1,16 +1,60 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/config"
+	"github.com/emiliocc5/payment-system/payment-wallet-service/pkg/correlation"
 )
 
 func New(cfg *config.Config) *slog.Logger {
-	logger := slog.Default()
+	logger := slog.New(NewContextHandler(slog.Default().Handler()))
 
-	logger.With(slog.String("service.name", cfg.AppID)).
+	return logger.With(slog.String("service.name", cfg.AppID)).
 		With(slog.String("service.version", cfg.Version))
+}
+
+// ContextHandler adds the correlation IDs tagged in the context, the request,
+// user and payment ones, to every record logged with a context. A record that
+// already carries one of those keys keeps its own value, as an explicit
+// attribute names the resource the call acts on.
+type ContextHandler struct {
+	handler slog.Handler
+}
+
+func NewContextHandler(handler slog.Handler) *ContextHandler {
+	return &ContextHandler{handler: handler}
+}
+
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *ContextHandler) Handle(ctx context.Context, record slog.Record) error {
+	ids := map[string]string{
+		"request_id": correlation.RequestID(ctx),
+		"user_id":    correlation.UserID(ctx),
+		"payment_id": correlation.PaymentID(ctx),
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		delete(ids, attr.Key)
+		return true
+	})
+
+	for _, key := range []string{"request_id", "user_id", "payment_id"} {
+		if value := ids[key]; value != "" {
+			record.AddAttrs(slog.String(key, value))
+		}
+	}
+
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{handler: h.handler.WithAttrs(attrs)}
+}
 
-	return logger
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{handler: h.handler.WithGroup(name)}
 }
@@ -2,15 +2,23 @@ package signals
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"net/http"
 	"sync/atomic"
 	"time"
 )
 
+// TracerProvider is flushed on shutdown so the spans still buffered are
+// exported.
+type TracerProvider interface {
+	Shutdown(ctx context.Context) error
+}
+
 type Shutdown struct {
 	logger                *slog.Logger
 	serverShutdownTimeout time.Duration
+	tracerProvider        TracerProvider
 }
 
 func NewShutdown(serverShutdownTimeout time.Duration, logger *slog.Logger) (*Shutdown, error) {
@@ -22,7 +30,13 @@ func NewShutdown(serverShutdownTimeout time.Duration, logger *slog.Logger) (*Shu
 	return srv, nil
 }
 
-func (s *Shutdown) Graceful(stopCh <-chan struct{}, httpServer *http.Server, healthy *int32) {
+// WithTracerProvider sets the tracer provider to flush on shutdown.
+func (s *Shutdown) WithTracerProvider(tracerProvider TracerProvider) *Shutdown {
+	s.tracerProvider = tracerProvider
+	return s
+}
+
+func (s *Shutdown) Graceful(stopCh <-chan struct{}, httpServer *http.Server, healthy *int32, workers ...io.Closer) {
 	ctx := context.Background()
 
 	// wait for SIGTERM or SIGINT
@@ -35,12 +49,28 @@ func (s *Shutdown) Graceful(stopCh <-chan struct{}, httpServer *http.Server, hea
 
 	s.logger.Info("shutting down", slog.Duration("timeout", s.serverShutdownTimeout))
 
-	// stop OpenTelemetry tracer provider
-
 	// determine if HTTP server was started
 	if httpServer != nil {
 		if err := httpServer.Shutdown(ctx); err != nil {
 			s.logger.Warn("HTTP server shutdown failed", slog.Any("error", err))
 		}
 	}
+
+	// stop background consumers and workers
+	for _, worker := range workers {
+		if worker == nil {
+			continue
+		}
+		if err := worker.Close(); err != nil {
+			s.logger.Warn("worker shutdown failed", slog.Any("error", err))
+		}
+	}
+
+	// stop OpenTelemetry tracer provider, last so the spans of the server and
+	// the workers are flushed
+	if s.tracerProvider != nil {
+		if err := s.tracerProvider.Shutdown(ctx); err != nil {
+			s.logger.Warn("OpenTelemetry tracer provider shutdown failed", slog.Any("error", err))
+		}
+	}
 }
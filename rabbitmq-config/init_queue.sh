@@ -28,23 +28,34 @@ if [ $? -ne 0 ]; then
   exit 1
 fi
 
-echo "Declarando queue..."
-rabbitmqadmin -u admin -p admin123 -V / declare queue name=payment_initiated durable=true 2> /tmp/rabbitmq_error.log
-if [ $? -ne 0 ]; then
-  echo "Error al declarar la queue. Verifica /tmp/rabbitmq_error.log"
-  cat /tmp/rabbitmq_error.log
-  exit 1
-fi
-
-echo "Haciendo binding..."
-rabbitmqadmin -u admin -p admin123 -V / declare binding source=payments_exchange destination=payment_initiated routing_key=payment_initiated 2> /tmp/rabbitmq_error.log
-if [ $? -ne 0 ]; then
-  echo "Error al declarar el binding. Verifica /tmp/rabbitmq_error.log"
-  cat /tmp/rabbitmq_error.log
-  exit 1
-fi
-
-echo "Verificando que la queue existe..."
-rabbitmqadmin -u admin -p admin123 -V / list queues | grep payment_initiated
+# declare_queue declara una queue durable y la vincula al exchange con una
+# routing key de su mismo nombre
+declare_queue() {
+  echo "Declarando queue $1..."
+  rabbitmqadmin -u admin -p admin123 -V / declare queue name=$1 durable=true 2> /tmp/rabbitmq_error.log
+  if [ $? -ne 0 ]; then
+    echo "Error al declarar la queue $1. Verifica /tmp/rabbitmq_error.log"
+    cat /tmp/rabbitmq_error.log
+    exit 1
+  fi
+
+  echo "Haciendo binding de $1..."
+  rabbitmqadmin -u admin -p admin123 -V / declare binding source=payments_exchange destination=$1 routing_key=$1 2> /tmp/rabbitmq_error.log
+  if [ $? -ne 0 ]; then
+    echo "Error al declarar el binding de $1. Verifica /tmp/rabbitmq_error.log"
+    cat /tmp/rabbitmq_error.log
+    exit 1
+  fi
+
+  echo "Verificando que la queue $1 existe..."
+  rabbitmqadmin -u admin -p admin123 -V / list queues | grep $1
+}
+
+# PaymentInitiated y PaymentCancelled van a la queue que consume el procesador
+declare_queue payment_initiated
+declare_queue wallet_credited
+declare_queue transfer_completed
+declare_queue refund_requested
+declare_queue payment_expired
 
 echo "Inicialización completada ✅"
\ No newline at end of file